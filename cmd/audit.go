@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/mcli/cmd/audit"
+	"github.com/giantswarm/mcli/pkg/github"
+)
+
+// auditCmd represents the audit command
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Audits the configuration of Management Clusters",
+}
+
+// auditExpiryCmd represents the audit expiry command
+var auditExpiryCmd = &cobra.Command{
+	Use:   "expiry",
+	Short: "Reports the expiry of credentials stored in the CMC repository",
+	Long: `Reports when credentials stored in the CMC repository entry of a Management Cluster expire.
+Certificates are inspected directly. For other credentials, the expiry date recorded at push time
+via --credential-expiry is used. The command exits with a non-zero exit code if a credential
+expires within the threshold. For example:
+
+mcli audit expiry --cluster=gigmac
+
+mcli audit expiry --all --threshold=60 --sort-by=cluster`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		defaultAudit()
+		err := validateAudit(cmd, args)
+		if err != nil {
+			return err
+		}
+		ctx := context.Background()
+		client := github.New(github.Config{
+			Token: githubToken,
+		})
+		c := audit.ExpiryConfig{
+			Cluster:       cluster,
+			All:           auditAll,
+			Github:        client,
+			CMCRepository: cmcRepository,
+			CMCBranch:     cmcBranch,
+			SortBy:        auditSortBy,
+		}
+		expiry, err := c.Run(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to audit credential expiry.\n%w", err)
+		}
+		err = audit.PrintExpiry(expiry)
+		if err != nil {
+			return err
+		}
+		if audit.HasFailed(expiry) {
+			return audit.ErrFailed
+		}
+		if audit.IsExpiring(expiry, auditThreshold) {
+			return fmt.Errorf("at least one credential expires within %d days.\n%w", auditThreshold, audit.ErrExpiring)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(auditCmd)
+	auditCmd.AddCommand(auditExpiryCmd)
+	addFlagsAudit()
+}
@@ -0,0 +1,9 @@
+package audit
+
+import (
+	"errors"
+)
+
+var ErrInvalidFlag = errors.New("invalid flag")
+var ErrExpiring = errors.New("credentials expire within threshold")
+var ErrFailed = errors.New("audit failed")
@@ -0,0 +1,183 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/giantswarm/mcli/pkg/github"
+	"github.com/giantswarm/mcli/pkg/key"
+	"github.com/giantswarm/mcli/pkg/managementcluster/cmc"
+	"github.com/giantswarm/mcli/pkg/sops"
+)
+
+const (
+	SortByExpiry     = "expiry"
+	SortByCluster    = "cluster"
+	SortByCredential = "credential"
+)
+
+type ExpiryConfig struct {
+	Cluster       string
+	All           bool
+	Github        *github.Github
+	CMCRepository string
+	CMCBranch     string
+	SortBy        string
+}
+
+func (c *ExpiryConfig) Run(ctx context.Context) ([]cmc.Expiry, error) {
+	err := c.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	cmcRepository := github.Repository{
+		Github:       c.Github,
+		Name:         c.CMCRepository,
+		Organization: key.OrganizationGiantSwarm,
+		Branch:       c.CMCBranch,
+	}
+	if err := cmcRepository.Check(ctx); err != nil {
+		return nil, err
+	}
+
+	clusters := []string{c.Cluster}
+	if c.All {
+		clusters, err = cmcRepository.GetDirectoryNames(ctx, key.CMCClustersPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list management clusters in %s.\n%w", c.CMCRepository, err)
+		}
+	}
+
+	sopsfile, err := cmcRepository.GetFile(ctx, cmc.SopsFile)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []cmc.Expiry
+	for _, cluster := range clusters {
+		expiry, err := c.getExpiry(ctx, cmcRepository, cluster, sopsfile)
+		if err != nil {
+			if !c.All {
+				return nil, err
+			}
+			// a broken entry should not hide the expiry of the other clusters
+			log.Debug().Msg(err.Error())
+			expiry = []cmc.Expiry{{
+				Cluster: cluster,
+				Error:   err.Error(),
+			}}
+		}
+		result = append(result, expiry...)
+	}
+	SortExpiry(result, c.SortBy)
+	return result, nil
+}
+
+func (c *ExpiryConfig) getExpiry(ctx context.Context, cmcRepository github.Repository, cluster string, sopsfile string) ([]cmc.Expiry, error) {
+	log.Debug().Msgf("checking credential expiry of %s entry for %s", c.CMCRepository, cluster)
+
+	data, err := cmcRepository.GetDirectory(ctx, key.GetCMCPath(cluster))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s entry for %s.\n%w", c.CMCRepository, cluster, err)
+	}
+	data[cmc.SopsFile] = sopsfile
+
+	current, err := cmc.GetCMCFromMap(data, cluster, c.CMCRepository)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s entry for %s.\n%w", c.CMCRepository, cluster, err)
+	}
+	expiry, err := current.GetExpiry()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get credential expiry for %s.\n%w", cluster, err)
+	}
+	return expiry, nil
+}
+
+func (c *ExpiryConfig) Validate() error {
+	// check if environment variable age key is set
+	if val, present := os.LookupEnv(sops.EnvAgeKey); !present || val == "" {
+		return fmt.Errorf("environment variable %s is not set\n%w", sops.EnvAgeKey, ErrInvalidFlag)
+	}
+	if c.CMCRepository == "" {
+		return fmt.Errorf("cmc repository is required\n%w", ErrInvalidFlag)
+	}
+	if c.Cluster == "" && !c.All {
+		return fmt.Errorf("cluster is required unless all clusters are audited\n%w", ErrInvalidFlag)
+	}
+	if !IsValidSortBy(c.SortBy) {
+		return fmt.Errorf("sort by %s is invalid. Valid values: %v\n%w", c.SortBy, GetValidSortBy(), ErrInvalidFlag)
+	}
+	return nil
+}
+
+func GetValidSortBy() []string {
+	return []string{SortByExpiry, SortByCluster, SortByCredential}
+}
+
+func IsValidSortBy(sortBy string) bool {
+	for _, s := range GetValidSortBy() {
+		if s == sortBy {
+			return true
+		}
+	}
+	return false
+}
+
+// SortExpiry sorts the report. Credentials with unknown expiry are listed last when sorting by expiry.
+func SortExpiry(expiry []cmc.Expiry, sortBy string) {
+	sort.SliceStable(expiry, func(i, j int) bool {
+		switch sortBy {
+		case SortByCluster:
+			if expiry[i].Cluster != expiry[j].Cluster {
+				return expiry[i].Cluster < expiry[j].Cluster
+			}
+			return expiry[i].Credential < expiry[j].Credential
+		case SortByCredential:
+			if expiry[i].Credential != expiry[j].Credential {
+				return expiry[i].Credential < expiry[j].Credential
+			}
+			return expiry[i].Cluster < expiry[j].Cluster
+		default:
+			if expiry[i].DaysToExpiry == nil {
+				return false
+			}
+			if expiry[j].DaysToExpiry == nil {
+				return true
+			}
+			return *expiry[i].DaysToExpiry < *expiry[j].DaysToExpiry
+		}
+	})
+}
+
+func HasFailed(expiry []cmc.Expiry) bool {
+	for _, e := range expiry {
+		if e.Error != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// IsExpiring returns true if any credential expires within the threshold in days.
+func IsExpiring(expiry []cmc.Expiry, threshold int) bool {
+	for _, e := range expiry {
+		if e.DaysToExpiry != nil && *e.DaysToExpiry <= threshold {
+			return true
+		}
+	}
+	return false
+}
+
+func PrintExpiry(expiry []cmc.Expiry) error {
+	data, err := key.GetData(expiry)
+	if err != nil {
+		return err
+	}
+	fmt.Print(string(data))
+	return nil
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/mcli/cmd/audit"
+	"github.com/giantswarm/mcli/pkg/key"
+)
+
+const (
+	flagThreshold = "threshold"
+	flagSortBy    = "sort-by"
+)
+
+var (
+	auditAll       bool
+	auditThreshold int
+	auditSortBy    string
+)
+
+func addFlagsAudit() {
+	auditExpiryCmd.Flags().BoolVar(&auditAll, flagAll, false, "Audit all management clusters in the CMC repository. (default: false)")
+	auditExpiryCmd.Flags().IntVar(&auditThreshold, flagThreshold, 30, "Number of days before expiry at which the command fails")
+	auditExpiryCmd.Flags().StringVar(&auditSortBy, flagSortBy, audit.SortByExpiry, "Sort the report by expiry, cluster or credential")
+}
+
+func defaultAudit() {
+	if cmcBranch == "" {
+		cmcBranch = key.CMCMainBranch
+	}
+	if customer == "" {
+		customer = key.OrganizationGiantSwarm
+	}
+	if cmcRepository == "" {
+		cmcRepository = key.GetCMCName(customer)
+	}
+}
+
+func validateAudit(cmd *cobra.Command, args []string) error {
+	if cluster == "" && !auditAll {
+		return invalidFlagError(flagCluster)
+	}
+	if githubToken == "" {
+		return invalidFlagError(flagGithubToken)
+	}
+	if cmcRepository == "" {
+		return invalidFlagError(flagCMCRepository)
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/mcli/cmd/bulk"
+	"github.com/giantswarm/mcli/pkg/github"
+)
+
+// bulkCmd represents the bulk command
+var bulkCmd = &cobra.Command{
+	Use:   "bulk",
+	Short: "Changes the configuration of several Management Clusters at once",
+}
+
+// bulkSetCmd represents the bulk set command
+var bulkSetCmd = &cobra.Command{
+	Use:   "set <path>=<value>...",
+	Short: "Sets fields of all Management Clusters matching a selector",
+	Long: `Sets fields of all Management Clusters matching a selector. The clusters are
+selected from the installations repository like in list. The fields are set on each
+cluster like in set, onto one branch per repository, and one pull request is opened
+per changed repository. A failing cluster does not stop the others. A table of the
+results is printed and the command fails if any cluster failed. For example:
+
+mcli bulk set cmc.clusterApp.version=1.2.3 --selector provider=capz,pipeline=testing --dry-run
+
+mcli bulk set cmc.clusterApp.version=1.2.3 --selector provider=capz,pipeline=testing`,
+	Args:         cobra.MinimumNArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		defaultBulk()
+		err := validateBulk(cmd, args)
+		if err != nil {
+			return err
+		}
+		ctx := context.Background()
+		client := github.New(github.Config{
+			Token: githubToken,
+		})
+		p, err := loadPolicy(ctx, client, cmcRepository)
+		if err != nil {
+			return err
+		}
+		c := bulk.Config{
+			Github:         client,
+			Selector:       bulkSelector,
+			Fields:         args,
+			Branch:         bulkBranch,
+			DryRun:         bulkDryRun,
+			DisplaySecrets: displaySecrets,
+			Out:            os.Stdout,
+			Policy:         p,
+		}
+		report, err := c.Run(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to set fields of management clusters.\n%w", err)
+		}
+		if err := bulk.Print(os.Stdout, report); err != nil {
+			return err
+		}
+		for _, result := range report.Clusters {
+			if result.Summary == nil {
+				continue
+			}
+			if err := writeAudit(ctx, cmd, client, result.CMCRepository, result.Summary); err != nil {
+				return err
+			}
+		}
+		if failed := report.Failed(); failed > 0 {
+			return fmt.Errorf("%d of %d management clusters or pull requests failed.\n%w", failed, len(report.Clusters)+len(report.PullRequests), bulk.ErrFailed)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(bulkCmd)
+	bulkCmd.AddCommand(bulkSetCmd)
+	addFlagsBulk()
+}
@@ -0,0 +1,261 @@
+package bulk
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/giantswarm/mcli/cmd/field"
+	"github.com/giantswarm/mcli/cmd/list"
+	"github.com/giantswarm/mcli/pkg/github"
+	"github.com/giantswarm/mcli/pkg/key"
+	"github.com/giantswarm/mcli/pkg/managementcluster"
+	"github.com/giantswarm/mcli/pkg/policy"
+)
+
+const (
+	StatusFailed = "failed"
+	StatusExists = "exists"
+	StatusDryRun = "dry-run"
+)
+
+type Config struct {
+	Github *github.Github
+	// Selector are field=pattern pairs as for list. All of them have to match.
+	Selector []string
+	// Fields are path=value pairs as for set.
+	Fields []string
+	// Branch is shared by all clusters of a repository.
+	Branch         string
+	DryRun         bool
+	DisplaySecrets bool
+	Out            io.Writer
+	// Policy is evaluated for each cluster. Clusters violating it fail.
+	Policy *policy.Policy
+}
+
+// Report lists the result of each cluster and the pull requests per repository.
+type Report struct {
+	Clusters     []ClusterResult
+	PullRequests []PullRequestResult
+}
+
+type ClusterResult struct {
+	Cluster       string
+	CMCRepository string
+	Status        string
+	Error         string
+	// Summary is set if the cluster was pushed.
+	Summary *managementcluster.Summary
+}
+
+type PullRequestResult struct {
+	Repository string
+	Branch     string
+	Status     string
+	Error      string
+}
+
+// Run sets the fields on all management clusters matching the selector.
+// Clusters are updated one by one, failures are reported and do not stop the other clusters.
+func (c *Config) Run(ctx context.Context) (*Report, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	l := list.Config{
+		Github:              c.Github,
+		InstallationsBranch: key.InstallationsMainBranch,
+		Filters:             c.Selector,
+		SortBy:              list.FieldCMCRepository,
+		Output:              list.OutputTable,
+	}
+	entries, err := l.Run(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list management clusters.\n%w", err)
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("no management cluster matches %s.\n%w", strings.Join(c.Selector, ","), ErrNotFound)
+	}
+	log.Debug().Msgf("found %d management clusters matching %s", len(entries), strings.Join(c.Selector, ","))
+
+	report := &Report{}
+	// changed repositories get a pull request, the value is the main branch
+	changed := map[string]string{}
+	for _, entry := range entries {
+		result := c.set(ctx, entry)
+		report.Clusters = append(report.Clusters, result)
+		if result.Summary == nil {
+			continue
+		}
+		for _, r := range result.Summary.Repositories {
+			if r.Status == github.StatusUnchanged {
+				continue
+			}
+			name := strings.TrimPrefix(r.Repository, key.OrganizationGiantSwarm+"/")
+			changed[name] = key.CMCMainBranch
+			if name == key.RepositoryInstallations {
+				changed[name] = key.InstallationsMainBranch
+			}
+		}
+	}
+
+	var repositories []string
+	for name := range changed {
+		repositories = append(repositories, name)
+	}
+	sort.Strings(repositories)
+	for _, name := range repositories {
+		report.PullRequests = append(report.PullRequests, c.createPullRequest(ctx, name, changed[name]))
+	}
+	return report, nil
+}
+
+func (c *Config) Validate() error {
+	if len(c.Selector) == 0 {
+		return fmt.Errorf("no selector given.\n%w", ErrInvalidFlag)
+	}
+	if len(c.Fields) == 0 {
+		return fmt.Errorf("no fields given.\n%w", ErrInvalidFlag)
+	}
+	for _, f := range c.Fields {
+		path, _, err := managementcluster.ParseField(f)
+		if err != nil {
+			return err
+		}
+		if _, err := managementcluster.GetFieldRepository(path); err != nil {
+			return err
+		}
+	}
+	if c.Branch == key.InstallationsMainBranch || c.Branch == key.CMCMainBranch {
+		return fmt.Errorf("branch %s is a main branch.\n%w", c.Branch, ErrInvalidFlag)
+	}
+	return nil
+}
+
+func (c *Config) set(ctx context.Context, entry list.Entry) ClusterResult {
+	result := ClusterResult{
+		Cluster:       entry.Codename,
+		CMCRepository: entry.CMCRepository,
+	}
+	var out bytes.Buffer
+	f := field.Config{
+		Cluster:             entry.Codename,
+		Github:              c.Github,
+		CMCRepository:       entry.CMCRepository,
+		InstallationsBranch: c.Branch,
+		CMCBranch:           c.Branch,
+		Fields:              c.Fields,
+		DryRun:              c.DryRun,
+		DisplaySecrets:      c.DisplaySecrets,
+		Out:                 &out,
+		Policy:              c.Policy,
+	}
+	summary, err := f.Set(ctx)
+	if c.DryRun && out.Len() > 0 {
+		fmt.Fprintf(c.Out, "%s:\n", entry.Codename)
+		for _, line := range strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n") {
+			fmt.Fprintf(c.Out, "  %s\n", line)
+		}
+	}
+	if err != nil {
+		log.Debug().Msgf("failed to set fields of %s.\n%s", entry.Codename, err)
+		result.Status = StatusFailed
+		result.Error = getErrorMessage(err)
+		return result
+	}
+	if c.DryRun {
+		result.Status = StatusDryRun
+		return result
+	}
+	result.Status = github.StatusUnchanged
+	if summary != nil {
+		result.Summary = summary
+		for _, r := range summary.Repositories {
+			if r.Status != github.StatusUnchanged {
+				result.Status = github.StatusUpdated
+			}
+		}
+	}
+	return result
+}
+
+func (c *Config) createPullRequest(ctx context.Context, name string, base string) PullRequestResult {
+	result := PullRequestResult{
+		Repository: name,
+		Branch:     c.Branch,
+		Status:     github.StatusCreated,
+	}
+	repository := github.Repository{
+		Github:       c.Github,
+		Name:         name,
+		Organization: key.OrganizationGiantSwarm,
+		Branch:       c.Branch,
+	}
+	title := fmt.Sprintf("Set %s on management clusters", strings.Join(c.getPaths(), ", "))
+	exists, err := repository.PullRequestExists(ctx, title)
+	if err == nil && exists {
+		log.Debug().Msgf("pull request %q already exists", title)
+		result.Status = StatusExists
+		return result
+	}
+	if err == nil {
+		err = repository.CreatePullRequest(ctx, title, base)
+	}
+	if err != nil {
+		result.Status = StatusFailed
+		result.Error = getErrorMessage(err)
+	}
+	return result
+}
+
+func (c *Config) getPaths() []string {
+	var paths []string
+	for _, f := range c.Fields {
+		path, _, _ := managementcluster.ParseField(f)
+		paths = append(paths, path)
+	}
+	return paths
+}
+
+// Failed returns the number of clusters and pull requests which failed.
+func (r *Report) Failed() int {
+	failed := 0
+	for _, c := range r.Clusters {
+		if c.Status == StatusFailed {
+			failed++
+		}
+	}
+	for _, p := range r.PullRequests {
+		if p.Status == StatusFailed {
+			failed++
+		}
+	}
+	return failed
+}
+
+func Print(w io.Writer, report *Report) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "CLUSTER\tCMC REPOSITORY\tSTATUS\tERROR")
+	for _, c := range report.Clusters {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", c.Cluster, c.CMCRepository, c.Status, c.Error)
+	}
+	if len(report.PullRequests) > 0 {
+		fmt.Fprintln(tw)
+		fmt.Fprintln(tw, "REPOSITORY\tBRANCH\tPULL REQUEST\tERROR")
+		for _, p := range report.PullRequests {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", p.Repository, p.Branch, p.Status, p.Error)
+		}
+	}
+	return tw.Flush()
+}
+
+// getErrorMessage returns the error on a single line for the table.
+func getErrorMessage(err error) string {
+	return strings.Join(strings.Fields(strings.ReplaceAll(err.Error(), "\n", " ")), " ")
+}
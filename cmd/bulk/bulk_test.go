@@ -0,0 +1,123 @@
+package bulk
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/giantswarm/mcli/pkg/github"
+	"github.com/giantswarm/mcli/pkg/github/githubtest"
+	"github.com/giantswarm/mcli/pkg/key"
+)
+
+func getInstallationsFile(cluster string, provider string) string {
+	return strings.NewReplacer("CLUSTER", cluster, "PROVIDER", provider).Replace(`base: CLUSTER.gigantic.io
+codename: CLUSTER
+customer: giantswarm
+cmc_repository: giantswarm-management-clusters
+ccr_repository: giantswarm-customer-configs
+accountEngineer: phoenix
+pipeline: testing
+provider: PROVIDER
+`)
+}
+
+func TestRun(t *testing.T) {
+	testCases := []struct {
+		name     string
+		selector []string
+		dryRun   bool
+
+		expectedStatus map[string]string
+		expectedPRs    int
+		expectedError  error
+	}{
+		{
+			name:     "case 0: update matching clusters",
+			selector: []string{"provider=capz"},
+
+			expectedStatus: map[string]string{"gigmac": github.StatusUpdated, "gauss": github.StatusUpdated, "broken": StatusFailed},
+			expectedPRs:    1,
+		},
+		{
+			name:     "case 1: dry run",
+			selector: []string{"provider=capz", "codename=g*"},
+			dryRun:   true,
+
+			expectedStatus: map[string]string{"gigmac": StatusDryRun, "gauss": StatusDryRun},
+		},
+		{
+			name:     "case 2: no matching cluster",
+			selector: []string{"provider=capv"},
+
+			expectedError: ErrNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := githubtest.NewServer()
+			defer server.Close()
+			server.AddBranchRepository(key.OrganizationGiantSwarm, key.RepositoryInstallations, key.InstallationsMainBranch, map[string]string{
+				"gigmac/cluster.yaml": getInstallationsFile("gigmac", "capz"),
+				"gauss/cluster.yaml":  getInstallationsFile("gauss", "capz"),
+				"golem/cluster.yaml":  getInstallationsFile("golem", "capa"),
+				// the account engineer is missing, so the cluster fails validation on push
+				"broken/cluster.yaml": strings.Replace(getInstallationsFile("broken", "capz"), "accountEngineer: phoenix\n", "", 1),
+			})
+
+			branch := "bulk_auto_test"
+			var out bytes.Buffer
+			c := Config{
+				Github:   server.Client(),
+				Selector: tc.selector,
+				Fields:   []string{"installations.pipeline=stable"},
+				Branch:   branch,
+				DryRun:   tc.dryRun,
+				Out:      &out,
+			}
+			report, err := c.Run(context.Background())
+			if tc.expectedError != nil {
+				if !errors.Is(err, tc.expectedError) {
+					t.Fatalf("expected error %v, got %v", tc.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			status := map[string]string{}
+			for _, result := range report.Clusters {
+				status[result.Cluster] = result.Status
+			}
+			if len(status) != len(tc.expectedStatus) {
+				t.Fatalf("expected %v, got %v", tc.expectedStatus, status)
+			}
+			for cluster, expected := range tc.expectedStatus {
+				if status[cluster] != expected {
+					t.Fatalf("expected %s to be %s, got %s", cluster, expected, status[cluster])
+				}
+			}
+			if pulls := server.PullRequests(key.OrganizationGiantSwarm, key.RepositoryInstallations); len(pulls) != tc.expectedPRs {
+				t.Fatalf("expected %d pull requests, got %+v", tc.expectedPRs, pulls)
+			}
+			if tc.dryRun {
+				if !strings.Contains(out.String(), `installations.pipeline: "testing" -> "stable"`) {
+					t.Fatalf("expected changes in output, got\n%s", out.String())
+				}
+				return
+			}
+			files := server.Files(key.OrganizationGiantSwarm, key.RepositoryInstallations, branch)
+			for _, cluster := range []string{"gigmac", "gauss"} {
+				if !strings.Contains(files[cluster+"/cluster.yaml"], "pipeline: stable") {
+					t.Fatalf("expected %s to be updated on %s, got\n%s", cluster, branch, files[cluster+"/cluster.yaml"])
+				}
+			}
+			if strings.Contains(files["golem/cluster.yaml"], "pipeline: stable") {
+				t.Fatalf("expected golem to be unchanged")
+			}
+		})
+	}
+}
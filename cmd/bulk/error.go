@@ -0,0 +1,11 @@
+package bulk
+
+import (
+	"errors"
+)
+
+var ErrInvalidFlag = errors.New("invalid flag")
+
+var ErrNotFound = errors.New("not found")
+
+var ErrFailed = errors.New("bulk update failed")
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/mcli/cmd/list"
+	"github.com/giantswarm/mcli/pkg/key"
+)
+
+const (
+	flagSelector = "selector"
+	flagBranch   = "branch"
+)
+
+var (
+	bulkSelector []string
+	bulkBranch   string
+	bulkDryRun   bool
+)
+
+func addFlagsBulk() {
+	bulkSetCmd.Flags().StringSliceVar(&bulkSelector, flagSelector, []string{}, fmt.Sprintf("Comma-separated field=pattern pairs selecting the management clusters. Patterns may contain shell wildcards. Valid fields: %v", list.GetFields()))
+	bulkSetCmd.Flags().StringVar(&bulkBranch, flagBranch, "", "Branch shared by all management clusters of a repository. (default: bulk_auto_<random>)")
+	bulkSetCmd.Flags().BoolVar(&bulkDryRun, flagDryRun, false, "Print the changed fields of each management cluster without pushing. (default: false)")
+	addFlagPolicy(bulkSetCmd)
+}
+
+func defaultBulk() {
+	if bulkBranch == "" {
+		bulkBranch = key.GetBulkBranch()
+	}
+	if customer == "" {
+		customer = key.OrganizationGiantSwarm
+	}
+	if cmcRepository == "" {
+		cmcRepository = key.GetCMCName(customer)
+	}
+}
+
+func validateBulk(cmd *cobra.Command, args []string) error {
+	if githubToken == "" {
+		return invalidFlagError(flagGithubToken)
+	}
+	if len(bulkSelector) == 0 {
+		return invalidFlagError(flagSelector)
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/mcli/cmd/ca"
+	"github.com/giantswarm/mcli/pkg/github"
+)
+
+// caCmd represents the ca command
+var caCmd = &cobra.Command{
+	Use:   "ca",
+	Short: "Inspects the private CAs of a Management Cluster",
+}
+
+// caInfoCmd represents the ca info command
+var caInfoCmd = &cobra.Command{
+	Use:   "info",
+	Short: "Lists subjects and expiry dates of the private CAs of a Management Cluster",
+	Long: `Lists subjects and expiry dates of the private CA certificates configured
+in the CMC repository entry of a Management Cluster. For example:
+
+mcli ca info --cluster=gigmac`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		defaultCA()
+		err := validateRoot(cmd, args)
+		if err != nil {
+			return err
+		}
+		ctx := context.Background()
+		client := github.New(github.Config{
+			Token: githubToken,
+		})
+		c := ca.Config{
+			Cluster:       cluster,
+			Github:        client,
+			CMCRepository: cmcRepository,
+			CMCBranch:     cmcBranch,
+		}
+		certificates, err := c.Run(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get private CA information.\n%w", err)
+		}
+		return ca.Print(certificates)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(caCmd)
+	caCmd.AddCommand(caInfoCmd)
+}
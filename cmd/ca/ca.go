@@ -0,0 +1,81 @@
+package ca
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/rs/zerolog/log"
+
+	pullcmc "github.com/giantswarm/mcli/cmd/pull/cmc"
+	"github.com/giantswarm/mcli/pkg/github"
+	"github.com/giantswarm/mcli/pkg/key"
+	"github.com/giantswarm/mcli/pkg/managementcluster/cmc"
+	"github.com/giantswarm/mcli/pkg/managementcluster/cmc/privateca"
+)
+
+const (
+	SourceBundle = "bundle"
+	SourceIssuer = "issuer"
+)
+
+type Config struct {
+	Cluster       string
+	Github        *github.Github
+	CMCRepository string
+	CMCBranch     string
+}
+
+func (c *Config) Run(ctx context.Context) ([]privateca.CertificateInfo, error) {
+	log.Debug().Msgf("getting private CA information of %s", c.Cluster)
+
+	p := pullcmc.Config{
+		Cluster:       c.Cluster,
+		Github:        c.Github,
+		CMCRepository: c.CMCRepository,
+		CMCBranch:     c.CMCBranch,
+	}
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	current, err := p.Run(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pull %s entry for %s.\n%w", c.CMCRepository, c.Cluster, err)
+	}
+	return GetCertificates(current)
+}
+
+// GetCertificates returns the certificates of the configured private CAs sorted by expiry.
+func GetCertificates(c *cmc.CMC) ([]privateca.CertificateInfo, error) {
+	var result []privateca.CertificateInfo
+	if !c.PrivateCA.Enabled {
+		return result, nil
+	}
+	if c.PrivateCA.Certificate != "" {
+		issuer, err := privateca.GetCertificates(c.PrivateCA.Certificate, SourceIssuer)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get issuer certificate.\n%w", err)
+		}
+		result = append(result, issuer...)
+	}
+	if c.PrivateCA.Bundle != "" {
+		bundle, err := privateca.GetCertificates(c.PrivateCA.Bundle, SourceBundle)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get bundle certificates.\n%w", err)
+		}
+		result = append(result, bundle...)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].NotAfter.Before(result[j].NotAfter)
+	})
+	return result, nil
+}
+
+func Print(certificates []privateca.CertificateInfo) error {
+	data, err := key.GetData(certificates)
+	if err != nil {
+		return err
+	}
+	fmt.Print(string(data))
+	return nil
+}
@@ -0,0 +1,7 @@
+package ca
+
+import (
+	"errors"
+)
+
+var ErrInvalidFlag = errors.New("invalid flag")
@@ -0,0 +1,17 @@
+package cmd
+
+import (
+	"github.com/giantswarm/mcli/pkg/key"
+)
+
+func defaultCA() {
+	if cmcBranch == "" {
+		cmcBranch = key.CMCMainBranch
+	}
+	if customer == "" {
+		customer = key.OrganizationGiantSwarm
+	}
+	if cmcRepository == "" {
+		cmcRepository = key.GetCMCName(customer)
+	}
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/mcli/cmd/check"
+	"github.com/giantswarm/mcli/pkg/github"
+)
+
+// checkCmd represents the check command
+var checkCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Checks the CMC repository entry of a Management Cluster for drift",
+	Long: `Checks whether the CMC repository entry of a Management Cluster still matches
+what mcli would render from the CMC entry template. Files that differ, files
+unknown to mcli and kustomization references to missing files are reported.
+The command exits with a non-zero exit code if drift is detected or a cluster
+could not be checked. For example:
+
+mcli check --cluster=gigmac
+
+mcli check --all --customer=giantswarm`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		defaultCheck()
+		err := validateCheck(cmd, args)
+		if err != nil {
+			return err
+		}
+		ctx := context.Background()
+		client := github.New(github.Config{
+			Token: githubToken,
+		})
+		c := check.Config{
+			Cluster:       cluster,
+			All:           checkAll,
+			Github:        client,
+			CMCRepository: cmcRepository,
+			CMCBranch:     cmcBranch,
+		}
+		drifts, err := c.Run(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to check CMC.\n%w", err)
+		}
+		err = check.Print(drifts)
+		if err != nil {
+			return err
+		}
+		if check.HasFailed(drifts) {
+			return check.ErrFailed
+		}
+		if check.HasDrift(drifts) {
+			return check.ErrDrift
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(checkCmd)
+	addFlagsCheck()
+}
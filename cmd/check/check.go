@@ -0,0 +1,153 @@
+package check
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+
+	pushcmc "github.com/giantswarm/mcli/cmd/push/cmc"
+	"github.com/giantswarm/mcli/pkg/github"
+	"github.com/giantswarm/mcli/pkg/key"
+	"github.com/giantswarm/mcli/pkg/managementcluster/cmc"
+	"github.com/giantswarm/mcli/pkg/sops"
+)
+
+type Config struct {
+	Cluster       string
+	All           bool
+	Github        *github.Github
+	CMCRepository string
+	CMCBranch     string
+}
+
+func (c *Config) Run(ctx context.Context) ([]*cmc.Drift, error) {
+	err := c.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	cmcRepository := github.Repository{
+		Github:       c.Github,
+		Name:         c.CMCRepository,
+		Organization: key.OrganizationGiantSwarm,
+		Branch:       c.CMCBranch,
+	}
+	if err := cmcRepository.Check(ctx); err != nil {
+		return nil, err
+	}
+
+	clusters := []string{c.Cluster}
+	if c.All {
+		clusters, err = cmcRepository.GetDirectoryNames(ctx, key.CMCClustersPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list management clusters in %s.\n%w", c.CMCRepository, err)
+		}
+	}
+
+	sopsfile, err := cmcRepository.GetFile(ctx, cmc.SopsFile)
+	if err != nil {
+		return nil, err
+	}
+
+	template, err := c.getTemplate()
+	if err != nil {
+		return nil, err
+	}
+
+	var result []*cmc.Drift
+	for _, cluster := range clusters {
+		drift, err := c.check(ctx, cmcRepository, cluster, sopsfile, template)
+		if err != nil {
+			err = fmt.Errorf("failed to check %s entry for %s.\n%w", c.CMCRepository, cluster, err)
+			if !c.All {
+				return nil, err
+			}
+			// a broken entry should not hide the drift of the other clusters
+			log.Debug().Msg(err.Error())
+			drift = &cmc.Drift{
+				Cluster: cluster,
+				Error:   err.Error(),
+			}
+		}
+		result = append(result, drift)
+	}
+	return result, nil
+}
+
+func (c *Config) check(ctx context.Context, cmcRepository github.Repository, cluster string, sopsfile string, template map[string]string) (*cmc.Drift, error) {
+	log.Debug().Msgf("checking %s entry for %s", c.CMCRepository, cluster)
+
+	data, err := cmcRepository.GetDirectory(ctx, key.GetCMCPath(cluster))
+	if err != nil {
+		return nil, err
+	}
+	data[cmc.SopsFile] = sopsfile
+
+	clusterTemplate := make(map[string]string, len(template))
+	for k, v := range template {
+		clusterTemplate[fmt.Sprintf("%s/%s", key.GetCMCPath(cluster), k)] = v
+	}
+
+	return cmc.GetDriftFromMap(data, clusterTemplate, cluster, c.CMCRepository)
+}
+
+// getTemplate returns the files of the cmc entry template relative to the cluster directory.
+func (c *Config) getTemplate() (map[string]string, error) {
+	p := pushcmc.Config{
+		Cluster: c.Cluster,
+		Github:  c.Github,
+	}
+	template, err := p.PullTemplate()
+	if err != nil {
+		return nil, fmt.Errorf("failed to pull template.\n%w", err)
+	}
+	files := make(map[string]string, len(template))
+	for k, v := range template {
+		files[strings.TrimPrefix(k, key.GetCMCPath(c.Cluster)+"/")] = v
+	}
+	return files, nil
+}
+
+func (c *Config) Validate() error {
+	// check if environment variable age key is set
+	if val, present := os.LookupEnv(sops.EnvAgeKey); !present || val == "" {
+		return fmt.Errorf("environment variable %s is not set\n%w", sops.EnvAgeKey, ErrInvalidFlag)
+	}
+	if c.CMCRepository == "" {
+		return fmt.Errorf("cmc repository is required\n%w", ErrInvalidFlag)
+	}
+	if c.Cluster == "" && !c.All {
+		return fmt.Errorf("cluster is required unless all clusters are checked\n%w", ErrInvalidFlag)
+	}
+	return nil
+}
+
+func Print(drifts []*cmc.Drift) error {
+	data, err := key.GetData(drifts)
+	if err != nil {
+		return err
+	}
+	fmt.Print(string(data))
+	return nil
+}
+
+func HasFailed(drifts []*cmc.Drift) bool {
+	for _, d := range drifts {
+		if d.Error != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func HasDrift(drifts []*cmc.Drift) bool {
+	for _, d := range drifts {
+		if d.HasDrift() {
+			return true
+		}
+	}
+	return false
+}
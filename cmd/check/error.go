@@ -0,0 +1,9 @@
+package check
+
+import (
+	"errors"
+)
+
+var ErrInvalidFlag = errors.New("invalid flag")
+var ErrDrift = errors.New("drift detected")
+var ErrFailed = errors.New("check failed")
@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/mcli/pkg/key"
+)
+
+const (
+	flagAll = "all"
+)
+
+var (
+	checkAll bool
+)
+
+func addFlagsCheck() {
+	checkCmd.Flags().BoolVar(&checkAll, flagAll, false, "Check all management clusters in the CMC repository. (default: false)")
+}
+
+func defaultCheck() {
+	if cmcBranch == "" {
+		cmcBranch = key.CMCMainBranch
+	}
+	if customer == "" {
+		customer = key.OrganizationGiantSwarm
+	}
+	if cmcRepository == "" {
+		cmcRepository = key.GetCMCName(customer)
+	}
+}
+
+func validateCheck(cmd *cobra.Command, args []string) error {
+	if cluster == "" && !checkAll {
+		return invalidFlagError(flagCluster)
+	}
+	if githubToken == "" {
+		return invalidFlagError(flagGithubToken)
+	}
+	if cmcRepository == "" {
+		return invalidFlagError(flagCMCRepository)
+	}
+	return nil
+}
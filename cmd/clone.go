@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/mcli/cmd/clone"
+	"github.com/giantswarm/mcli/cmd/pull"
+)
+
+// cloneCmd represents the clone command
+var cloneCmd = &cobra.Command{
+	Use:   "clone",
+	Short: "Clones the configuration of a Management Cluster as a template for a new one",
+	Long: `Clones the configuration of an existing Management Cluster as a template for a new one.
+Cluster specific fields like the name, base domain, namespace and branches are
+rewritten and all secrets are removed. The result is written to a file that can be
+edited and passed to mcli push using --input. For example:
+
+mcli clone --from=gigmac --to=golem
+
+mcli clone --from=gigmac --to=golem --base-domain=golem.example.com --secret-folder=secrets/golem`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		defaultClone()
+		err := validateClone(cmd, args)
+		if err != nil {
+			return err
+		}
+		ctx := context.Background()
+		c := clone.Config{
+			From:         cloneFrom,
+			To:           cloneTo,
+			BaseDomain:   baseDomain,
+			SecretFolder: secretFolder,
+			File:         cloneFile,
+			Pull: pull.Config{
+				GithubToken:         githubToken,
+				InstallationsBranch: installationsBranch,
+				CMCBranch:           cmcBranch,
+				CMCRepository:       cmcRepository,
+			},
+		}
+		_, err = c.Run(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to clone management cluster.\n%w", err)
+		}
+		fmt.Printf("Wrote %s. Add the secrets and push it with:\n\nmcli push --cluster %s --customer %s --input %s\n", c.File, cloneTo, customer, c.File)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(cloneCmd)
+	addFlagsClone()
+}
@@ -0,0 +1,154 @@
+package clone
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/giantswarm/mcli/cmd/pull"
+	"github.com/giantswarm/mcli/pkg/key"
+	"github.com/giantswarm/mcli/pkg/managementcluster"
+)
+
+type Config struct {
+	From string
+	To   string
+	// BaseDomain defaults to the base domain of the source with the cluster name replaced.
+	BaseDomain   string
+	SecretFolder string
+	// File defaults to <to>.yaml.
+	File string
+	Pull pull.Config
+}
+
+func (c *Config) Run(ctx context.Context) (*managementcluster.ManagementCluster, error) {
+	c.Pull.Cluster = c.From
+	mc, err := c.Pull.Pull(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pull management cluster %s.\n%w", c.From, err)
+	}
+	mc, err = c.Clone(mc)
+	if err != nil {
+		return nil, err
+	}
+	if c.File == "" {
+		c.File = fmt.Sprintf("%s.yaml", c.To)
+	}
+	data, err := GetData(mc)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(c.File, data, 0600); err != nil {
+		return nil, fmt.Errorf("failed to write %s.\n%w", c.File, err)
+	}
+	return mc, nil
+}
+
+// GetData returns the input file of the clone. Fields which are null or "", e.g. stripped secrets, are left out,
+// since they would unset the fields of an existing management cluster on push.
+func GetData(mc *managementcluster.ManagementCluster) ([]byte, error) {
+	data, err := key.RemoveEmptyFields(mc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to remove empty fields.\n%w", err)
+	}
+	return data, nil
+}
+
+// Clone rewrites the cluster specific fields of the source for the new cluster and strips all secrets.
+func (c *Config) Clone(source *managementcluster.ManagementCluster) (*managementcluster.ManagementCluster, error) {
+	log.Debug().Msgf("cloning management cluster %s to %s", c.From, c.To)
+	mc := *source
+
+	fromBaseDomain := mc.CMC.BaseDomain
+	if fromBaseDomain == "" {
+		fromBaseDomain = mc.Installations.Base
+	}
+	toBaseDomain, err := c.getBaseDomain(fromBaseDomain)
+	if err != nil {
+		return nil, err
+	}
+
+	if mc.Installations.Codename != "" {
+		mc.Installations.Codename = c.To
+		mc.Installations.Base = toBaseDomain
+	}
+
+	if mc.CMC.Cluster != "" {
+		mc.CMC.Cluster = c.To
+		mc.CMC.BaseDomain = toBaseDomain
+		mc.CMC.ClusterNamespace = replaceNameSegment(mc.CMC.ClusterNamespace, c.From, c.To)
+		mc.CMC.ClusterApp.AppName = replaceNameSegment(mc.CMC.ClusterApp.AppName, c.From, c.To)
+		mc.CMC.DefaultApps.AppName = replaceNameSegment(mc.CMC.DefaultApps.AppName, c.From, c.To)
+		mc.CMC.GitOps.CMCBranch = key.GetDefaultPRBranch(c.To)
+		mc.CMC.GitOps.ConfigBranch = key.GetDefaultConfigBranch(c.To)
+		mc.CMC.GitOps.MCAppCollectionBranch = key.GetDefaultPRBranch(c.To)
+
+		mc.CMC.ClusterApp.Values = c.replaceValues(mc.CMC.ClusterApp.Values, fromBaseDomain, toBaseDomain)
+		mc.CMC.DefaultApps.Values = c.replaceValues(mc.CMC.DefaultApps.Values, fromBaseDomain, toBaseDomain)
+		mc.CMC.StripSecrets()
+	}
+	mc.SecretFolder = c.SecretFolder
+	return &mc, nil
+}
+
+func (c *Config) getBaseDomain(fromBaseDomain string) (string, error) {
+	if c.BaseDomain != "" {
+		return c.BaseDomain, nil
+	}
+	toBaseDomain := replaceName(fromBaseDomain, c.From, c.To)
+	if fromBaseDomain == "" || toBaseDomain == fromBaseDomain {
+		return "", fmt.Errorf("base domain %s of %s does not contain the cluster name. Please set the base domain.\n%w", fromBaseDomain, c.From, ErrInvalidFlag)
+	}
+	return toBaseDomain, nil
+}
+
+// replaceValues replaces the CMC path and the base domain of the source in values.
+func (c *Config) replaceValues(values string, fromBaseDomain string, toBaseDomain string) string {
+	values = replaceName(values, key.GetCMCPath(c.From), key.GetCMCPath(c.To))
+	if fromBaseDomain != "" {
+		values = replaceName(values, fromBaseDomain, toBaseDomain)
+	}
+	return values
+}
+
+// replaceName only replaces whole names, e.g. management-clusters/gig is not replaced in management-clusters/gigmac.
+func replaceName(value string, from string, to string) string {
+	return replaceWord(value, from, to, isNameChar)
+}
+
+// replaceNameSegment also replaces names which are a segment of a dash separated name, e.g. gig in org-gig but not in org-gigmac.
+func replaceNameSegment(value string, from string, to string) string {
+	return replaceWord(value, from, to, isAlphanumeric)
+}
+
+func replaceWord(value string, from string, to string, isWordChar func(byte) bool) string {
+	var result strings.Builder
+	offset := 0
+	for {
+		i := strings.Index(value[offset:], from)
+		if i < 0 {
+			result.WriteString(value[offset:])
+			return result.String()
+		}
+		start := offset + i
+		end := start + len(from)
+		result.WriteString(value[offset:start])
+		if (start == 0 || !isWordChar(value[start-1])) && (end == len(value) || !isWordChar(value[end])) {
+			result.WriteString(to)
+		} else {
+			result.WriteString(from)
+		}
+		offset = end
+	}
+}
+
+func isNameChar(c byte) bool {
+	return c == '-' || isAlphanumeric(c)
+}
+
+func isAlphanumeric(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
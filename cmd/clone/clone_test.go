@@ -0,0 +1,273 @@
+package clone
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/giantswarm/mcli/pkg/key"
+	"github.com/giantswarm/mcli/pkg/managementcluster"
+	"github.com/giantswarm/mcli/pkg/managementcluster/cmc"
+	"github.com/giantswarm/mcli/pkg/managementcluster/cmc/registry"
+	"github.com/giantswarm/mcli/pkg/managementcluster/installations"
+)
+
+func TestClone(t *testing.T) {
+	testCases := []struct {
+		name        string
+		baseDomain  string
+		source      managementcluster.ManagementCluster
+		expectError error
+		expected    managementcluster.ManagementCluster
+	}{
+		{
+			name: "case 0: cluster specific fields are rewritten and secrets stripped",
+			source: managementcluster.ManagementCluster{
+				Installations: installations.Installations{
+					Codename: "gig",
+					Base:     "gig.example.com",
+					Customer: "giantswarm",
+				},
+				CMC: cmc.CMC{
+					AgePubKey:        "age1key",
+					Cluster:          "gig",
+					BaseDomain:       "gig.example.com",
+					ClusterNamespace: "org-gig",
+					ClusterApp: cmc.App{
+						Name:    "cluster-aws",
+						AppName: "gig",
+						Values:  "path: management-clusters/gig/values\nother: management-clusters/gigmac\ndomain: api.gig.example.com\n",
+					},
+					DefaultApps:    cmc.App{AppName: "gig-default-apps"},
+					TaylorBotToken: "token",
+					SSHdeployKey:   cmc.DeployKey{Identity: "identity", Passphrase: "passphrase", KnownHosts: "hosts"},
+					Provider: cmc.Provider{
+						Name: "capz",
+						CAPZ: cmc.CAPZ{ClientID: "client", ClientSecret: "secret"},
+					},
+					PrivateCA: cmc.PrivateCA{Enabled: true, Certificate: "cert", Key: "key"},
+					ConfigureContainerRegistries: cmc.ConfigureContainerRegistries{
+						Enabled: true,
+						Registries: []registry.Registry{{
+							Name:      "docker.io",
+							Endpoints: []registry.Endpoint{{Endpoint: "mirror", Username: "user", Password: "password"}},
+						}},
+					},
+					CredentialExpiry: map[string]string{"taylorBotToken": "2030-01-01"},
+					GitOps: cmc.GitOps{
+						CMCRepository:   "giantswarm-management-clusters",
+						CMCBranch:       "main",
+						MCBBranchSource: "main",
+					},
+				},
+			},
+			expected: managementcluster.ManagementCluster{
+				Installations: installations.Installations{
+					Codename: "golem",
+					Base:     "golem.example.com",
+					Customer: "giantswarm",
+				},
+				CMC: cmc.CMC{
+					Cluster:          "golem",
+					BaseDomain:       "golem.example.com",
+					ClusterNamespace: "org-golem",
+					ClusterApp: cmc.App{
+						Name:    "cluster-aws",
+						AppName: "golem",
+						Values:  "path: management-clusters/golem/values\nother: management-clusters/gigmac\ndomain: api.golem.example.com\n",
+					},
+					DefaultApps: cmc.App{AppName: "golem-default-apps"},
+					Provider: cmc.Provider{
+						Name: "capz",
+						CAPZ: cmc.CAPZ{ClientID: "client"},
+					},
+					PrivateCA: cmc.PrivateCA{Enabled: true, Certificate: "cert"},
+					ConfigureContainerRegistries: cmc.ConfigureContainerRegistries{
+						Enabled: true,
+						Registries: []registry.Registry{{
+							Name:      "docker.io",
+							Endpoints: []registry.Endpoint{{Endpoint: "mirror", Username: "user"}},
+						}},
+					},
+					GitOps: cmc.GitOps{
+						CMCRepository:         "giantswarm-management-clusters",
+						CMCBranch:             "golem_auto_branch",
+						MCBBranchSource:       "main",
+						ConfigBranch:          "golem_auto_config",
+						MCAppCollectionBranch: "golem_auto_branch",
+					},
+				},
+				SecretFolder: "secrets/golem",
+			},
+		},
+		{
+			name:       "case 1: base domain is set",
+			baseDomain: "golem.other.io",
+			source: managementcluster.ManagementCluster{
+				Installations: installations.Installations{Codename: "gig", Base: "example.com"},
+			},
+			expected: managementcluster.ManagementCluster{
+				Installations: installations.Installations{Codename: "golem", Base: "golem.other.io"},
+				SecretFolder:  "secrets/golem",
+			},
+		},
+		{
+			name: "case 2: cluster name is a prefix of the base domain",
+			source: managementcluster.ManagementCluster{
+				CMC: cmc.CMC{
+					Cluster:          "gig",
+					BaseDomain:       "gig.gigantic.io",
+					ClusterNamespace: "org-gigantic",
+					ClusterApp:       cmc.App{AppName: "gig", Values: "domain: api.gig.gigantic.io\n"},
+					DefaultApps:      cmc.App{AppName: "gigantic-default-apps"},
+				},
+			},
+			expected: managementcluster.ManagementCluster{
+				CMC: cmc.CMC{
+					Cluster:          "golem",
+					BaseDomain:       "golem.gigantic.io",
+					ClusterNamespace: "org-gigantic",
+					ClusterApp:       cmc.App{AppName: "golem", Values: "domain: api.golem.gigantic.io\n"},
+					DefaultApps:      cmc.App{AppName: "gigantic-default-apps"},
+					GitOps: cmc.GitOps{
+						CMCBranch:             "golem_auto_branch",
+						ConfigBranch:          "golem_auto_config",
+						MCAppCollectionBranch: "golem_auto_branch",
+					},
+				},
+				SecretFolder: "secrets/golem",
+			},
+		},
+		{
+			name: "case 3: base domain without cluster name",
+			source: managementcluster.ManagementCluster{
+				Installations: installations.Installations{Codename: "gig", Base: "example.com"},
+			},
+			expectError: ErrInvalidFlag,
+		},
+		{
+			name: "case 4: base domain only contains the cluster name as a prefix",
+			source: managementcluster.ManagementCluster{
+				Installations: installations.Installations{Codename: "gig", Base: "gigantic.io"},
+			},
+			expectError: ErrInvalidFlag,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := Config{
+				From:         "gig",
+				To:           "golem",
+				BaseDomain:   tc.baseDomain,
+				SecretFolder: "secrets/golem",
+			}
+			mc, err := c.Clone(&tc.source)
+			if tc.expectError != nil {
+				if !errors.Is(err, tc.expectError) {
+					t.Fatalf("expected %v but got %v", tc.expectError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(*mc, tc.expected) {
+				t.Fatalf("expected %#v\nbut got %#v", tc.expected, *mc)
+			}
+		})
+	}
+}
+
+func TestPushClone(t *testing.T) {
+	source := managementcluster.ManagementCluster{
+		Installations: installations.Installations{Codename: "gig", Base: "gig.example.com", Pipeline: "testing"},
+		CMC: cmc.CMC{
+			Cluster:        "gig",
+			BaseDomain:     "gig.example.com",
+			ClusterApp:     cmc.App{Name: "cluster-aws", AppName: "gig", Version: "2.0.0"},
+			TaylorBotToken: "token",
+			SSHdeployKey:   cmc.DeployKey{Identity: "identity", Passphrase: "passphrase", KnownHosts: "hosts"},
+			PrivateCA:      cmc.PrivateCA{Enabled: true, Certificate: "cert", Key: "key"},
+		},
+	}
+	existing := managementcluster.ManagementCluster{
+		Installations: installations.Installations{Codename: "golem", Base: "golem.example.com", Pipeline: "stable", CmcRepository: "giantswarm-management-clusters"},
+		CMC: cmc.CMC{
+			Cluster:        "golem",
+			BaseDomain:     "golem.example.com",
+			AgePubKey:      "age1golem",
+			ClusterApp:     cmc.App{Name: "cluster-aws", AppName: "golem", Version: "1.0.0", Catalog: "cluster", Values: "values"},
+			TaylorBotToken: "golem-token",
+			SSHdeployKey:   cmc.DeployKey{Identity: "golem-identity", Passphrase: "golem-passphrase", KnownHosts: "golem-hosts"},
+			PrivateCA:      cmc.PrivateCA{Enabled: true, Certificate: "golem-cert", Key: "golem-key"},
+		},
+	}
+
+	c := Config{From: "gig", To: "golem"}
+	mc, err := c.Clone(&source)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, err := GetData(mc)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	input, err := managementcluster.GetManagementCluster(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// fields are overridden and unset like push --input does
+	unset, err := key.GetUnsetFields(data, "cmc")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	unset, err = key.FilterUnsetFields(&input.CMC, unset)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	desired, err := existing.CMC.Override(&input.CMC).Unset(unset)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if desired.ClusterApp.Version != "2.0.0" || desired.ClusterApp.Catalog != "cluster" || desired.ClusterApp.Values != "values" {
+		t.Fatalf("expected cluster app to be updated and kept, got %+v", desired.ClusterApp)
+	}
+	if desired.AgePubKey != "age1golem" || desired.TaylorBotToken != "golem-token" || desired.SSHdeployKey != existing.CMC.SSHdeployKey || desired.PrivateCA.Key != "golem-key" {
+		t.Fatalf("expected secrets of the existing cluster to be kept, got %+v", desired)
+	}
+
+	unset, err = key.GetUnsetFields(data, "installations")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	desiredInstallations, err := existing.Installations.Override(&input.Installations).Unset(unset)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if desiredInstallations.Pipeline != "testing" || desiredInstallations.CmcRepository != "giantswarm-management-clusters" {
+		t.Fatalf("expected installations to be updated and kept, got %+v", desiredInstallations)
+	}
+}
+
+func TestReplaceName(t *testing.T) {
+	testCases := []struct {
+		value    string
+		expected string
+	}{
+		{value: "gig", expected: "golem"},
+		{value: "gig gig", expected: "golem golem"},
+		{value: "gigmac", expected: "gigmac"},
+		{value: "org-gig", expected: "org-gig"},
+		{value: "gig.example.com/gig", expected: "golem.example.com/golem"},
+		{value: "xgig gigx gig", expected: "xgig gigx golem"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.value, func(t *testing.T) {
+			if result := replaceName(tc.value, "gig", "golem"); result != tc.expected {
+				t.Fatalf("expected %s but got %s", tc.expected, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,7 @@
+package clone
+
+import (
+	"errors"
+)
+
+var ErrInvalidFlag = errors.New("invalid flag")
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	flagFrom = "from"
+	flagTo   = "to"
+)
+
+var (
+	cloneFrom string
+	cloneTo   string
+	cloneFile string
+)
+
+func addFlagsClone() {
+	viper.AutomaticEnv()
+
+	cloneCmd.Flags().StringVar(&cloneFrom, flagFrom, "", "Name of the management cluster to clone")
+	cloneCmd.Flags().StringVar(&cloneTo, flagTo, "", "Name of the new management cluster")
+	cloneCmd.Flags().StringVarP(&cloneFile, flagFile, "f", "", "File to write the configuration to. (default: <to>.yaml)")
+	cloneCmd.Flags().StringVar(&baseDomain, flagBaseDomain, viper.GetString(envBaseDomain), "Base domain of the new management cluster. (default: base domain of the source with the cluster name replaced)")
+	cloneCmd.Flags().StringVar(&secretFolder, flagSecretFolder, viper.GetString(envSecretFolder), "Secret folder referenced by the configuration")
+}
+
+func defaultClone() {
+	defaultPull()
+}
+
+func validateClone(cmd *cobra.Command, args []string) error {
+	if cloneFrom == "" {
+		return invalidFlagError(flagFrom)
+	}
+	if cloneTo == "" || cloneTo == cloneFrom {
+		return invalidFlagError(flagTo)
+	}
+	if githubToken == "" {
+		return invalidFlagError(flagGithubToken)
+	}
+	if cmcRepository == "" {
+		return invalidFlagError(flagCMCRepository)
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/mcli/cmd/compare"
+	"github.com/giantswarm/mcli/pkg/github"
+)
+
+// compareCmd represents the compare command
+var compareCmd = &cobra.Command{
+	Use:   "compare <cluster> <cluster>",
+	Short: "Compares the configuration of two Management Clusters",
+	Long: `Compares the configuration of two Management Clusters field by field.
+Cluster-specific values like the codename, base domain, namespace and CMC path
+are replaced by placeholders, so only differences in the configuration remain.
+App values are compared field by field and the resources of the kustomizations
+of the CMC entries are compared as well. Secrets are only compared with
+--display-secrets. For example:
+
+mcli compare gigmac gauss
+
+mcli compare gigmac gauss --skip installations -o json`,
+	Args:         cobra.ExactArgs(2),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		defaultCompare()
+		err := validateCompare(cmd, args)
+		if err != nil {
+			return err
+		}
+		ctx := context.Background()
+		c := compare.Config{
+			A: args[0],
+			B: args[1],
+			Github: github.New(github.Config{
+				Token: githubToken,
+			}),
+			CMCRepository:       cmcRepository,
+			InstallationsBranch: installationsBranch,
+			CMCBranch:           cmcBranch,
+			Skip:                skip,
+			DisplaySecrets:      displaySecrets,
+		}
+		differences, err := c.Run(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to compare management clusters.\n%w", err)
+		}
+		return compare.Print(os.Stdout, differences, compareOutput)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(compareCmd)
+	addFlagsCompare()
+}
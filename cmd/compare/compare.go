@@ -0,0 +1,149 @@
+package compare
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/rs/zerolog/log"
+
+	pullcmc "github.com/giantswarm/mcli/cmd/pull/cmc"
+	pullinstallations "github.com/giantswarm/mcli/cmd/pull/installations"
+	"github.com/giantswarm/mcli/pkg/github"
+	"github.com/giantswarm/mcli/pkg/key"
+	"github.com/giantswarm/mcli/pkg/managementcluster"
+	"github.com/giantswarm/mcli/pkg/managementcluster/cmc/kustomization"
+)
+
+const (
+	OutputText = "text"
+	OutputJSON = key.OutputJSON
+)
+
+// FieldResources lists the resources and patches of the kustomization of the CMC entry.
+const FieldResources = "kustomization.resources"
+
+type Config struct {
+	A      string
+	B      string
+	Github *github.Github
+	// CMCRepository is used for clusters without installations entry.
+	CMCRepository       string
+	InstallationsBranch string
+	CMCBranch           string
+	Skip                []string
+	// Secrets are only compared with DisplaySecrets, since they always differ between clusters.
+	DisplaySecrets bool
+}
+
+type state struct {
+	mc        *managementcluster.ManagementCluster
+	resources []string
+}
+
+// Run returns the differences of the management clusters A and B.
+func (c *Config) Run(ctx context.Context) ([]managementcluster.Difference, error) {
+	if c.A == "" || c.B == "" {
+		return nil, fmt.Errorf("two management clusters are required.\n%w", ErrInvalidFlag)
+	}
+	a, err := c.pull(ctx, c.A)
+	if err != nil {
+		return nil, err
+	}
+	b, err := c.pull(ctx, c.B)
+	if err != nil {
+		return nil, err
+	}
+	differences, err := managementcluster.Compare(a.mc, b.mc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compare %s and %s.\n%w", c.A, c.B, err)
+	}
+	var resourcesA, resourcesB []string
+	for _, r := range a.resources {
+		resourcesA = append(resourcesA, managementcluster.Normalize(a.mc, r))
+	}
+	for _, r := range b.resources {
+		resourcesB = append(resourcesB, managementcluster.Normalize(b.mc, r))
+	}
+	return append(differences, managementcluster.CompareLists(FieldResources, resourcesA, resourcesB)...), nil
+}
+
+func (c *Config) pull(ctx context.Context, cluster string) (*state, error) {
+	log.Debug().Msgf("pulling management cluster %s", cluster)
+	s := &state{
+		mc: &managementcluster.ManagementCluster{},
+	}
+	cmcRepository := c.CMCRepository
+	if !key.Skip(key.RepositoryInstallations, c.Skip) {
+		p := pullinstallations.Config{
+			Cluster:             cluster,
+			Github:              c.Github,
+			InstallationsBranch: c.InstallationsBranch,
+		}
+		installations, err := p.Run(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to pull installations of %s.\n%w", cluster, err)
+		}
+		s.mc.Installations = *installations
+		if installations.CmcRepository != "" {
+			cmcRepository = installations.CmcRepository
+		}
+	}
+	if !key.Skip(key.RepositoryCMC, c.Skip) {
+		p := pullcmc.Config{
+			Cluster:        cluster,
+			Github:         c.Github,
+			CMCRepository:  cmcRepository,
+			CMCBranch:      c.CMCBranch,
+			DisplaySecrets: c.DisplaySecrets,
+		}
+		cmc, err := p.Run(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to pull CMC of %s.\n%w", cluster, err)
+		}
+		s.mc.CMC = *cmc
+
+		repository := github.Repository{
+			Github:       c.Github,
+			Name:         cmcRepository,
+			Organization: key.OrganizationGiantSwarm,
+			Branch:       c.CMCBranch,
+		}
+		file, err := repository.GetFile(ctx, fmt.Sprintf("%s/%s", key.GetCMCPath(cluster), kustomization.KustomizationFile))
+		if err != nil {
+			return nil, err
+		}
+		s.resources, err = kustomization.GetResources(file)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+func GetValidOutputs() []string {
+	return []string{OutputText, OutputJSON}
+}
+
+func Print(w io.Writer, differences []managementcluster.Difference, output string) error {
+	if output == OutputJSON {
+		if differences == nil {
+			differences = []managementcluster.Difference{}
+		}
+		data, err := json.MarshalIndent(differences, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal differences.\n%w", err)
+		}
+		_, err = fmt.Fprintln(w, string(data))
+		return err
+	}
+	if len(differences) == 0 {
+		fmt.Fprintln(w, "no differences")
+		return nil
+	}
+	for _, difference := range differences {
+		fmt.Fprintf(w, "%s: %s | %s\n", difference.Field, difference.A, difference.B)
+	}
+	return nil
+}
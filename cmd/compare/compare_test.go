@@ -0,0 +1,40 @@
+package compare
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/giantswarm/mcli/pkg/github/githubtest"
+	"github.com/giantswarm/mcli/pkg/key"
+)
+
+func TestRun(t *testing.T) {
+	server := githubtest.NewServer()
+	defer server.Close()
+	server.AddBranchRepository(key.OrganizationGiantSwarm, key.RepositoryInstallations, key.InstallationsMainBranch, map[string]string{
+		"gigmac/cluster.yaml": "base: gigmac.gigantic.io\ncodename: gigmac\ncustomer: giantswarm\npipeline: testing\nprovider: capz\n",
+		"gauss/cluster.yaml":  "base: gauss.gigantic.io\ncodename: gauss\ncustomer: giantswarm\npipeline: stable\nprovider: capz\n",
+	})
+
+	c := Config{
+		A:                   "gigmac",
+		B:                   "gauss",
+		Github:              server.Client(),
+		InstallationsBranch: key.InstallationsMainBranch,
+		Skip:                []string{key.RepositoryCMC},
+	}
+	differences, err := c.Run(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var out bytes.Buffer
+	if err := Print(&out, differences, OutputText); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := "installations.pipeline: \"testing\" | \"stable\"\n"
+	if out.String() != expected {
+		t.Fatalf("expected %q, got %q", expected, out.String())
+	}
+}
@@ -0,0 +1,7 @@
+package compare
+
+import (
+	"errors"
+)
+
+var ErrInvalidFlag = errors.New("invalid flag")
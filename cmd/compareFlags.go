@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/mcli/cmd/compare"
+	"github.com/giantswarm/mcli/pkg/key"
+)
+
+var (
+	compareOutput string
+)
+
+func addFlagsCompare() {
+	compareCmd.Flags().StringVarP(&compareOutput, flagOutput, "o", compare.OutputText, fmt.Sprintf("Output format. Valid values: %v", compare.GetValidOutputs()))
+	compareCmd.Flags().StringArrayVarP(&skip, flagSkip, "s", []string{}, fmt.Sprintf("List of repositories to skip. (default: none) Valid values: %s", key.GetValidRepositories()))
+}
+
+func defaultCompare() {
+	if installationsBranch == "" {
+		installationsBranch = key.InstallationsMainBranch
+	}
+	if cmcBranch == "" {
+		cmcBranch = key.CMCMainBranch
+	}
+	if customer == "" {
+		customer = key.OrganizationGiantSwarm
+	}
+	if cmcRepository == "" {
+		cmcRepository = key.GetCMCName(customer)
+	}
+}
+
+func validateCompare(cmd *cobra.Command, args []string) error {
+	if githubToken == "" {
+		return invalidFlagError(flagGithubToken)
+	}
+	for _, repository := range skip {
+		if !key.IsValidRepository(repository) {
+			return fmt.Errorf("invalid repository %s. Valid values: %s:\n%w", repository, key.GetValidRepositories(), ErrInvalidFlag)
+		}
+	}
+	if !slices.Contains(compare.GetValidOutputs(), compareOutput) {
+		return fmt.Errorf("invalid output %s. Valid values: %s:\n%w", compareOutput, compare.GetValidOutputs(), ErrInvalidFlag)
+	}
+	return nil
+}
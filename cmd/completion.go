@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/mcli/cmd/completion"
+	"github.com/giantswarm/mcli/pkg/github"
+	"github.com/giantswarm/mcli/pkg/key"
+)
+
+// registerCompletions adds dynamic completions to the flags. It has to run after all flags are added.
+func registerCompletions() {
+	completions := []struct {
+		cmd  *cobra.Command
+		flag string
+		fn   cobra.CompletionFunc
+	}{
+		{rootCmd, flagCluster, completeClusters},
+		{rootCmd, flagCustomer, completeCustomers},
+		{rootCmd, flagCMCRepository, completeCMCRepositories},
+		{pushCmd, flagProvider, completeStatic(key.GetValidProviders())},
+		{initCmd, flagProvider, completeStatic(key.GetValidProviders())},
+		{pushCmd, flagSkip, completeStatic(key.GetValidRepositories())},
+		{pullCmd, flagSkip, completeStatic(key.GetValidRepositories())},
+		{deleteCmd, flagSkip, completeStatic(key.GetValidRepositories())},
+		{historyCmd, flagSkip, completeStatic(key.GetValidRepositories())},
+		{revertCmd, flagSkip, completeStatic(key.GetValidRepositories())},
+		{compareCmd, flagSkip, completeStatic(key.GetValidRepositories())},
+		{validateCmd, flagSkip, completeStatic(key.GetValidRepositories())},
+		{cloneCmd, flagFrom, completeClusters},
+		{migrateCmd, flagToCMCRepository, completeCMCRepositories},
+	}
+	for _, c := range completions {
+		if err := c.cmd.RegisterFlagCompletionFunc(c.flag, c.fn); err != nil {
+			panic(err)
+		}
+	}
+	compareCmd.ValidArgsFunction = completeClusters
+}
+
+func completeClusters(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return completeDynamic(toComplete, func(ctx context.Context, c *completion.Config) ([]string, error) {
+		return c.GetClusters(ctx)
+	})
+}
+
+func completeCustomers(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return completeDynamic(toComplete, func(ctx context.Context, c *completion.Config) ([]string, error) {
+		return c.GetCustomers(ctx)
+	})
+}
+
+func completeCMCRepositories(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return completeDynamic(toComplete, func(ctx context.Context, c *completion.Config) ([]string, error) {
+		return c.GetCMCRepositories(ctx)
+	})
+}
+
+func completeStatic(values []string) cobra.CompletionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return completion.Filter(values, toComplete), cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// completeDynamic looks up values on GitHub. Nothing is completed without a token or if the lookup fails.
+func completeDynamic(toComplete string, get func(context.Context, *completion.Config) ([]string, error)) ([]string, cobra.ShellCompDirective) {
+	if githubToken == "" {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	c := &completion.Config{
+		Github: github.New(github.Config{
+			Token: githubToken,
+		}),
+		Cache: &completion.Cache{
+			Dir: completion.GetDefaultCacheDir(),
+			TTL: completion.DefaultTTL,
+		},
+	}
+	values, err := get(context.Background(), c)
+	if err != nil {
+		cobra.CompDebugln(err.Error(), true)
+		return nil, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveError
+	}
+	return completion.Filter(values, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
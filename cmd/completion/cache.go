@@ -0,0 +1,90 @@
+package completion
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Cache keeps completion values on disk for a short time so that completion does not query GitHub on every key press.
+type Cache struct {
+	// Dir holds one file per cached value. The cache is disabled if it is empty.
+	Dir string
+	TTL time.Duration
+	// Now defaults to time.Now.
+	Now func() time.Time
+}
+
+type cacheEntry struct {
+	Time   time.Time `json:"time"`
+	Values []string  `json:"values"`
+}
+
+// GetDefaultCacheDir returns the completion cache in the cache directory of the user.
+func GetDefaultCacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "mcli", "completion")
+}
+
+// Get returns the cached values or fetches and caches them if they are missing or expired.
+// Failures to read or write the cache are not fatal.
+func (c *Cache) Get(name string, fetch func() ([]string, error)) ([]string, error) {
+	if c == nil || c.Dir == "" {
+		return fetch()
+	}
+	file := filepath.Join(c.Dir, name+".json")
+	now := c.now()
+	if values, ok := c.read(file, now); ok {
+		return values, nil
+	}
+	values, err := fetch()
+	if err != nil {
+		return nil, err
+	}
+	c.write(file, cacheEntry{Time: now, Values: values})
+	return values, nil
+}
+
+func (c *Cache) read(file string, now time.Time) ([]string, bool) {
+	data, err := os.ReadFile(file) // #nosec G304
+	if err != nil {
+		return nil, false
+	}
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		log.Debug().Msgf("ignoring invalid completion cache %s.\n%s", file, err)
+		return nil, false
+	}
+	if now.Sub(entry.Time) > c.TTL || now.Before(entry.Time) {
+		return nil, false
+	}
+	return entry.Values, true
+}
+
+func (c *Cache) write(file string, entry cacheEntry) {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		log.Debug().Msgf("failed to marshal completion cache.\n%s", err)
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0700); err != nil {
+		log.Debug().Msgf("failed to create completion cache directory.\n%s", err)
+		return
+	}
+	if err := os.WriteFile(file, data, 0600); err != nil {
+		log.Debug().Msgf("failed to write completion cache %s.\n%s", file, err)
+	}
+}
+
+func (c *Cache) now() time.Time {
+	if c.Now != nil {
+		return c.Now()
+	}
+	return time.Now()
+}
@@ -0,0 +1,96 @@
+package completion
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/giantswarm/mcli/pkg/github"
+	"github.com/giantswarm/mcli/pkg/key"
+)
+
+const (
+	DefaultTTL = 5 * time.Minute
+)
+
+type Config struct {
+	Github *github.Github
+	Cache  *Cache
+}
+
+// GetClusters returns the clusters of the installations repository.
+func (c *Config) GetClusters(ctx context.Context) ([]string, error) {
+	return c.Cache.Get("clusters", func() ([]string, error) {
+		repository := github.Repository{
+			Github:       c.Github,
+			Name:         key.RepositoryInstallations,
+			Organization: key.OrganizationGiantSwarm,
+			Branch:       key.InstallationsMainBranch,
+		}
+		files, err := repository.GetFileNames(ctx)
+		if err != nil {
+			return nil, err
+		}
+		var clusters []string
+		for _, file := range files {
+			if cluster, ok := key.GetClusterFromInstallationsPath(file); ok {
+				clusters = append(clusters, cluster)
+			}
+		}
+		sort.Strings(clusters)
+		return clusters, nil
+	})
+}
+
+// GetCMCRepositories returns the repositories of the organization that follow the naming of CMC repositories.
+func (c *Config) GetCMCRepositories(ctx context.Context) ([]string, error) {
+	return c.Cache.Get("cmc-repositories", func() ([]string, error) {
+		names, err := c.Github.ListRepositories(ctx, key.OrganizationGiantSwarm)
+		if err != nil {
+			return nil, err
+		}
+		var repositories []string
+		for _, name := range names {
+			if _, ok := getCustomer(name); ok {
+				repositories = append(repositories, name)
+			}
+		}
+		sort.Strings(repositories)
+		return repositories, nil
+	})
+}
+
+// GetCustomers returns the customers which have a CMC repository.
+func (c *Config) GetCustomers(ctx context.Context) ([]string, error) {
+	repositories, err := c.GetCMCRepositories(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var customers []string
+	for _, repository := range repositories {
+		customer, _ := getCustomer(repository)
+		customers = append(customers, customer)
+	}
+	return customers, nil
+}
+
+// Filter returns the values starting with the prefix.
+func Filter(values []string, prefix string) []string {
+	var filtered []string
+	for _, value := range values {
+		if strings.HasPrefix(value, prefix) {
+			filtered = append(filtered, value)
+		}
+	}
+	return filtered
+}
+
+// getCustomer returns the customer of a repository named like key.GetCMCName.
+func getCustomer(repository string) (string, bool) {
+	customer, ok := strings.CutSuffix(repository, key.GetCMCName(""))
+	if !ok || customer == "" || repository == key.CMCTemplateRepository {
+		return "", false
+	}
+	return customer, true
+}
@@ -0,0 +1,133 @@
+package completion
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/giantswarm/mcli/pkg/github/githubtest"
+	"github.com/giantswarm/mcli/pkg/key"
+)
+
+func TestConfig(t *testing.T) {
+	server := githubtest.NewServer()
+	defer server.Close()
+	server.AddBranchRepository(key.OrganizationGiantSwarm, key.RepositoryInstallations, key.InstallationsMainBranch, map[string]string{
+		"README.md":             "installations\n",
+		"golem/cluster.yaml":    "codename: golem\n",
+		"gigmac/cluster.yaml":   "codename: gigmac\n",
+		"gigmac/other.yaml":     "other\n",
+		"docs/cluster.md":       "docs\n",
+		"nested/a/cluster.yaml": "codename: nested\n",
+	})
+	for _, name := range []string{"giantswarm-management-clusters", "acme-management-clusters", key.CMCTemplateRepository, "mcli"} {
+		server.AddRepository(key.OrganizationGiantSwarm, name, map[string]string{"README.md": name})
+	}
+	c := Config{
+		Github: server.Client(),
+		Cache: &Cache{
+			Dir: t.TempDir(),
+			TTL: DefaultTTL,
+		},
+	}
+	ctx := context.Background()
+
+	clusters, err := c.GetClusters(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := []string{"gigmac", "golem"}; !reflect.DeepEqual(clusters, expected) {
+		t.Fatalf("expected clusters %v, got %v", expected, clusters)
+	}
+	repositories, err := c.GetCMCRepositories(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := []string{"acme-management-clusters", "giantswarm-management-clusters"}; !reflect.DeepEqual(repositories, expected) {
+		t.Fatalf("expected repositories %v, got %v", expected, repositories)
+	}
+	customers, err := c.GetCustomers(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := []string{"acme", "giantswarm"}; !reflect.DeepEqual(customers, expected) {
+		t.Fatalf("expected customers %v, got %v", expected, customers)
+	}
+	if filtered := Filter(customers, "gi"); !reflect.DeepEqual(filtered, []string{"giantswarm"}) {
+		t.Fatalf("expected filtered customers, got %v", filtered)
+	}
+
+	// cached values are used while the server is gone
+	server.Close()
+	cached, err := c.GetClusters(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(cached, clusters) {
+		t.Fatalf("expected cached clusters %v, got %v", clusters, cached)
+	}
+}
+
+func TestCache(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	testCases := []struct {
+		name    string
+		dir     bool
+		elapsed time.Duration
+
+		expectFetches int
+	}{
+		{
+			name:    "case 0: fresh cache",
+			dir:     true,
+			elapsed: time.Minute,
+
+			expectFetches: 1,
+		},
+		{
+			name:    "case 1: expired cache",
+			dir:     true,
+			elapsed: 2 * DefaultTTL,
+
+			expectFetches: 2,
+		},
+		{
+			name:    "case 2: disabled cache",
+			elapsed: time.Minute,
+
+			expectFetches: 2,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			current := now
+			c := &Cache{
+				TTL: DefaultTTL,
+				Now: func() time.Time { return current },
+			}
+			if tc.dir {
+				c.Dir = t.TempDir()
+			}
+			fetches := 0
+			fetch := func() ([]string, error) {
+				fetches++
+				return []string{"gigmac"}, nil
+			}
+			for _, elapsed := range []time.Duration{0, tc.elapsed} {
+				current = now.Add(elapsed)
+				values, err := c.Get("clusters", fetch)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if !reflect.DeepEqual(values, []string{"gigmac"}) {
+					t.Fatalf("unexpected values %v", values)
+				}
+			}
+			if fetches != tc.expectFetches {
+				t.Fatalf("expected %d fetches, got %d", tc.expectFetches, fetches)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/giantswarm/mcli/pkg/config"
+	"github.com/giantswarm/mcli/pkg/key"
+)
+
+// settings which can be set by profiles
+var profileSettings = []config.Setting{
+	{Flag: flagCMCRepository, Env: envCMCRepository, Profile: func(p config.Profile) string { return p.CMCRepository }},
+	{Flag: flagCustomer, Env: envCustomer, Profile: func(p config.Profile) string { return p.Customer }},
+	{Flag: flagProvider, Env: envProvider, Profile: func(p config.Profile) string { return p.Provider }},
+	{Flag: flagClusterAppCatalog, Env: envClusterAppCatalog, Profile: func(p config.Profile) string { return p.ClusterAppCatalog }},
+	{Flag: flagClusterAppVersion, Env: envClusterAppVersion, Profile: func(p config.Profile) string { return p.ClusterAppVersion }},
+	{Flag: flagDefaultAppsCatalog, Env: envDefaultAppsCatalog, Profile: func(p config.Profile) string { return p.DefaultAppsCatalog }},
+	{Flag: flagDefaultAppsVersion, Env: envDefaultAppsVersion, Profile: func(p config.Profile) string { return p.DefaultAppsVersion }},
+	{Flag: flagCMCBranch, Env: envCMCBranch, Profile: func(p config.Profile) string { return p.CMCBranch }},
+	{Flag: flagInstallationsBranch, Env: envInstallationsBranch, Profile: func(p config.Profile) string { return p.InstallationsBranch }},
+	{Flag: flagSecretFolder, Env: envSecretFolder, Profile: func(p config.Profile) string { return p.SecretFolder }},
+}
+
+// configCmd represents the config command
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Shows the mcli configuration",
+}
+
+// configViewCmd represents the config view command
+var configViewCmd = &cobra.Command{
+	Use:   "view",
+	Short: "Shows the effective settings and their source",
+	Long: `Shows the effective settings of push and where they come from. Settings are taken
+from flags, environment variables, the selected profile and defaults in that order.
+Profiles are read from the user configuration file and a .mcli.yaml file in the
+current directory, which overrides the user configuration. For example:
+
+mcli config view
+
+mcli config view --profile=acme`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c, err := loadConfig()
+		if err != nil {
+			return err
+		}
+		name, profile, err := c.GetProfile(profileName)
+		if err != nil {
+			return err
+		}
+		view := config.View{
+			Profile:  name,
+			Profiles: c.GetProfileNames(),
+		}
+		for _, file := range getConfigFiles() {
+			if _, err := os.Stat(file); err == nil {
+				view.Files = append(view.Files, file)
+			}
+		}
+		view.Values = config.ResolveAll(getPushFlags(cmd), profileSettings, profile, cluster)
+		data, err := key.GetData(view)
+		if err != nil {
+			return err
+		}
+		_, err = cmd.OutOrStdout().Write(data)
+		return err
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configViewCmd)
+}
+
+// getPushFlags returns the flags of the command and those of push which the command does not define,
+// so settings are resolved against the flags and defaults push would use.
+func getPushFlags(cmd *cobra.Command) *pflag.FlagSet {
+	flags := pflag.NewFlagSet(pushCmd.Name(), pflag.ContinueOnError)
+	flags.AddFlagSet(cmd.Flags())
+	flags.AddFlagSet(pushCmd.LocalFlags())
+	return flags
+}
+
+func getConfigFiles() []string {
+	return []string{configFile, config.LocalFile}
+}
+
+func loadConfig() (*config.Config, error) {
+	return config.Load(getConfigFiles()...)
+}
+
+// applyProfile sets flags which are not set otherwise from the selected profile.
+func applyProfile(cmd *cobra.Command) error {
+	c, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	_, profile, err := c.GetProfile(profileName)
+	if err != nil {
+		return err
+	}
+	return config.Apply(cmd.Flags(), profileSettings, profile, cluster)
+}
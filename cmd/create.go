@@ -38,11 +38,11 @@ mcli create cmc --customer=gigamac`,
 			CMCBranch:     cmcBranch,
 			Customer:      customer,
 		}
-		_, err = c.Run(ctx)
+		summary, err := c.Run(ctx)
 		if err != nil {
 			return fmt.Errorf("failed to create CMC repository.\n%w", err)
 		}
-		return nil
+		return writeAudit(ctx, cmd, client, cmcRepository, summary)
 	},
 }
 
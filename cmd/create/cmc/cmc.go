@@ -10,6 +10,7 @@ import (
 
 	"github.com/giantswarm/mcli/pkg/github"
 	"github.com/giantswarm/mcli/pkg/key"
+	"github.com/giantswarm/mcli/pkg/managementcluster"
 	"github.com/giantswarm/mcli/pkg/repositories"
 )
 
@@ -20,6 +21,9 @@ const (
 	customerKey            = "CUSTOMER_CODENAME"
 )
 
+// repositoryReadyDelay is the time to wait for a newly generated repository to be ready.
+var repositoryReadyDelay = 3 * time.Second
+
 type Config struct {
 	Github        *github.Github
 	CMCRepository string
@@ -27,50 +31,64 @@ type Config struct {
 	CMCBranch     string
 }
 
-func (c *Config) Run(ctx context.Context) (*github.Repository, error) {
+// Run returns a summary of the changes to the CMC repository and the ownership file.
+func (c *Config) Run(ctx context.Context) (*managementcluster.Summary, error) {
 
 	// Create customer repository
-	cmc, err := c.createCMC(ctx)
+	cmc, created, err := c.createCMC(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create CMC repository.\n%w", err)
 	}
 
 	// Add custom changes on top of repository template
-	if err := c.customizeMC(ctx, cmc); err != nil {
+	cmcResult, err := c.customizeMC(ctx, cmc)
+	if err != nil {
 		return nil, fmt.Errorf("failed to customize CMC repository.\n%w", err)
 	}
+	if created {
+		cmcResult.Status = github.StatusCreated
+	}
 
 	// Setup branch protection rules
 	if err := c.setupBranchProtection(ctx, cmc); err != nil {
 		return nil, fmt.Errorf("failed to setup branch protection rules.\n%w", err)
 	}
 
-	if err := c.createOwnershipPR(ctx); err != nil {
+	ownershipResult, err := c.createOwnershipPR(ctx)
+	if err != nil {
 		return nil, fmt.Errorf("failed to create ownership PR.\n%w", err)
 	}
 
-	return cmc, nil
+	return &managementcluster.Summary{
+		Repositories: []*managementcluster.RepositorySummary{
+			{Result: *cmcResult},
+			{Result: *ownershipResult},
+		},
+	}, nil
 }
 
-func (c *Config) createCMC(ctx context.Context) (*github.Repository, error) {
+// createCMC returns the CMC repository and whether it was created.
+func (c *Config) createCMC(ctx context.Context) (*github.Repository, bool, error) {
 	cmcRepository := github.Repository{
 		Github:       c.Github,
 		Name:         c.CMCRepository,
 		Organization: key.OrganizationGiantSwarm,
 		Branch:       c.CMCBranch,
 	}
+	created := false
 	if err := cmcRepository.CheckRepository(ctx); err != nil {
 		if !github.IsNotFound(err) {
-			return nil, fmt.Errorf("failed to check CMC repository %s.\n%w", c.CMCRepository, err)
+			return nil, false, fmt.Errorf("failed to check CMC repository %s.\n%w", c.CMCRepository, err)
 		} else {
 			// CMC repository does not exist, create it
 			description := fmt.Sprintf("Management Clusters configuration for %s", c.Customer)
 			log.Debug().Msgf("creating CMC repository %s", c.CMCRepository)
 			if err := cmcRepository.CreatePrivateRepo(ctx, description, key.CMCTemplateRepository); err != nil {
-				return nil, fmt.Errorf("failed to create CMC repository %s.\n%w", c.CMCRepository, err)
+				return nil, false, fmt.Errorf("failed to create CMC repository %s.\n%w", c.CMCRepository, err)
 			}
 			log.Debug().Msgf("waiting for CMC repository %s to be ready", c.CMCRepository)
-			time.Sleep(3 * time.Second)
+			time.Sleep(repositoryReadyDelay)
+			created = true
 		}
 	} else {
 		// CMC repository already exists, nothing to do
@@ -78,15 +96,15 @@ func (c *Config) createCMC(ctx context.Context) (*github.Repository, error) {
 	}
 
 	if err := cmcRepository.AddCollaborator(ctx, key.Employees, "admin"); err != nil {
-		return nil, fmt.Errorf("failed to add collaborator %s to CMC repository %s.\n%w", key.Employees, c.CMCRepository, err)
+		return nil, false, fmt.Errorf("failed to add collaborator %s to CMC repository %s.\n%w", key.Employees, c.CMCRepository, err)
 	}
 	if err := cmcRepository.AddCollaborator(ctx, key.Bots, "push"); err != nil {
-		return nil, fmt.Errorf("failed to add collaborator %s to CMC repository %s.\n%w", key.Bots, c.CMCRepository, err)
+		return nil, false, fmt.Errorf("failed to add collaborator %s to CMC repository %s.\n%w", key.Bots, c.CMCRepository, err)
 	}
-	return &cmcRepository, nil
+	return &cmcRepository, created, nil
 }
 
-func (c *Config) customizeMC(ctx context.Context, cmcRepository *github.Repository) error {
+func (c *Config) customizeMC(ctx context.Context, cmcRepository *github.Repository) (*github.Result, error) {
 	// Add custom changes on top of repository template
 	err := cmcRepository.CheckBranch(ctx)
 	if err != nil {
@@ -94,40 +112,45 @@ func (c *Config) customizeMC(ctx context.Context, cmcRepository *github.Reposito
 			log.Debug().Msgf("CMC branch %s not found, creating it", c.CMCBranch)
 			err = cmcRepository.CreateBranch(ctx, key.CMCMainBranch)
 			if err != nil {
-				return fmt.Errorf("failed to create CMC branch %s.\n%w", c.CMCBranch, err)
+				return nil, fmt.Errorf("failed to create CMC branch %s.\n%w", c.CMCBranch, err)
 			}
 		} else {
-			return fmt.Errorf("failed to check CMC branch %s.\n%w", c.CMCBranch, err)
+			return nil, fmt.Errorf("failed to check CMC branch %s.\n%w", c.CMCBranch, err)
 		}
 	}
+	result := cmcRepository.NewResult()
 	// Get kustomization and makefile from repository
 	kustomization, err := cmcRepository.GetFile(ctx, kustomizationPostBuild)
 	if err != nil {
-		return fmt.Errorf("failed to get kustomization file %s.\n%w", kustomizationPostBuild, err)
+		return nil, fmt.Errorf("failed to get kustomization file %s.\n%w", kustomizationPostBuild, err)
 	}
 	makefile, err := cmcRepository.GetFile(ctx, makeFile)
 	if err != nil {
-		return fmt.Errorf("failed to get makefile %s.\n%w", makeFile, err)
+		return nil, fmt.Errorf("failed to get makefile %s.\n%w", makeFile, err)
 	}
 
 	// update the kustomization if customer key is present
 	if strings.Contains(kustomization, customerKey) {
 		log.Debug().Msgf("Updating %s with customer codename %s", kustomizationPostBuild, c.Customer)
 		kustomization = strings.ReplaceAll(kustomization, customerKey, c.Customer)
-		if err := cmcRepository.CreateFile(ctx, []byte(kustomization), kustomizationPostBuild); err != nil {
-			return fmt.Errorf("failed to update kustomization file %s.\n%w", kustomizationPostBuild, err)
+		written, err := cmcRepository.CreateFile(ctx, []byte(kustomization), kustomizationPostBuild)
+		if err != nil {
+			return nil, fmt.Errorf("failed to update kustomization file %s.\n%w", kustomizationPostBuild, err)
 		}
+		result.Merge(written)
 	}
 
 	// update the makefile if customer key is present
 	if strings.Contains(makefile, customerKey) {
 		log.Debug().Msgf("Updating %s with customer codename %s", makeFile, c.Customer)
 		makefile = strings.ReplaceAll(makefile, customerKey, c.Customer)
-		if err := cmcRepository.CreateFile(ctx, []byte(makefile), makeFile); err != nil {
-			return fmt.Errorf("failed to update makefile %s.\n%w", makeFile, err)
+		written, err := cmcRepository.CreateFile(ctx, []byte(makefile), makeFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to update makefile %s.\n%w", makeFile, err)
 		}
+		result.Merge(written)
 	}
-	return nil
+	return result, nil
 }
 
 func (c *Config) setupBranchProtection(ctx context.Context, cmcRepository *github.Repository) error {
@@ -139,7 +162,7 @@ func (c *Config) setupBranchProtection(ctx context.Context, cmcRepository *githu
 	return nil
 }
 
-func (c *Config) createOwnershipPR(ctx context.Context) error {
+func (c *Config) createOwnershipPR(ctx context.Context) (*github.Result, error) {
 	githubRepository := github.Repository{
 		Github:       c.Github,
 		Name:         key.RepositoryGithub,
@@ -152,27 +175,28 @@ func (c *Config) createOwnershipPR(ctx context.Context) error {
 			log.Debug().Msgf("Ownership branch %s not found, creating it", githubRepository.Branch)
 			err = githubRepository.CreateBranch(ctx, key.CMCMainBranch)
 			if err != nil {
-				return fmt.Errorf("failed to create ownership branch %s.\n%w", githubRepository.Branch, err)
+				return nil, fmt.Errorf("failed to create ownership branch %s.\n%w", githubRepository.Branch, err)
 			}
 		} else {
-			return fmt.Errorf("failed to check ownership branch %s.\n%w", githubRepository.Branch, err)
+			return nil, fmt.Errorf("failed to check ownership branch %s.\n%w", githubRepository.Branch, err)
 		}
 	}
+	result := githubRepository.NewResult()
 	// get ownership file from repository
 	log.Debug().Msgf("getting ownership file %s from repository %s", ownershipFile, key.RepositoryGithub)
 	file, err := githubRepository.GetFile(ctx, ownershipFile)
 	if err != nil {
-		return fmt.Errorf("failed to get ownership file %s.\n%w", ownershipFile, err)
+		return nil, fmt.Errorf("failed to get ownership file %s.\n%w", ownershipFile, err)
 	}
 
 	// check if an entry for the cmc repository already exists
 	if strings.Contains(file, c.CMCRepository) {
 		log.Debug().Msgf("CMC repository %s already exists in ownership file", c.CMCRepository)
-		return nil
+		return result, nil
 	}
 	repos, err := repositories.GetRepos([]byte(file))
 	if err != nil {
-		return fmt.Errorf("failed to get repositories from ownership file.\n%w", err)
+		return nil, fmt.Errorf("failed to get repositories from ownership file.\n%w", err)
 	}
 	repository := repositories.Repo{
 		Name:          c.CMCRepository,
@@ -186,28 +210,30 @@ func (c *Config) createOwnershipPR(ctx context.Context) error {
 	repos = repositories.SortReposAlphabetically(repos)
 	data, err := repositories.GetData(repos)
 	if err != nil {
-		return fmt.Errorf("failed to marshal repositories.\n%w", err)
+		return nil, fmt.Errorf("failed to marshal repositories.\n%w", err)
 	}
 
 	log.Debug().Msgf("updating ownership file %s with CMC repository %s", ownershipFile, c.CMCRepository)
-	if err := githubRepository.CreateFile(ctx, data, ownershipFile); err != nil {
-		return fmt.Errorf("failed to update ownership file %s.\n%w", ownershipFile, err)
+	written, err := githubRepository.CreateFile(ctx, data, ownershipFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update ownership file %s.\n%w", ownershipFile, err)
 	}
+	result.Merge(written)
 
 	title := fmt.Sprintf("chore: Add %s to team honeybadger", c.CMCRepository)
 	exists, err := githubRepository.PullRequestExists(ctx, title)
 	if err != nil {
-		return fmt.Errorf("failed to check for an existing ownership PR.\n%w", err)
+		return nil, fmt.Errorf("failed to check for an existing ownership PR.\n%w", err)
 	}
 	if exists {
 		log.Debug().Msgf("ownership PR %q already exists", title)
-		return nil
+		return result, nil
 	}
 
 	log.Debug().Msgf("creating PR for ownership file %s", ownershipFile)
 	// create PR
 	if err := githubRepository.CreatePullRequest(ctx, title, key.CMCMainBranch); err != nil {
-		return fmt.Errorf("failed to create PR for ownership file.\n%w", err)
+		return nil, fmt.Errorf("failed to create PR for ownership file.\n%w", err)
 	}
-	return nil
+	return result, nil
 }
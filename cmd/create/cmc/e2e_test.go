@@ -0,0 +1,92 @@
+package createcmc
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/giantswarm/mcli/pkg/github"
+	"github.com/giantswarm/mcli/pkg/github/githubtest"
+	"github.com/giantswarm/mcli/pkg/key"
+)
+
+func TestCreateCMCEndToEnd(t *testing.T) {
+	repositoryReadyDelay = 0
+
+	var testCases = []struct {
+		name   string
+		exists bool
+
+		expectCMCStatus string
+	}{
+		{
+			name:   "case 0: new repository",
+			exists: false,
+
+			expectCMCStatus: github.StatusCreated,
+		},
+		{
+			name:   "case 1: existing repository",
+			exists: true,
+
+			expectCMCStatus: github.StatusUpdated,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := githubtest.NewServer()
+			defer server.Close()
+			template := map[string]string{
+				kustomizationPostBuild: "customer: CUSTOMER_CODENAME\n",
+				makeFile:               "CUSTOMER ?= CUSTOMER_CODENAME\n",
+			}
+			server.AddRepository(key.OrganizationGiantSwarm, key.CMCTemplateRepository, template)
+			server.AddRepository(key.OrganizationGiantSwarm, key.RepositoryGithub, map[string]string{
+				ownershipFile: "- name: b-repository\n  componentType: configuration\n",
+			})
+			if tc.exists {
+				server.AddRepository(key.OrganizationGiantSwarm, "test-management-clusters", template)
+			}
+
+			c := Config{
+				Github:        server.Client(),
+				CMCRepository: "test-management-clusters",
+				Customer:      "test",
+				CMCBranch:     key.CMCMainBranch,
+			}
+			ctx := context.Background()
+			for i, expectStatus := range []string{tc.expectCMCStatus, github.StatusUnchanged} {
+				summary, err := c.Run(ctx)
+				if err != nil {
+					t.Fatalf("run %d: unexpected error: %v", i, err)
+				}
+				if len(summary.Repositories) != 2 || summary.Repositories[0].Status != expectStatus {
+					t.Fatalf("run %d: expected CMC repository %s, got %+v", i, expectStatus, summary.Repositories)
+				}
+			}
+
+			files := server.Files(key.OrganizationGiantSwarm, c.CMCRepository, key.CMCMainBranch)
+			if files[kustomizationPostBuild] != "customer: test\n" || files[makeFile] != "CUSTOMER ?= test\n" {
+				t.Fatalf("expected customized files, got %v", files)
+			}
+			expectTeams := map[string]string{key.Employees: "admin", key.Bots: "push"}
+			if teams := server.Teams(key.OrganizationGiantSwarm, c.CMCRepository); !reflect.DeepEqual(teams, expectTeams) {
+				t.Fatalf("expected teams %v, got %v", expectTeams, teams)
+			}
+			if protections := server.BranchProtections(key.OrganizationGiantSwarm, c.CMCRepository); len(protections) == 0 || protections[0] != key.CMCMainBranch {
+				t.Fatalf("expected branch protection on %s, got %v", key.CMCMainBranch, protections)
+			}
+
+			pulls := server.PullRequests(key.OrganizationGiantSwarm, key.RepositoryGithub)
+			if len(pulls) != 1 || pulls[0].Base != key.CMCMainBranch {
+				t.Fatalf("expected one ownership PR, got %v", pulls)
+			}
+			ownership := server.Files(key.OrganizationGiantSwarm, key.RepositoryGithub, pulls[0].Head)[ownershipFile]
+			if strings.Index(ownership, "b-repository") > strings.Index(ownership, c.CMCRepository) || strings.Count(ownership, c.CMCRepository) != 1 {
+				t.Fatalf("expected %s to be added to the ownership file once, got\n%s", c.CMCRepository, ownership)
+			}
+		})
+	}
+}
@@ -0,0 +1,286 @@
+package decommission
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/giantswarm/mcli/pkg/github"
+	"github.com/giantswarm/mcli/pkg/key"
+	"github.com/giantswarm/mcli/pkg/managementcluster/cmc"
+	"github.com/giantswarm/mcli/pkg/managementcluster/cmc/kustomization"
+	"github.com/giantswarm/mcli/pkg/managementcluster/cmc/sopsfile"
+)
+
+type Config struct {
+	Cluster             string
+	CMCRepository       string
+	CMCBranch           string
+	InstallationsBranch string
+	Github              *github.Github
+	Skip                []string
+	// Confirmation has to match the cluster name. If it is empty, it is read from In.
+	Confirmation string
+	In           io.Reader
+	Out          io.Writer
+}
+
+// Plan lists what is removed from the repositories.
+type Plan struct {
+	CMCFiles           []string
+	SopsRule           bool
+	InstallationsFiles []string
+}
+
+func (p *Plan) IsEmpty() bool {
+	return len(p.CMCFiles) == 0 && !p.SopsRule && len(p.InstallationsFiles) == 0
+}
+
+func (c *Config) Run(ctx context.Context) ([]*github.Result, error) {
+	plan, err := c.GetPlan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if plan.IsEmpty() {
+		return nil, fmt.Errorf("management cluster %s\n%w", c.Cluster, ErrNotFound)
+	}
+	c.printPlan(plan)
+	if err := c.confirm(); err != nil {
+		return nil, err
+	}
+
+	var results []*github.Result
+	if len(plan.CMCFiles) > 0 || plan.SopsRule {
+		result, err := c.deleteCMC(ctx, plan)
+		if err != nil {
+			return nil, fmt.Errorf("failed to delete CMC entry.\n%w", err)
+		}
+		results = append(results, result)
+	}
+	if len(plan.InstallationsFiles) > 0 {
+		result, err := c.deleteInstallations(ctx, plan)
+		if err != nil {
+			return nil, fmt.Errorf("failed to delete installations entry.\n%w", err)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// GetPlan collects the files of the cluster. It fails if other files of the CMC repository still reference the cluster.
+func (c *Config) GetPlan(ctx context.Context) (*Plan, error) {
+	plan := &Plan{}
+	if !key.Skip(key.RepositoryCMC, c.Skip) {
+		cmcRepository := c.getCMCRepository(key.CMCMainBranch)
+		files, err := cmcRepository.GetFileNames(ctx)
+		if err != nil {
+			return nil, err
+		}
+		references, err := c.getReferences(ctx, cmcRepository, files)
+		if err != nil {
+			return nil, err
+		}
+		if len(references) > 0 {
+			return nil, fmt.Errorf("%s is referenced by %s.\n%w", key.GetCMCPath(c.Cluster), strings.Join(references, ", "), ErrReferenced)
+		}
+		for _, file := range files {
+			if strings.HasPrefix(file, key.GetCMCPath(c.Cluster)+"/") {
+				plan.CMCFiles = append(plan.CMCFiles, file)
+			}
+		}
+		sopsFile, err := cmcRepository.GetFile(ctx, cmc.SopsFile)
+		if err != nil && !github.IsNotFound(err) {
+			return nil, err
+		}
+		if err == nil {
+			_, plan.SopsRule, err = sopsfile.RemoveCluster(sopsFile, c.Cluster)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	if !key.Skip(key.RepositoryInstallations, c.Skip) {
+		installationsRepository := c.getInstallationsRepository(key.InstallationsMainBranch)
+		files, err := installationsRepository.GetFileNames(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			if strings.HasPrefix(file, c.Cluster+"/") {
+				plan.InstallationsFiles = append(plan.InstallationsFiles, file)
+			}
+		}
+	}
+	return plan, nil
+}
+
+// getReferences returns the files outside of the cluster directory that contain its path.
+// Only kustomizations are read in the directories of other clusters since their other files are encrypted.
+func (c *Config) getReferences(ctx context.Context, repository github.Repository, files []string) ([]string, error) {
+	clusterPath := key.GetCMCPath(c.Cluster)
+	var references []string
+	for _, file := range files {
+		if strings.HasPrefix(file, clusterPath+"/") || file == cmc.SopsFile || !isYAML(file) {
+			continue
+		}
+		if strings.HasPrefix(file, key.CMCClustersPath+"/") && path.Base(file) != kustomization.KustomizationFile {
+			continue
+		}
+		content, err := repository.GetFile(ctx, file)
+		if err != nil {
+			return nil, err
+		}
+		if containsPath(content, clusterPath) {
+			log.Debug().Msgf("%s references %s", file, clusterPath)
+			references = append(references, file)
+		}
+	}
+	return references, nil
+}
+
+func (c *Config) deleteCMC(ctx context.Context, plan *Plan) (*github.Result, error) {
+	cmcRepository := c.getCMCRepository(c.CMCBranch)
+	if err := prepareBranch(ctx, cmcRepository, key.CMCMainBranch); err != nil {
+		return nil, err
+	}
+	result, err := cmcRepository.DeleteFiles(ctx, plan.CMCFiles, fmt.Sprintf("delete %s", key.GetCMCPath(c.Cluster)))
+	if err != nil {
+		return nil, err
+	}
+	if plan.SopsRule {
+		sopsFile, err := cmcRepository.GetFile(ctx, cmc.SopsFile)
+		if err != nil {
+			return nil, err
+		}
+		data, removed, err := sopsfile.RemoveCluster(sopsFile, c.Cluster)
+		if err != nil {
+			return nil, err
+		}
+		if removed {
+			sopsResult, err := cmcRepository.CreateFile(ctx, []byte(data), cmc.SopsFile)
+			if err != nil {
+				return nil, err
+			}
+			result.Merge(sopsResult)
+		}
+	}
+	if err := c.createPullRequest(ctx, cmcRepository, key.CMCMainBranch); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c *Config) deleteInstallations(ctx context.Context, plan *Plan) (*github.Result, error) {
+	installationsRepository := c.getInstallationsRepository(c.InstallationsBranch)
+	if err := prepareBranch(ctx, installationsRepository, key.InstallationsMainBranch); err != nil {
+		return nil, err
+	}
+	result, err := installationsRepository.DeleteFiles(ctx, plan.InstallationsFiles, fmt.Sprintf("delete %s", c.Cluster))
+	if err != nil {
+		return nil, err
+	}
+	if err := c.createPullRequest(ctx, installationsRepository, key.InstallationsMainBranch); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c *Config) createPullRequest(ctx context.Context, repository github.Repository, base string) error {
+	title := fmt.Sprintf("Delete management cluster %s", c.Cluster)
+	exists, err := repository.PullRequestExists(ctx, title)
+	if err != nil {
+		return err
+	}
+	if exists {
+		log.Debug().Msgf("pull request %q already exists", title)
+		return nil
+	}
+	return repository.CreatePullRequest(ctx, title, base)
+}
+
+func (c *Config) confirm() error {
+	confirmation := c.Confirmation
+	if confirmation == "" {
+		fmt.Fprintf(c.Out, "Type the name of the management cluster to confirm: ")
+		line, err := bufio.NewReader(c.In).ReadString('\n')
+		if err != nil && line == "" {
+			return fmt.Errorf("failed to read confirmation.\n%w", ErrAborted)
+		}
+		confirmation = strings.TrimSpace(line)
+	}
+	if confirmation != c.Cluster {
+		return fmt.Errorf("confirmation %s does not match %s.\n%w", confirmation, c.Cluster, ErrAborted)
+	}
+	return nil
+}
+
+func (c *Config) printPlan(plan *Plan) {
+	fmt.Fprintf(c.Out, "The following will be removed for management cluster %s:\n", c.Cluster)
+	for _, file := range plan.CMCFiles {
+		fmt.Fprintf(c.Out, "  %s: %s\n", c.CMCRepository, file)
+	}
+	if plan.SopsRule {
+		fmt.Fprintf(c.Out, "  %s: creation rule of %s in %s\n", c.CMCRepository, c.Cluster, cmc.SopsFile)
+	}
+	for _, file := range plan.InstallationsFiles {
+		fmt.Fprintf(c.Out, "  %s: %s\n", key.RepositoryInstallations, file)
+	}
+}
+
+func (c *Config) getCMCRepository(branch string) github.Repository {
+	return github.Repository{
+		Github:       c.Github,
+		Name:         c.CMCRepository,
+		Organization: key.OrganizationGiantSwarm,
+		Branch:       branch,
+	}
+}
+
+func (c *Config) getInstallationsRepository(branch string) github.Repository {
+	return github.Repository{
+		Github:       c.Github,
+		Name:         key.RepositoryInstallations,
+		Organization: key.OrganizationGiantSwarm,
+		Branch:       branch,
+	}
+}
+
+func prepareBranch(ctx context.Context, repository github.Repository, base string) error {
+	err := repository.CheckBranch(ctx)
+	if err == nil {
+		return nil
+	}
+	if !github.IsNotFound(err) {
+		return err
+	}
+	log.Debug().Msgf("branch %s not found, creating it", repository.Branch)
+	return repository.CreateBranch(ctx, base)
+}
+
+// containsPath reports whether the content contains the path as a whole, e.g. management-clusters/gig is not contained in management-clusters/gigmac.
+func containsPath(content string, p string) bool {
+	for offset := 0; ; {
+		i := strings.Index(content[offset:], p)
+		if i < 0 {
+			return false
+		}
+		end := offset + i + len(p)
+		if end == len(content) || !isNameChar(content[end]) {
+			return true
+		}
+		offset = end
+	}
+}
+
+func isNameChar(c byte) bool {
+	return c == '-' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+func isYAML(file string) bool {
+	return strings.HasSuffix(file, ".yaml") || strings.HasSuffix(file, ".yml")
+}
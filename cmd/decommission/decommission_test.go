@@ -0,0 +1,156 @@
+package decommission
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/giantswarm/mcli/pkg/github/githubtest"
+	"github.com/giantswarm/mcli/pkg/key"
+	"github.com/giantswarm/mcli/pkg/managementcluster/cmc"
+	"github.com/giantswarm/mcli/pkg/managementcluster/cmc/sopsfile"
+)
+
+const cmcRepository = "giantswarm-management-clusters"
+
+func TestRun(t *testing.T) {
+	testCases := []struct {
+		name         string
+		cluster      string
+		extraFiles   map[string]string
+		confirmation string
+		input        string
+
+		expectError error
+	}{
+		{
+			name:    "case 0: delete after typed confirmation",
+			cluster: "gig",
+			input:   "gig\n",
+		},
+		{
+			name:         "case 1: delete with confirmation flag",
+			cluster:      "gig",
+			confirmation: "gig",
+		},
+		{
+			name:    "case 2: wrong confirmation",
+			cluster: "gig",
+			input:   "gigmac\n",
+
+			expectError: ErrAborted,
+		},
+		{
+			name:    "case 3: referenced by a fleet kustomization",
+			cluster: "gig",
+			extraFiles: map[string]string{
+				"fleet/kustomization.yaml": "resources:\n- ../management-clusters/gig\n",
+			},
+			confirmation: "gig",
+
+			expectError: ErrReferenced,
+		},
+		{
+			name:         "case 4: unknown cluster",
+			cluster:      "unknown",
+			confirmation: "unknown",
+
+			expectError: ErrNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := githubtest.NewServer()
+			defer server.Close()
+			cmcFiles := getCMCFiles(t)
+			for k, v := range tc.extraFiles {
+				cmcFiles[k] = v
+			}
+			server.AddRepository(key.OrganizationGiantSwarm, cmcRepository, cmcFiles)
+			server.AddBranchRepository(key.OrganizationGiantSwarm, key.RepositoryInstallations, key.InstallationsMainBranch, map[string]string{
+				"gig/cluster.yaml":    "codename: gig\n",
+				"gigmac/cluster.yaml": "codename: gigmac\n",
+			})
+
+			c := Config{
+				Cluster:             tc.cluster,
+				CMCRepository:       cmcRepository,
+				CMCBranch:           key.GetDeleteBranch(tc.cluster),
+				InstallationsBranch: key.GetDeleteBranch(tc.cluster),
+				Github:              server.Client(),
+				Confirmation:        tc.confirmation,
+				In:                  strings.NewReader(tc.input),
+				Out:                 &bytes.Buffer{},
+			}
+			results, err := c.Run(context.Background())
+			if tc.expectError != nil {
+				if !errors.Is(err, tc.expectError) {
+					t.Fatalf("expected %v but got %v", tc.expectError, err)
+				}
+				if len(server.PullRequests(key.OrganizationGiantSwarm, cmcRepository)) != 0 {
+					t.Fatalf("expected no pull requests")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(results) != 2 {
+				t.Fatalf("expected results for 2 repositories, got %v", results)
+			}
+
+			files := server.Files(key.OrganizationGiantSwarm, cmcRepository, key.GetDeleteBranch("gig"))
+			for file := range files {
+				if strings.HasPrefix(file, "management-clusters/gig/") {
+					t.Fatalf("expected %s to be deleted", file)
+				}
+			}
+			if _, ok := files["management-clusters/gigmac/kustomization.yaml"]; !ok {
+				t.Fatalf("expected other clusters to be kept")
+			}
+			if _, err := sopsfile.GetSopsConfig(files[cmc.SopsFile], "gig"); err == nil {
+				t.Fatalf("expected creation rule of gig to be removed")
+			}
+			if _, err := sopsfile.GetSopsConfig(files[cmc.SopsFile], "gigmac"); err != nil {
+				t.Fatalf("expected creation rule of gigmac to be kept: %v", err)
+			}
+
+			installations := server.Files(key.OrganizationGiantSwarm, key.RepositoryInstallations, key.GetDeleteBranch("gig"))
+			if _, ok := installations["gig/cluster.yaml"]; ok {
+				t.Fatalf("expected installations entry to be deleted")
+			}
+			if _, ok := installations["gigmac/cluster.yaml"]; !ok {
+				t.Fatalf("expected other installations entries to be kept")
+			}
+
+			for _, repository := range []string{cmcRepository, key.RepositoryInstallations} {
+				pulls := server.PullRequests(key.OrganizationGiantSwarm, repository)
+				if len(pulls) != 1 || pulls[0].Title != "Delete management cluster gig" {
+					t.Fatalf("expected one pull request in %s, got %v", repository, pulls)
+				}
+			}
+		})
+	}
+}
+
+func getCMCFiles(t *testing.T) map[string]string {
+	sops := ""
+	for _, cluster := range []string{"gig", "gigmac"} {
+		var err error
+		sops, err = sopsfile.GetSopsFile(sopsfile.Config{Cluster: cluster, AgePubKey: "age1" + cluster}, sops)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	return map[string]string{
+		cmc.SopsFile: sops,
+		"management-clusters/gig/kustomization.yaml":    "resources:\n- secret.yaml\n",
+		"management-clusters/gig/secret.yaml":           "data: encrypted\n",
+		"management-clusters/gigmac/kustomization.yaml": "resources:\n- ../../bases/gigmac\n",
+		"management-clusters/gigmac/secret.yaml":        "path: management-clusters/gig\n",
+		"bases/kustomization.yaml":                      "resources:\n- ../management-clusters/gigmac\n",
+	}
+}
@@ -0,0 +1,11 @@
+package decommission
+
+import (
+	"errors"
+)
+
+var ErrAborted = errors.New("aborted")
+
+var ErrNotFound = errors.New("not found")
+
+var ErrReferenced = errors.New("management cluster is still referenced")
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/mcli/cmd/decommission"
+	"github.com/giantswarm/mcli/pkg/github"
+	"github.com/giantswarm/mcli/pkg/managementcluster"
+)
+
+// deleteCmd represents the delete command
+var deleteCmd = &cobra.Command{
+	Use:   "delete",
+	Short: "Prepares the removal of a Management Cluster from all repositories",
+	Long: `Prepares the removal of a Management Cluster from all relevant git repositories.
+The CMC entry, its SOPS creation rule and the installations entry are deleted
+on branches and pull requests are opened. The command refuses to delete a cluster
+that is still referenced by other files of the CMC repository.
+The cluster name has to be typed back to confirm the deletion. For example:
+
+mcli delete --cluster=gigmac
+
+mcli delete --cluster=gigmac --confirm=gigmac`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		defaultDelete()
+		err := validateRoot(cmd, args)
+		if err != nil {
+			return err
+		}
+		ctx := context.Background()
+		client := github.New(github.Config{
+			Token: githubToken,
+		})
+		c := decommission.Config{
+			Cluster:             cluster,
+			CMCRepository:       cmcRepository,
+			CMCBranch:           cmcBranch,
+			InstallationsBranch: installationsBranch,
+			Github:              client,
+			Skip:                skip,
+			Confirmation:        deleteConfirmation,
+			In:                  os.Stdin,
+			Out:                 os.Stdout,
+		}
+		results, err := c.Run(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to delete management cluster.\n%w", err)
+		}
+		summary := &managementcluster.Summary{
+			Cluster: cluster,
+		}
+		for _, result := range results {
+			fmt.Printf("%s: %s on branch %s (%d files)\n", result.Repository, result.Status, result.Branch, len(result.Files))
+			summary.Repositories = append(summary.Repositories, &managementcluster.RepositorySummary{Result: *result})
+		}
+		return writeAudit(ctx, cmd, client, cmcRepository, summary)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(deleteCmd)
+	addFlagsDelete()
+}
@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/giantswarm/mcli/pkg/key"
+)
+
+const (
+	flagConfirm = "confirm"
+)
+
+var (
+	deleteConfirmation string
+)
+
+func addFlagsDelete() {
+	deleteCmd.Flags().StringVar(&deleteConfirmation, flagConfirm, "", "Name of the management cluster to confirm the deletion without prompt")
+	deleteCmd.Flags().StringArrayVarP(&skip, flagSkip, "s", []string{}, fmt.Sprintf("List of repositories to skip. (default: none) Valid values: %s", key.GetValidRepositories()))
+}
+
+func defaultDelete() {
+	if customer == "" {
+		customer = key.OrganizationGiantSwarm
+	}
+	if cmcRepository == "" {
+		cmcRepository = key.GetCMCName(customer)
+	}
+	if cmcBranch == "" {
+		cmcBranch = key.GetDeleteBranch(cluster)
+	}
+	if installationsBranch == "" {
+		installationsBranch = key.GetDeleteBranch(cluster)
+	}
+}
@@ -0,0 +1,7 @@
+package field
+
+import (
+	"errors"
+)
+
+var ErrInvalidFlag = errors.New("invalid flag")
@@ -0,0 +1,219 @@
+package field
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+
+	pullcmc "github.com/giantswarm/mcli/cmd/pull/cmc"
+	pullinstallations "github.com/giantswarm/mcli/cmd/pull/installations"
+	pushcmc "github.com/giantswarm/mcli/cmd/push/cmc"
+	pushinstallations "github.com/giantswarm/mcli/cmd/push/installations"
+	"github.com/giantswarm/mcli/pkg/github"
+	"github.com/giantswarm/mcli/pkg/key"
+	"github.com/giantswarm/mcli/pkg/managementcluster"
+	"github.com/giantswarm/mcli/pkg/policy"
+)
+
+type Config struct {
+	Cluster             string
+	Github              *github.Github
+	CMCRepository       string
+	InstallationsBranch string
+	CMCBranch           string
+	// Fields are paths for get and path=value for set.
+	Fields         []string
+	DryRun         bool
+	DisplaySecrets bool
+	Out            io.Writer
+	// Policy is evaluated against the desired state before it is pushed.
+	Policy *policy.Policy
+}
+
+// Get prints the values of the fields.
+func (c *Config) Get(ctx context.Context) error {
+	repositories, err := getRepositories(c.Fields)
+	if err != nil {
+		return err
+	}
+	mc, err := c.pull(ctx, repositories, c.DisplaySecrets)
+	if err != nil {
+		return err
+	}
+	for _, path := range c.Fields {
+		value, err := managementcluster.GetField(mc, path)
+		if err != nil {
+			return err
+		}
+		if len(c.Fields) > 1 {
+			value = fmt.Sprintf("%s: %s", path, managementcluster.FormatValue(value))
+		}
+		fmt.Fprint(c.Out, strings.TrimSuffix(value, "\n")+"\n")
+	}
+	return nil
+}
+
+// Set applies the fields to the current state and pushes the result.
+// With DryRun the changed fields are printed and nothing is pushed.
+func (c *Config) Set(ctx context.Context) (*managementcluster.Summary, error) {
+	var paths []string
+	for _, field := range c.Fields {
+		path, _, err := managementcluster.ParseField(field)
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, path)
+	}
+	repositories, err := getRepositories(paths)
+	if err != nil {
+		return nil, err
+	}
+	current, err := c.pull(ctx, repositories, true)
+	if err != nil {
+		return nil, err
+	}
+	desired, err := managementcluster.SetFields(current, c.Fields)
+	if err != nil {
+		return nil, err
+	}
+	if c.DryRun {
+		if err := managementcluster.PrintChanges(c.Out, current, desired, c.DisplaySecrets); err != nil {
+			return nil, err
+		}
+		return nil, c.checkPolicy(desired, repositories)
+	}
+	if err := c.checkPolicy(desired, repositories); err != nil {
+		return nil, err
+	}
+
+	summary := &managementcluster.Summary{
+		Cluster: c.Cluster,
+	}
+	if repositories[key.RepositoryInstallations] {
+		i := pushinstallations.Config{
+			Cluster:             c.Cluster,
+			Github:              c.Github,
+			InstallationsBranch: c.InstallationsBranch,
+			CMCRepository:       c.CMCRepository,
+			Input:               &desired.Installations,
+			Replace:             true,
+		}
+		_, result, err := i.Run(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to push installations.\n%w", err)
+		}
+		summary.Repositories = append(summary.Repositories, result)
+	}
+	if repositories[key.RepositoryCMC] {
+		i := pushcmc.Config{
+			Cluster:       c.Cluster,
+			Github:        c.Github,
+			CMCRepository: c.CMCRepository,
+			CMCBranch:     c.CMCBranch,
+			Input:         &desired.CMC,
+			Replace:       true,
+		}
+		_, result, err := i.Run(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to push cmc.\n%w", err)
+		}
+		summary.Repositories = append(summary.Repositories, result)
+	}
+	return summary, nil
+}
+
+// pull reads the current state from the branches if they exist and from the main branches otherwise.
+// The CMC repository is taken from the installations entry.
+func (c *Config) pull(ctx context.Context, repositories map[string]bool, displaySecrets bool) (*managementcluster.ManagementCluster, error) {
+	log.Debug().Msgf("pulling management cluster %s", c.Cluster)
+	mc := &managementcluster.ManagementCluster{}
+
+	branch, err := c.getBranch(ctx, key.RepositoryInstallations, c.InstallationsBranch, key.InstallationsMainBranch)
+	if err != nil {
+		return nil, err
+	}
+	i := pullinstallations.Config{
+		Cluster:             c.Cluster,
+		Github:              c.Github,
+		InstallationsBranch: branch,
+	}
+	installations, err := i.Run(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pull installations.\n%w", err)
+	}
+	mc.Installations = *installations
+	if mc.Installations.CmcRepository != "" {
+		c.CMCRepository = mc.Installations.CmcRepository
+	}
+
+	if repositories[key.RepositoryCMC] {
+		branch, err := c.getBranch(ctx, c.CMCRepository, c.CMCBranch, key.CMCMainBranch)
+		if err != nil {
+			return nil, err
+		}
+		p := pullcmc.Config{
+			Cluster:        c.Cluster,
+			Github:         c.Github,
+			CMCRepository:  c.CMCRepository,
+			CMCBranch:      branch,
+			DisplaySecrets: displaySecrets,
+		}
+		cmc, err := p.Run(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to pull CMC.\n%w", err)
+		}
+		mc.CMC = *cmc
+	}
+	return mc, nil
+}
+
+// checkPolicy evaluates the policy against the desired state. The CMC part is only pulled if fields of it are set.
+func (c *Config) checkPolicy(desired *managementcluster.ManagementCluster, repositories map[string]bool) error {
+	if c.Policy == nil {
+		return nil
+	}
+	var skip []string
+	if !repositories[key.RepositoryCMC] {
+		skip = append(skip, key.RepositoryCMC)
+	}
+	return c.Policy.Without(skip).Check(c.Out, desired)
+}
+
+func (c *Config) getBranch(ctx context.Context, name string, branch string, mainBranch string) (string, error) {
+	if branch == mainBranch {
+		return branch, nil
+	}
+	repository := github.Repository{
+		Github:       c.Github,
+		Name:         name,
+		Organization: key.OrganizationGiantSwarm,
+		Branch:       branch,
+	}
+	err := repository.CheckBranch(ctx)
+	if err == nil {
+		return branch, nil
+	}
+	if github.IsNotFound(err) {
+		log.Debug().Msgf("%s branch %s not found, using %s", name, branch, mainBranch)
+		return mainBranch, nil
+	}
+	return "", fmt.Errorf("failed to check %s branch %s.\n%w", name, branch, err)
+}
+
+func getRepositories(paths []string) (map[string]bool, error) {
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no fields given.\n%w", ErrInvalidFlag)
+	}
+	repositories := map[string]bool{}
+	for _, path := range paths {
+		repository, err := managementcluster.GetFieldRepository(path)
+		if err != nil {
+			return nil, err
+		}
+		repositories[repository] = true
+	}
+	return repositories, nil
+}
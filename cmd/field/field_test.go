@@ -0,0 +1,231 @@
+package field
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/giantswarm/mcli/pkg/github/githubtest"
+	"github.com/giantswarm/mcli/pkg/key"
+	"github.com/giantswarm/mcli/pkg/managementcluster"
+	"github.com/giantswarm/mcli/pkg/policy"
+)
+
+const installationsFile = `base: gigmac.gigantic.io
+codename: gigmac
+customer: giantswarm
+cmc_repository: giantswarm-management-clusters
+ccr_repository: giantswarm-customer-configs
+accountEngineer: phoenix
+pipeline: testing
+provider: capz
+`
+
+func TestGet(t *testing.T) {
+	testCases := []struct {
+		name   string
+		fields []string
+
+		expected    string
+		expectedErr error
+	}{
+		{
+			name:   "case 0: get single field",
+			fields: []string{"installations.pipeline"},
+
+			expected: "testing\n",
+		},
+		{
+			name:   "case 1: get several fields",
+			fields: []string{"installations.pipeline", "installations.provider"},
+
+			expected: "installations.pipeline: \"testing\"\ninstallations.provider: \"capz\"\n",
+		},
+		{
+			name:   "case 2: unknown repository",
+			fields: []string{"pipeline"},
+
+			expectedErr: managementcluster.ErrInvalidField,
+		},
+		{
+			name: "case 3: no fields",
+
+			expectedErr: ErrInvalidFlag,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := githubtest.NewServer()
+			defer server.Close()
+			server.AddBranchRepository(key.OrganizationGiantSwarm, key.RepositoryInstallations, key.InstallationsMainBranch, map[string]string{
+				"gigmac/cluster.yaml": installationsFile,
+			})
+
+			out := &bytes.Buffer{}
+			c := Config{
+				Cluster:             "gigmac",
+				Github:              server.Client(),
+				InstallationsBranch: key.InstallationsMainBranch,
+				CMCBranch:           key.CMCMainBranch,
+				Fields:              tc.fields,
+				Out:                 out,
+			}
+			err := c.Get(context.Background())
+			if !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("expected error %v, got %v", tc.expectedErr, err)
+			}
+			if out.String() != tc.expected {
+				t.Fatalf("expected %q, got %q", tc.expected, out.String())
+			}
+		})
+	}
+}
+
+func TestSet(t *testing.T) {
+	testCases := []struct {
+		name   string
+		fields []string
+		dryRun bool
+
+		expectedOut      string
+		expectedPipeline string
+	}{
+		{
+			name:   "case 0: dry run",
+			fields: []string{"installations.pipeline=stable"},
+			dryRun: true,
+
+			expectedOut:      "installations.pipeline: \"testing\" -> \"stable\"\n",
+			expectedPipeline: "testing",
+		},
+		{
+			name:   "case 1: dry run without changes",
+			fields: []string{"installations.pipeline=testing"},
+			dryRun: true,
+
+			expectedOut:      "no changes\n",
+			expectedPipeline: "testing",
+		},
+		{
+			name:   "case 2: push",
+			fields: []string{"installations.pipeline=stable"},
+
+			expectedPipeline: "stable",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := githubtest.NewServer()
+			defer server.Close()
+			server.AddBranchRepository(key.OrganizationGiantSwarm, key.RepositoryInstallations, key.InstallationsMainBranch, map[string]string{
+				"gigmac/cluster.yaml": installationsFile,
+			})
+
+			out := &bytes.Buffer{}
+			branch := key.GetDefaultPRBranch("gigmac")
+			c := Config{
+				Cluster:             "gigmac",
+				Github:              server.Client(),
+				InstallationsBranch: branch,
+				CMCBranch:           branch,
+				Fields:              tc.fields,
+				DryRun:              tc.dryRun,
+				Out:                 out,
+			}
+			summary, err := c.Set(context.Background())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if out.String() != tc.expectedOut {
+				t.Fatalf("expected %q, got %q", tc.expectedOut, out.String())
+			}
+			if tc.dryRun {
+				if summary != nil || len(server.Commits(key.OrganizationGiantSwarm, key.RepositoryInstallations, branch)) != 0 {
+					t.Fatalf("expected nothing to be pushed")
+				}
+				return
+			}
+			if len(summary.Repositories) != 1 {
+				t.Fatalf("expected 1 repository in summary, got %d", len(summary.Repositories))
+			}
+			if summary.Repositories[0].ChangedFields[0] != "pipeline" {
+				t.Fatalf("expected pipeline to be changed, got %v", summary.Repositories[0].ChangedFields)
+			}
+			files := server.Files(key.OrganizationGiantSwarm, key.RepositoryInstallations, branch)
+			if !strings.Contains(files["gigmac/cluster.yaml"], "pipeline: "+tc.expectedPipeline) {
+				t.Fatalf("expected pipeline %s, got %s", tc.expectedPipeline, files["gigmac/cluster.yaml"])
+			}
+		})
+	}
+}
+
+func TestSetPolicy(t *testing.T) {
+	p, err := policy.Parse([]byte("rules:\n- name: stable-pipeline\n  require: installations.pipeline == \"stable\"\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	testCases := []struct {
+		name   string
+		fields []string
+		dryRun bool
+
+		expectDenied bool
+	}{
+		{
+			name:   "case 0: allowed",
+			fields: []string{"installations.pipeline=stable"},
+		},
+		{
+			name:   "case 1: denied",
+			fields: []string{"installations.pipeline=alpha"},
+
+			expectDenied: true,
+		},
+		{
+			name:   "case 2: denied in dry run",
+			fields: []string{"installations.pipeline=alpha"},
+			dryRun: true,
+
+			expectDenied: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := githubtest.NewServer()
+			defer server.Close()
+			server.AddBranchRepository(key.OrganizationGiantSwarm, key.RepositoryInstallations, key.InstallationsMainBranch, map[string]string{
+				"gigmac/cluster.yaml": installationsFile,
+			})
+
+			branch := key.GetDefaultPRBranch("gigmac")
+			c := Config{
+				Cluster:             "gigmac",
+				Github:              server.Client(),
+				InstallationsBranch: branch,
+				CMCBranch:           branch,
+				Fields:              tc.fields,
+				DryRun:              tc.dryRun,
+				Policy:              p,
+				Out:                 &bytes.Buffer{},
+			}
+			_, err := c.Set(context.Background())
+			if tc.expectDenied {
+				if !errors.Is(err, policy.ErrDenied) {
+					t.Fatalf("expected policy error, got %v", err)
+				}
+				if len(server.Commits(key.OrganizationGiantSwarm, key.RepositoryInstallations, branch)) != 0 {
+					t.Fatalf("expected nothing to be pushed")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/mcli/cmd/field"
+	"github.com/giantswarm/mcli/pkg/github"
+)
+
+// getCmd represents the get command
+var getCmd = &cobra.Command{
+	Use:   "get <path>...",
+	Short: "Prints fields of the configuration of a Management Cluster",
+	Long: `Prints fields of the current configuration of a Management Cluster.
+Fields are addressed by their path in the output of pull, starting with
+installations or cmc. Numeric path segments address list elements. For example:
+
+mcli get cmc.clusterApp.version --cluster=gigmac
+
+mcli get installations.pipeline installations.provider --cluster=gigmac`,
+	Args:         cobra.MinimumNArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		defaultPull()
+		err := validateRoot(cmd, args)
+		if err != nil {
+			return err
+		}
+		ctx := context.Background()
+		c := field.Config{
+			Cluster: cluster,
+			Github: github.New(github.Config{
+				Token: githubToken,
+			}),
+			CMCRepository:       cmcRepository,
+			InstallationsBranch: installationsBranch,
+			CMCBranch:           cmcBranch,
+			Fields:              args,
+			DisplaySecrets:      displaySecrets,
+			Out:                 os.Stdout,
+		}
+		if err := c.Get(ctx); err != nil {
+			return fmt.Errorf("failed to get fields.\n%w", err)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(getCmd)
+}
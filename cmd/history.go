@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/mcli/cmd/history"
+	"github.com/giantswarm/mcli/pkg/github"
+)
+
+// historyCmd represents the history command
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Lists the changes of the configuration of a Management Cluster over time",
+	Long: `Lists the commits which changed the configuration of a Management Cluster in the
+installations and CMC repositories with time, author and the changed fields of each
+revision, newest first. Secrets are compared but redacted in the output. For example:
+
+mcli history --cluster=gigmac
+
+mcli history --cluster=gigmac --field=cmc.mcProxy --limit=50`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		defaultPull()
+		err := validateRoot(cmd, args)
+		if err != nil {
+			return err
+		}
+		err = validateHistory(cmd, args)
+		if err != nil {
+			return err
+		}
+		ctx := context.Background()
+		c := history.Config{
+			Cluster: cluster,
+			Github: github.New(github.Config{
+				Token: githubToken,
+			}),
+			InstallationsBranch: installationsBranch,
+			CMCBranch:           cmcBranch,
+			CMCRepository:       cmcRepository,
+			Skip:                skip,
+			Fields:              historyFields,
+			Limit:               historyLimit,
+			DisplaySecrets:      displaySecrets,
+		}
+		revisions, err := c.Run(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get history of management cluster.\n%w", err)
+		}
+		return history.Print(os.Stdout, revisions, historyOutput)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(historyCmd)
+	addFlagsHistory()
+}
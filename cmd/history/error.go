@@ -0,0 +1,7 @@
+package history
+
+import (
+	"errors"
+)
+
+var ErrInvalidFlag = errors.New("invalid flag")
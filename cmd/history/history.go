@@ -0,0 +1,297 @@
+package history
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	pullcmc "github.com/giantswarm/mcli/cmd/pull/cmc"
+	pullinstallations "github.com/giantswarm/mcli/cmd/pull/installations"
+	"github.com/giantswarm/mcli/pkg/github"
+	"github.com/giantswarm/mcli/pkg/key"
+	"github.com/giantswarm/mcli/pkg/managementcluster"
+)
+
+const (
+	OutputText = "text"
+	OutputJSON = key.OutputJSON
+)
+
+// DefaultLimit is the default number of revisions per repository.
+const DefaultLimit = 20
+
+type Config struct {
+	Cluster             string
+	Github              *github.Github
+	InstallationsBranch string
+	CMCBranch           string
+	CMCRepository       string
+	Skip                []string
+	// Fields limits the changes to fields below the paths, e.g. cmc.mcProxy.
+	Fields []string
+	// Limit is the number of revisions per repository. 0 lists all revisions.
+	Limit          int
+	DisplaySecrets bool
+}
+
+// Revision is a commit which changed the configuration of the management cluster.
+type Revision struct {
+	Time       time.Time `json:"time"`
+	Repository string    `json:"repository"`
+	SHA        string    `json:"sha"`
+	Author     string    `json:"author"`
+	Message    string    `json:"message"`
+	URL        string    `json:"url,omitempty"`
+	Changes    []Change  `json:"changes"`
+}
+
+type Change struct {
+	Field string `json:"field"`
+	Old   string `json:"old"`
+	New   string `json:"new"`
+}
+
+// Run returns the revisions of the management cluster, newest first.
+func (c *Config) Run(ctx context.Context) ([]Revision, error) {
+	if c.Limit < 0 {
+		return nil, fmt.Errorf("limit %d is negative.\n%w", c.Limit, ErrInvalidFlag)
+	}
+	var revisions []Revision
+	if !key.Skip(key.RepositoryInstallations, c.Skip) {
+		repository := github.Repository{
+			Github:       c.Github,
+			Name:         key.RepositoryInstallations,
+			Organization: key.OrganizationGiantSwarm,
+			Branch:       c.InstallationsBranch,
+		}
+		r, err := c.getRevisions(ctx, repository, key.GetInstallationsPath(c.Cluster), c.pullInstallations)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get installations history.\n%w", err)
+		}
+		revisions = append(revisions, r...)
+
+		// the history of the cmc repository is read from the one the cluster is in now
+		current, err := c.pullInstallations(ctx, c.InstallationsBranch)
+		if err != nil {
+			return nil, err
+		}
+		if current.Installations.CmcRepository != "" {
+			c.CMCRepository = current.Installations.CmcRepository
+		}
+	}
+	if !key.Skip(key.RepositoryCMC, c.Skip) {
+		repository := github.Repository{
+			Github:       c.Github,
+			Name:         c.CMCRepository,
+			Organization: key.OrganizationGiantSwarm,
+			Branch:       c.CMCBranch,
+		}
+		r, err := c.getRevisions(ctx, repository, key.GetCMCPath(c.Cluster), c.pullCMC)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get %s history.\n%w", c.CMCRepository, err)
+		}
+		revisions = append(revisions, r...)
+	}
+	sort.SliceStable(revisions, func(i, j int) bool {
+		return revisions[i].Time.After(revisions[j].Time)
+	})
+	return revisions, nil
+}
+
+type pullFunc func(ctx context.Context, ref string) (*managementcluster.ManagementCluster, error)
+
+// getRevisions compares each commit which changed the path with the state before it.
+func (c *Config) getRevisions(ctx context.Context, repository github.Repository, path string, pull pullFunc) ([]Revision, error) {
+	if err := repository.Check(ctx); err != nil {
+		return nil, err
+	}
+	commits, err := repository.ListCommits(ctx, path, c.Limit)
+	if err != nil {
+		return nil, err
+	}
+	log.Debug().Msgf("found %d commits changing %s in %s", len(commits), path, repository.Name)
+
+	var revisions []Revision
+	var next *managementcluster.ManagementCluster
+	for i, commit := range commits {
+		current := next
+		if current == nil {
+			current, err = pull(ctx, commit.SHA)
+			if err != nil {
+				return nil, err
+			}
+		}
+		// commits are filtered by path, so the state before a commit is the state of the next older one
+		previous := &managementcluster.ManagementCluster{}
+		if commit.Parent != "" {
+			previous, err = pull(ctx, commit.Parent)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if i+1 < len(commits) && commits[i+1].SHA == commit.Parent {
+			next = previous
+		} else {
+			next = nil
+		}
+
+		changes, err := c.getChanges(previous, current)
+		if err != nil {
+			return nil, err
+		}
+		if len(changes) == 0 && len(c.Fields) > 0 {
+			continue
+		}
+		revisions = append(revisions, Revision{
+			Time:       commit.Time,
+			Repository: repository.Name,
+			SHA:        commit.SHA,
+			Author:     commit.Author,
+			Message:    commit.GetSubject(),
+			URL:        commit.URL,
+			Changes:    changes,
+		})
+	}
+	return revisions, nil
+}
+
+func (c *Config) getChanges(previous *managementcluster.ManagementCluster, current *managementcluster.ManagementCluster) ([]Change, error) {
+	// the parts are compared on their own to list single fields if the cluster did not exist before
+	installationsFields, err := key.GetChangedFields(&previous.Installations, &current.Installations)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get changed fields.\n%w", err)
+	}
+	cmcFields, err := key.GetChangedFields(&previous.CMC, &current.CMC)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get changed fields.\n%w", err)
+	}
+	var fields []string
+	for _, field := range installationsFields {
+		fields = append(fields, "installations."+field)
+	}
+	for _, field := range cmcFields {
+		fields = append(fields, "cmc."+field)
+	}
+	// secrets are compared before they are redacted, so changes of secrets are listed
+	if !c.DisplaySecrets {
+		if previous, err = redact(previous); err != nil {
+			return nil, err
+		}
+		if current, err = redact(current); err != nil {
+			return nil, err
+		}
+	}
+	changes := []Change{}
+	for _, field := range fields {
+		if !c.matches(field) {
+			continue
+		}
+		changes = append(changes, Change{
+			Field: field,
+			Old:   managementcluster.FormatField(previous, field),
+			New:   managementcluster.FormatField(current, field),
+		})
+	}
+	return changes, nil
+}
+
+func (c *Config) matches(field string) bool {
+	if len(c.Fields) == 0 {
+		return true
+	}
+	for _, prefix := range c.Fields {
+		if field == prefix || strings.HasPrefix(field, prefix+".") || strings.HasPrefix(prefix, field+".") {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Config) pullInstallations(ctx context.Context, ref string) (*managementcluster.ManagementCluster, error) {
+	p := pullinstallations.Config{
+		Cluster:             c.Cluster,
+		Github:              c.Github,
+		InstallationsBranch: c.InstallationsBranch,
+		Ref:                 ref,
+	}
+	i, err := p.Run(ctx)
+	if github.IsNotFound(err) {
+		// the cluster did not exist at this revision
+		return &managementcluster.ManagementCluster{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to pull installations at %s.\n%w", ref, err)
+	}
+	return &managementcluster.ManagementCluster{Installations: *i}, nil
+}
+
+func (c *Config) pullCMC(ctx context.Context, ref string) (*managementcluster.ManagementCluster, error) {
+	p := pullcmc.Config{
+		Cluster:        c.Cluster,
+		Github:         c.Github,
+		CMCRepository:  c.CMCRepository,
+		CMCBranch:      c.CMCBranch,
+		DisplaySecrets: true,
+		Ref:            ref,
+	}
+	cmc, err := p.Run(ctx)
+	if github.IsNotFound(err) {
+		return &managementcluster.ManagementCluster{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to pull %s at %s.\n%w", c.CMCRepository, ref, err)
+	}
+	return &managementcluster.ManagementCluster{CMC: *cmc}, nil
+}
+
+// redact returns a copy without secrets. The original is compared with the next revision.
+func redact(mc *managementcluster.ManagementCluster) (*managementcluster.ManagementCluster, error) {
+	data, err := managementcluster.GetData(mc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy management cluster.\n%w", err)
+	}
+	redacted, err := managementcluster.GetManagementCluster(data)
+	if err != nil {
+		return nil, err
+	}
+	redacted.CMC.RedactSecrets()
+	return redacted, nil
+}
+
+func GetValidOutputs() []string {
+	return []string{OutputText, OutputJSON}
+}
+
+func Print(w io.Writer, revisions []Revision, output string) error {
+	if output == OutputJSON {
+		if revisions == nil {
+			revisions = []Revision{}
+		}
+		data, err := json.MarshalIndent(revisions, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal history.\n%w", err)
+		}
+		_, err = fmt.Fprintln(w, string(data))
+		return err
+	}
+	for _, revision := range revisions {
+		fmt.Fprintf(w, "%s %s %s %s %s\n", revision.Time.UTC().Format(time.RFC3339), revision.Repository, getShortSHA(revision.SHA), revision.Author, revision.Message)
+		for _, change := range revision.Changes {
+			fmt.Fprintf(w, "  %s: %s -> %s\n", change.Field, change.Old, change.New)
+		}
+	}
+	return nil
+}
+
+func getShortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
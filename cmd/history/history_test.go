@@ -0,0 +1,123 @@
+package history
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/giantswarm/mcli/pkg/github"
+	"github.com/giantswarm/mcli/pkg/github/githubtest"
+	"github.com/giantswarm/mcli/pkg/key"
+)
+
+const installationsFile = `base: gigmac.gigantic.io
+codename: gigmac
+customer: giantswarm
+cmc_repository: giantswarm-management-clusters
+ccr_repository: giantswarm-customer-configs
+accountEngineer: phoenix
+pipeline: testing
+provider: capz
+`
+
+func TestRun(t *testing.T) {
+	testCases := []struct {
+		name   string
+		fields []string
+		limit  int
+
+		expectedMessages []string
+		expectedChanges  []string
+	}{
+		{
+			name: "case 0: all revisions",
+
+			expectedMessages: []string{"move to stable", "change engineer", "initial commit"},
+			expectedChanges:  []string{`installations.pipeline: "testing" -> "stable"`, `installations.accountEngineer: "phoenix" -> "rocket"`, `installations.pipeline: <unset> -> "testing"`},
+		},
+		{
+			name:   "case 1: filtered by field",
+			fields: []string{"installations.pipeline"},
+
+			expectedMessages: []string{"move to stable", "initial commit"},
+		},
+		{
+			name:  "case 2: limited",
+			limit: 1,
+
+			expectedMessages: []string{"move to stable"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := githubtest.NewServer()
+			defer server.Close()
+			server.AddBranchRepository(key.OrganizationGiantSwarm, key.RepositoryInstallations, key.InstallationsMainBranch, map[string]string{
+				"gigmac/cluster.yaml": installationsFile,
+			})
+			repository := github.Repository{
+				Github:       server.Client(),
+				Name:         key.RepositoryInstallations,
+				Organization: key.OrganizationGiantSwarm,
+				Branch:       key.InstallationsMainBranch,
+			}
+			ctx := context.Background()
+			updates := []struct {
+				path    string
+				content string
+				message string
+			}{
+				{"gigmac/cluster.yaml", strings.Replace(installationsFile, "phoenix", "rocket", 1), "change engineer"},
+				{"gauss/cluster.yaml", "codename: gauss\n", "add other cluster"},
+				{"gigmac/cluster.yaml", strings.Replace(strings.Replace(installationsFile, "phoenix", "rocket", 1), "testing", "stable", 1), "move to stable"},
+			}
+			for _, u := range updates {
+				if _, err := repository.CreateFileWithMessage(ctx, []byte(u.content), u.path, u.message); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+
+			c := Config{
+				Cluster:             "gigmac",
+				Github:              server.Client(),
+				InstallationsBranch: key.InstallationsMainBranch,
+				Skip:                []string{key.RepositoryCMC},
+				Fields:              tc.fields,
+				Limit:               tc.limit,
+			}
+			revisions, err := c.Run(ctx)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var messages []string
+			for _, revision := range revisions {
+				messages = append(messages, revision.Message)
+				if revision.Author != githubtest.User {
+					t.Fatalf("expected author %s, got %s", githubtest.User, revision.Author)
+				}
+			}
+			if strings.Join(messages, ",") != strings.Join(tc.expectedMessages, ",") {
+				t.Fatalf("expected revisions %v, got %v", tc.expectedMessages, messages)
+			}
+			if tc.fields != nil {
+				for _, revision := range revisions {
+					if len(revision.Changes) != 1 || revision.Changes[0].Field != "installations.pipeline" {
+						t.Fatalf("expected only pipeline changes, got %v", revision.Changes)
+					}
+				}
+			}
+
+			out := &bytes.Buffer{}
+			if err := Print(out, revisions, OutputText); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			for _, change := range tc.expectedChanges {
+				if !strings.Contains(out.String(), change) {
+					t.Fatalf("expected output to contain %q, got\n%s", change, out.String())
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/mcli/cmd/history"
+	"github.com/giantswarm/mcli/pkg/key"
+)
+
+const (
+	flagField = "field"
+	flagLimit = "limit"
+)
+
+var (
+	historyFields []string
+	historyLimit  int
+	historyOutput string
+)
+
+func addFlagsHistory() {
+	historyCmd.Flags().StringArrayVar(&historyFields, flagField, []string{}, "Only list changes of fields below the path, e.g. cmc.mcProxy. Can be repeated. (default: all fields)")
+	historyCmd.Flags().IntVar(&historyLimit, flagLimit, history.DefaultLimit, "Number of revisions per repository. 0 lists all revisions")
+	historyCmd.Flags().StringVarP(&historyOutput, flagOutput, "o", history.OutputText, fmt.Sprintf("Output format. Valid values: %v", history.GetValidOutputs()))
+	historyCmd.Flags().StringArrayVarP(&skip, flagSkip, "s", []string{}, fmt.Sprintf("List of repositories to skip. (default: none) Valid values: %s", key.GetValidRepositories()))
+}
+
+func validateHistory(cmd *cobra.Command, args []string) error {
+	if historyLimit < 0 {
+		return fmt.Errorf("invalid limit %d. The limit must not be negative:\n%w", historyLimit, ErrInvalidFlag)
+	}
+	if !slices.Contains(history.GetValidOutputs(), historyOutput) {
+		return fmt.Errorf("invalid output %s. Valid values: %s:\n%w", historyOutput, history.GetValidOutputs(), ErrInvalidFlag)
+	}
+	return nil
+}
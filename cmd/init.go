@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/mcli/cmd/initialize"
+	"github.com/giantswarm/mcli/pkg/github"
+)
+
+// initCmd represents the init command
+var initCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Interactively creates the configuration file of a new Management Cluster",
+	Long: `Interactively creates the configuration file of a new Management Cluster.
+The answers are written to a file that can be passed to mcli push using --input.
+Secrets are not written to the file. Instead, the file references a secret folder
+which is read on push.
+If a Github token is set, the apps of an existing management cluster of the same
+provider in the CMC repository are offered as defaults. For example:
+
+mcli init --cluster=gigmac --file=gigmac.yaml
+
+mcli push --cluster=gigmac --input=gigmac.yaml`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		defaultInit()
+		err := validateInit(cmd, args)
+		if err != nil {
+			return err
+		}
+		ctx := context.Background()
+		c := initialize.Config{
+			Cluster:       cluster,
+			Customer:      customer,
+			Provider:      provider,
+			BaseDomain:    baseDomain,
+			CMCRepository: cmcRepository,
+			AgePubKey:     agePubKey,
+			SecretFolder:  secretFolder,
+			File:          initFile,
+			In:            os.Stdin,
+			Out:           os.Stdout,
+		}
+		if githubToken != "" {
+			c.Github = github.New(github.Config{
+				Token: githubToken,
+			})
+		}
+		_, err = c.Run(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to initialize management cluster.\n%w", err)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(initCmd)
+	addFlagsInit()
+}
@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/giantswarm/mcli/pkg/key"
+)
+
+const (
+	flagFile = "file"
+)
+
+var (
+	initFile string
+)
+
+func addFlagsInit() {
+	viper.AutomaticEnv()
+
+	initCmd.Flags().StringVarP(&initFile, flagFile, "f", "", "File to write the configuration to. (default: <cluster>.yaml)")
+	initCmd.Flags().StringVar(&provider, flagProvider, viper.GetString(envProvider), "Default provider of the cluster")
+	initCmd.Flags().StringVar(&baseDomain, flagBaseDomain, viper.GetString(envBaseDomain), "Default base domain of the cluster")
+	initCmd.Flags().StringVar(&secretFolder, flagSecretFolder, viper.GetString(envSecretFolder), "Secret folder referenced by the configuration")
+}
+
+func defaultInit() {
+	if customer == "" {
+		customer = key.OrganizationGiantSwarm
+	}
+}
+
+func validateInit(cmd *cobra.Command, args []string) error {
+	if provider != "" && !key.IsValidProvider(provider) {
+		return invalidFlagError(flagProvider)
+	}
+	return nil
+}
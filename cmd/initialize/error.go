@@ -0,0 +1,7 @@
+package initialize
+
+import (
+	"errors"
+)
+
+var ErrAborted = errors.New("aborted")
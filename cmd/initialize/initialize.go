@@ -0,0 +1,313 @@
+package initialize
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/giantswarm/mcli/pkg/github"
+	"github.com/giantswarm/mcli/pkg/key"
+	"github.com/giantswarm/mcli/pkg/managementcluster"
+	"github.com/giantswarm/mcli/pkg/managementcluster/cmc"
+	"github.com/giantswarm/mcli/pkg/managementcluster/cmc/certmanager"
+	"github.com/giantswarm/mcli/pkg/managementcluster/cmc/mcproxy"
+	"github.com/giantswarm/mcli/pkg/managementcluster/installations"
+)
+
+const (
+	DefaultClusterNamespace = "org-giantswarm"
+	DefaultCatalog          = "cluster"
+	DefaultPipeline         = "testing"
+	DefaultSecretFolder     = "secrets"
+)
+
+type Config struct {
+	Cluster       string
+	Customer      string
+	Provider      string
+	BaseDomain    string
+	CMCRepository string
+	AgePubKey     string
+	SecretFolder  string
+	// File defaults to <cluster>.yaml.
+	File string
+	// Github is used to look up defaults from existing clusters. It is optional.
+	Github *github.Github
+	In     io.Reader
+	Out    io.Writer
+}
+
+func (c *Config) Run(ctx context.Context) (*managementcluster.ManagementCluster, error) {
+	mc, err := c.Ask(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if c.File == "" {
+		c.File = fmt.Sprintf("%s.yaml", c.Cluster)
+	}
+	if err := mc.WriteFile(c.File); err != nil {
+		return nil, err
+	}
+	fmt.Fprintf(c.Out, "\nWrote %s. Push it with:\n\nmcli push --cluster %s --customer %s --input %s\n", c.File, c.Cluster, c.Customer, c.File)
+	return mc, nil
+}
+
+// Ask builds the management cluster definition from the answers.
+// Secrets are not part of the definition, they are read from the secret folder on push.
+func (c *Config) Ask(ctx context.Context) (*managementcluster.ManagementCluster, error) {
+	p := NewPrompt(c.In, c.Out)
+	var err error
+
+	c.Cluster, err = p.Required("Management cluster name", c.Cluster)
+	if err != nil {
+		return nil, err
+	}
+	c.Provider, err = p.Choice("Provider", key.GetValidProviders(), c.Provider)
+	if err != nil {
+		return nil, err
+	}
+	c.Customer, err = p.Required("Customer", defaultString(c.Customer, key.OrganizationGiantSwarm))
+	if err != nil {
+		return nil, err
+	}
+	c.CMCRepository, err = p.Required("CMC repository", defaultString(c.CMCRepository, key.GetCMCName(c.Customer)))
+	if err != nil {
+		return nil, err
+	}
+	c.BaseDomain, err = p.Required("Base domain", c.BaseDomain)
+	if err != nil {
+		return nil, err
+	}
+
+	reference := c.getReference(ctx)
+	if reference == nil {
+		reference = &Reference{
+			ClusterNamespace: DefaultClusterNamespace,
+			ClusterApp:       cmc.App{Catalog: DefaultCatalog},
+			DefaultApps:      cmc.App{Catalog: DefaultCatalog},
+		}
+	} else {
+		fmt.Fprintf(c.Out, "Using apps of %s as defaults.\n", reference.Cluster)
+	}
+
+	i, err := c.askInstallations(p)
+	if err != nil {
+		return nil, err
+	}
+	m, err := c.askCMC(p, reference)
+	if err != nil {
+		return nil, err
+	}
+	c.SecretFolder, err = p.Required("Secret folder", defaultString(c.SecretFolder, DefaultSecretFolder))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := i.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid installations configuration.\n%w", err)
+	}
+	return &managementcluster.ManagementCluster{
+		Installations: *i,
+		CMC:           *m,
+		SecretFolder:  c.SecretFolder,
+	}, nil
+}
+
+func (c *Config) askInstallations(p *Prompt) (*installations.Installations, error) {
+	team, err := p.Required("Team", "")
+	if err != nil {
+		return nil, err
+	}
+	pipeline, err := p.Required("Pipeline", DefaultPipeline)
+	if err != nil {
+		return nil, err
+	}
+	ccrRepository, err := p.Required("CCR repository", key.GetCCRName(c.Customer))
+	if err != nil {
+		return nil, err
+	}
+	config := installations.InstallationsConfig{
+		Base:            c.BaseDomain,
+		Codename:        c.Cluster,
+		Customer:        c.Customer,
+		CmcRepository:   c.CMCRepository,
+		CcrRepository:   ccrRepository,
+		AccountEngineer: team,
+		Pipeline:        pipeline,
+		Provider:        fmt.Sprintf("%s-test", c.Provider),
+	}
+	if key.IsProviderAWS(c.Provider) {
+		config.AwsRegion, err = p.Required("AWS region", "")
+		if err != nil {
+			return nil, err
+		}
+		config.AwsHostClusterAccount, err = p.Required("AWS account ID", "")
+		if err != nil {
+			return nil, err
+		}
+		config.AwsHostClusterAdminRoleArn = fmt.Sprintf("arn:aws:iam::%s:role/GiantSwarmAdmin", config.AwsHostClusterAccount)
+	}
+	return installations.NewInstallations(config), nil
+}
+
+func (c *Config) askCMC(p *Prompt, reference *Reference) (*cmc.CMC, error) {
+	var err error
+	m := &cmc.CMC{
+		Cluster:    c.Cluster,
+		BaseDomain: c.BaseDomain,
+		Provider: cmc.Provider{
+			Name: c.Provider,
+		},
+		GitOps: cmc.GitOps{
+			CMCRepository:         c.CMCRepository,
+			CMCBranch:             key.GetDefaultPRBranch(c.Cluster),
+			MCBBranchSource:       key.MCBMainBranch,
+			ConfigBranch:          key.GetDefaultConfigBranch(c.Cluster),
+			MCAppCollectionBranch: key.GetDefaultPRBranch(c.Cluster),
+		},
+		DisableDenyAllNetPol: !key.IsProviderAWS(c.Provider) && !key.IsProviderVsphere(c.Provider) && !key.IsProviderVCD(c.Provider),
+	}
+	m.AgePubKey, err = p.Required("Age public key", c.AgePubKey)
+	if err != nil {
+		return nil, err
+	}
+	m.ClusterNamespace, err = p.Required("Cluster namespace", reference.ClusterNamespace)
+	if err != nil {
+		return nil, err
+	}
+
+	m.ClusterApp, err = askApp(p, "Cluster app", reference.ClusterApp)
+	if err != nil {
+		return nil, err
+	}
+	m.ClusterApp.AppName = c.Cluster
+	m.ClusterIntegratesDefaultApps, err = p.Bool("Does the cluster app integrate the default apps?", reference.ClusterIntegratesDefaultApps)
+	if err != nil {
+		return nil, err
+	}
+	if !m.ClusterIntegratesDefaultApps {
+		m.DefaultApps, err = askApp(p, "Default apps", reference.DefaultApps)
+		if err != nil {
+			return nil, err
+		}
+		m.DefaultApps.AppName = fmt.Sprintf("%s-default-apps", c.Cluster)
+	}
+	m.MCAppsPreventDeletion, err = p.Bool("Prevent deletion of management cluster apps?", false)
+	if err != nil {
+		return nil, err
+	}
+
+	m.PrivateCA.Enabled, err = p.Bool("Use a private CA?", false)
+	if err != nil {
+		return nil, err
+	}
+	m.PrivateMC, err = p.Bool("Is the management cluster private?", false)
+	if err != nil {
+		return nil, err
+	}
+	m.MCProxy, err = askProxy(p)
+	if err != nil {
+		return nil, err
+	}
+	m.ConfigureContainerRegistries.Enabled, err = p.Bool("Configure container registries?", false)
+	if err != nil {
+		return nil, err
+	}
+	m.CertManagerDNSChallenge.Enabled, err = p.Bool("Use the cert-manager DNS01 challenge?", false)
+	if err != nil {
+		return nil, err
+	}
+	if m.CertManagerDNSChallenge.Enabled {
+		solver, err := p.Choice("DNS01 solver", certmanager.GetSolvers(), certmanager.SolverRoute53)
+		if err != nil {
+			return nil, err
+		}
+		if solver != certmanager.SolverRoute53 {
+			m.CertManagerDNSChallenge.Solver = solver
+		}
+	}
+	return m, nil
+}
+
+func askApp(p *Prompt, name string, def cmc.App) (cmc.App, error) {
+	var app cmc.App
+	var err error
+	app.Name, err = p.Required(fmt.Sprintf("%s name", name), def.Name)
+	if err != nil {
+		return cmc.App{}, err
+	}
+	app.Catalog, err = p.Required(fmt.Sprintf("%s catalog", name), def.Catalog)
+	if err != nil {
+		return cmc.App{}, err
+	}
+	app.Version, err = p.Required(fmt.Sprintf("%s version", name), def.Version)
+	if err != nil {
+		return cmc.App{}, err
+	}
+	return app, nil
+}
+
+func askProxy(p *Prompt) (cmc.MCProxy, error) {
+	enabled, err := p.Bool("Use a proxy?", false)
+	if err != nil || !enabled {
+		return cmc.MCProxy{}, err
+	}
+	httpsProxy, err := p.String("HTTPS proxy", "", func(answer string) error {
+		_, _, err := mcproxy.GetProxyFromURL(answer)
+		if err != nil {
+			return fmt.Errorf("invalid proxy %s. Expected format: http://<hostname>:<port>", answer)
+		}
+		return nil
+	})
+	if err != nil {
+		return cmc.MCProxy{}, err
+	}
+	hostname, port, err := mcproxy.GetProxyFromURL(httpsProxy)
+	if err != nil {
+		return cmc.MCProxy{}, err
+	}
+	noProxy, err := p.String("Comma separated NO_PROXY list", "", nil)
+	if err != nil {
+		return cmc.MCProxy{}, err
+	}
+	return cmc.MCProxy{
+		Enabled:    true,
+		Hostname:   hostname,
+		Port:       port,
+		HTTPSProxy: httpsProxy,
+		NoProxy:    splitList(noProxy),
+	}, nil
+}
+
+// getReference looks up an existing cluster of the provider. Failures are not fatal since it only provides defaults.
+func (c *Config) getReference(ctx context.Context) *Reference {
+	if c.Github == nil {
+		return nil
+	}
+	reference, err := c.GetReference(ctx, c.Provider)
+	if err != nil {
+		log.Debug().Msgf("failed to get reference cluster.\n%s", err)
+		return nil
+	}
+	return reference
+}
+
+func defaultString(value string, def string) string {
+	if value == "" {
+		return def
+	}
+	return value
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,184 @@
+package initialize
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/giantswarm/mcli/pkg/github/githubtest"
+	"github.com/giantswarm/mcli/pkg/key"
+	"github.com/giantswarm/mcli/pkg/managementcluster"
+	"github.com/giantswarm/mcli/pkg/managementcluster/cmc"
+	"github.com/giantswarm/mcli/pkg/managementcluster/cmc/apps"
+	"github.com/giantswarm/mcli/pkg/managementcluster/cmc/kustomization"
+)
+
+func TestRun(t *testing.T) {
+	testCases := []struct {
+		name        string
+		reference   bool
+		answers     []string
+		expectError error
+		expected    func(*managementcluster.ManagementCluster) error
+	}{
+		{
+			name:      "case 0: aws with reference cluster",
+			reference: true,
+			answers: []string{
+				"",                 // cluster name
+				"capa",             // provider
+				"",                 // customer
+				"",                 // cmc repository
+				"test.gigantic.io", // base domain
+				"team-rocket",      // team
+				"",                 // pipeline
+				"",                 // ccr repository
+				"eu-west-1",        // aws region
+				"123456789012",     // aws account
+				"age1key",          // age public key
+				"",                 // cluster namespace
+				"",                 // cluster app name
+				"",                 // cluster app catalog
+				"",                 // cluster app version
+				"",                 // cluster integrates default apps
+				"",                 // prevent deletion
+				"",                 // private CA
+				"y",                // private MC
+				"",                 // proxy
+				"",                 // container registries
+				"yes",              // cert-manager DNS01
+				"cloudflare",       // DNS01 solver
+				"",                 // secret folder
+			},
+			expected: func(mc *managementcluster.ManagementCluster) error {
+				if mc.CMC.ClusterApp.Name != "cluster-aws" || mc.CMC.ClusterApp.Version != "1.0.0" || mc.CMC.ClusterApp.AppName != "gigmac" {
+					return fmt.Errorf("cluster app not taken from reference: %v", mc.CMC.ClusterApp)
+				}
+				if !mc.CMC.ClusterIntegratesDefaultApps || mc.CMC.ClusterNamespace != "org-reference" {
+					return fmt.Errorf("defaults not taken from reference")
+				}
+				if !mc.CMC.PrivateMC || mc.CMC.CertManagerDNSChallenge.Solver != "cloudflare" {
+					return fmt.Errorf("toggles not set")
+				}
+				if mc.Installations.Aws.HostCluster.AdminRoleArn != "arn:aws:iam::123456789012:role/GiantSwarmAdmin" {
+					return fmt.Errorf("unexpected admin role %s", mc.Installations.Aws.HostCluster.AdminRoleArn)
+				}
+				if mc.SecretFolder != DefaultSecretFolder || mc.CMC.AgePubKey != "age1key" {
+					return fmt.Errorf("unexpected secret folder %s", mc.SecretFolder)
+				}
+				return nil
+			},
+		},
+		{
+			name: "case 1: vsphere with default apps and proxy",
+			answers: []string{
+				"",
+				"4",
+				"",
+				"",
+				"test.gigantic.io",
+				"team-rocket",
+				"",
+				"",
+				"age1key",
+				"",
+				"cluster-vsphere",
+				"",
+				"0.60.0",
+				"maybe", // invalid answer is asked again
+				"n",
+				"default-apps-vsphere",
+				"",
+				"0.15.0",
+				"",
+				"",
+				"",
+				"y",
+				"proxy.example.com", // invalid proxy is asked again
+				"http://proxy.example.com:3128",
+				"10.0.0.0/8, .internal",
+				"",
+				"",
+				"../secrets/gigmac",
+			},
+			expected: func(mc *managementcluster.ManagementCluster) error {
+				if mc.CMC.ClusterIntegratesDefaultApps || mc.CMC.DefaultApps.AppName != "gigmac-default-apps" || mc.CMC.DefaultApps.Catalog != DefaultCatalog {
+					return fmt.Errorf("unexpected default apps %v", mc.CMC.DefaultApps)
+				}
+				if mc.CMC.Provider.Name != key.ProviderVsphere || mc.CMC.DisableDenyAllNetPol {
+					return fmt.Errorf("unexpected provider %s", mc.CMC.Provider.Name)
+				}
+				expected := cmc.MCProxy{Enabled: true, Hostname: "proxy.example.com", Port: "3128", HTTPSProxy: "http://proxy.example.com:3128", NoProxy: []string{"10.0.0.0/8", ".internal"}}
+				if fmt.Sprint(mc.CMC.MCProxy) != fmt.Sprint(expected) {
+					return fmt.Errorf("expected proxy %v but got %v", expected, mc.CMC.MCProxy)
+				}
+				return nil
+			},
+		},
+		{
+			name:        "case 2: aborted",
+			answers:     []string{"", "capa"},
+			expectError: ErrAborted,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := Config{
+				Cluster: "gigmac",
+				File:    filepath.Join(t.TempDir(), "gigmac.yaml"),
+				In:      strings.NewReader(strings.Join(tc.answers, "\n") + "\n"),
+				Out:     &bytes.Buffer{},
+			}
+			if tc.reference {
+				server := githubtest.NewServer()
+				defer server.Close()
+				server.AddRepository(key.OrganizationGiantSwarm, key.GetCMCName(key.OrganizationGiantSwarm), getReferenceFiles(t))
+				c.Github = server.Client()
+			}
+
+			mc, err := c.Run(context.Background())
+			if tc.expectError != nil {
+				if !errors.Is(err, tc.expectError) {
+					t.Fatalf("expected %v but got %v", tc.expectError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v\n%s", err, c.Out)
+			}
+			if err := tc.expected(mc); err != nil {
+				t.Fatal(err)
+			}
+			written, err := managementcluster.GetManagementClusterFromFile(c.File)
+			if err != nil {
+				t.Fatalf("unexpected error reading %s: %v", c.File, err)
+			}
+			if written.SecretFolder != mc.SecretFolder || written.CMC.Cluster != "gigmac" {
+				t.Fatalf("written file does not match the answers")
+			}
+		})
+	}
+}
+
+func getReferenceFiles(t *testing.T) map[string]string {
+	file, err := apps.GetClusterAppsFile(apps.Config{
+		Cluster:   "reference",
+		Name:      "reference",
+		AppName:   "cluster-aws",
+		Catalog:   "cluster",
+		Version:   "1.0.0",
+		Namespace: "org-reference",
+		Values:    "global:\n  metadata:\n    name: reference\n",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return map[string]string{
+		fmt.Sprintf("%s/%s", key.GetCMCPath("reference"), kustomization.ClusterAppsFile): file,
+	}
+}
@@ -0,0 +1,116 @@
+package initialize
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+type Prompt struct {
+	reader *bufio.Reader
+	out    io.Writer
+}
+
+func NewPrompt(in io.Reader, out io.Writer) *Prompt {
+	return &Prompt{
+		reader: bufio.NewReader(in),
+		out:    out,
+	}
+}
+
+// String asks until the answer is valid. An empty answer selects the default.
+func (p *Prompt) String(question string, def string, validate func(string) error) (string, error) {
+	for {
+		if def != "" {
+			fmt.Fprintf(p.out, "%s [%s]: ", question, def)
+		} else {
+			fmt.Fprintf(p.out, "%s: ", question)
+		}
+		answer, err := p.readLine()
+		if err != nil {
+			return "", err
+		}
+		if answer == "" {
+			answer = def
+		}
+		if validate == nil {
+			return answer, nil
+		}
+		if err := validate(answer); err != nil {
+			fmt.Fprintf(p.out, "%s\n", err)
+			continue
+		}
+		return answer, nil
+	}
+}
+
+// Required asks until the answer is not empty.
+func (p *Prompt) Required(question string, def string) (string, error) {
+	return p.String(question, def, func(answer string) error {
+		if answer == "" {
+			return fmt.Errorf("a value is required")
+		}
+		return nil
+	})
+}
+
+func (p *Prompt) Bool(question string, def bool) (bool, error) {
+	options := "y/N"
+	if def {
+		options = "Y/n"
+	}
+	for {
+		fmt.Fprintf(p.out, "%s [%s]: ", question, options)
+		answer, err := p.readLine()
+		if err != nil {
+			return false, err
+		}
+		switch strings.ToLower(answer) {
+		case "":
+			return def, nil
+		case "y", "yes":
+			return true, nil
+		case "n", "no":
+			return false, nil
+		}
+		fmt.Fprintf(p.out, "please answer yes or no\n")
+	}
+}
+
+// Choice asks for one of the options either by name or by number.
+func (p *Prompt) Choice(question string, options []string, def string) (string, error) {
+	for i, option := range options {
+		fmt.Fprintf(p.out, "  %d) %s\n", i+1, option)
+	}
+	answer, err := p.String(question, def, func(answer string) error {
+		answer = resolveChoice(answer, options)
+		for _, option := range options {
+			if answer == option {
+				return nil
+			}
+		}
+		return fmt.Errorf("invalid choice %s. Valid values: %v", answer, options)
+	})
+	if err != nil {
+		return "", err
+	}
+	return resolveChoice(answer, options), nil
+}
+
+func (p *Prompt) readLine() (string, error) {
+	line, err := p.reader.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return "", fmt.Errorf("failed to read answer.\n%w", ErrAborted)
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// resolveChoice maps the number of an option to the option.
+func resolveChoice(answer string, options []string) string {
+	if i, err := strconv.Atoi(answer); err == nil && i > 0 && i <= len(options) {
+		return options[i-1]
+	}
+	return answer
+}
@@ -0,0 +1,83 @@
+package initialize
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/giantswarm/mcli/pkg/github"
+	"github.com/giantswarm/mcli/pkg/key"
+	"github.com/giantswarm/mcli/pkg/managementcluster/cmc"
+	"github.com/giantswarm/mcli/pkg/managementcluster/cmc/apps"
+	"github.com/giantswarm/mcli/pkg/managementcluster/cmc/kustomization"
+)
+
+// Reference is an existing management cluster whose apps are used as defaults.
+type Reference struct {
+	Cluster                      string
+	ClusterNamespace             string
+	ClusterApp                   cmc.App
+	DefaultApps                  cmc.App
+	ClusterIntegratesDefaultApps bool
+}
+
+// GetReference returns the first management cluster of the provider in the CMC repository.
+// Only the app manifests are read, they are not encrypted.
+func (c *Config) GetReference(ctx context.Context, provider string) (*Reference, error) {
+	cmcRepository := github.Repository{
+		Github:       c.Github,
+		Name:         c.CMCRepository,
+		Organization: key.OrganizationGiantSwarm,
+		Branch:       key.CMCMainBranch,
+	}
+	clusters, err := cmcRepository.GetDirectoryNames(ctx, key.CMCClustersPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list management clusters in %s.\n%w", c.CMCRepository, err)
+	}
+	for _, cluster := range clusters {
+		if cluster == c.Cluster {
+			continue
+		}
+		path := key.GetCMCPath(cluster)
+		file, err := cmcRepository.GetFile(ctx, fmt.Sprintf("%s/%s", path, kustomization.ClusterAppsFile))
+		if err != nil {
+			log.Debug().Msgf("skipping %s as reference.\n%s", cluster, err)
+			continue
+		}
+		clusterApp, err := apps.GetAppsConfig(file)
+		if err != nil || clusterApp.Provider != provider {
+			continue
+		}
+		reference := &Reference{
+			Cluster:                      cluster,
+			ClusterNamespace:             clusterApp.Namespace,
+			ClusterApp:                   getReferenceApp(clusterApp),
+			ClusterIntegratesDefaultApps: true,
+		}
+		file, err = cmcRepository.GetFile(ctx, fmt.Sprintf("%s/%s", path, kustomization.DefaultAppsFile))
+		if err == nil {
+			defaultApps, err := apps.GetAppsConfig(file)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get default apps of %s.\n%w", cluster, err)
+			}
+			reference.DefaultApps = getReferenceApp(defaultApps)
+			reference.ClusterIntegratesDefaultApps = false
+		} else if !github.IsNotFound(err) {
+			return nil, fmt.Errorf("failed to get default apps of %s.\n%w", cluster, err)
+		}
+		log.Debug().Msgf("using %s as reference for %s", cluster, provider)
+		return reference, nil
+	}
+	return nil, nil
+}
+
+// getReferenceApp copies the chart of the app of the reference.
+// The chart name is the app name of the parsed CR.
+func getReferenceApp(c apps.Config) cmc.App {
+	return cmc.App{
+		Name:    c.AppName,
+		Catalog: c.Catalog,
+		Version: c.Version,
+	}
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/mcli/cmd/list"
+	"github.com/giantswarm/mcli/pkg/github"
+)
+
+// listCmd represents the list command
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Lists the Management Clusters of the installations repository",
+	Long: `Lists the Management Clusters of the installations repository with their customer,
+provider, pipeline, CMC repository and base domain. Clusters can be filtered by field
+with shell wildcard patterns and sorted by field. For example:
+
+mcli list
+
+mcli list --filter customer=giantswarm --filter provider=capa --sort-by pipeline
+
+mcli list --output csv > clusters.csv`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		defaultList()
+		err := validateList(cmd, args)
+		if err != nil {
+			return err
+		}
+		ctx := context.Background()
+		client := github.New(github.Config{
+			Token: githubToken,
+		})
+		c := list.Config{
+			Github:              client,
+			InstallationsBranch: installationsBranch,
+			Filters:             listFilters,
+			SortBy:              listSortBy,
+			Output:              listOutput,
+		}
+		entries, err := c.Run(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list management clusters.\n%w", err)
+		}
+		return list.Print(os.Stdout, entries, listOutput)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(listCmd)
+	addFlagsList()
+}
@@ -0,0 +1,7 @@
+package list
+
+import (
+	"errors"
+)
+
+var ErrInvalidFlag = errors.New("invalid flag")
@@ -0,0 +1,228 @@
+package list
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/giantswarm/mcli/pkg/github"
+	"github.com/giantswarm/mcli/pkg/key"
+	"github.com/giantswarm/mcli/pkg/managementcluster/installations"
+)
+
+const (
+	OutputTable = "table"
+	OutputJSON  = key.OutputJSON
+	OutputCSV   = "csv"
+)
+
+const (
+	FieldCodename      = "codename"
+	FieldCustomer      = "customer"
+	FieldProvider      = "provider"
+	FieldPipeline      = "pipeline"
+	FieldCMCRepository = "cmcRepository"
+	FieldBaseDomain    = "baseDomain"
+)
+
+type Config struct {
+	Github              *github.Github
+	InstallationsBranch string
+	// Filters are field=pattern pairs. Patterns may contain shell wildcards. All filters have to match.
+	Filters []string
+	SortBy  string
+	Output  string
+}
+
+type Entry struct {
+	Codename      string `json:"codename"`
+	Customer      string `json:"customer"`
+	Provider      string `json:"provider"`
+	Pipeline      string `json:"pipeline"`
+	CMCRepository string `json:"cmcRepository"`
+	BaseDomain    string `json:"baseDomain"`
+}
+
+func (c *Config) Run(ctx context.Context) ([]Entry, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	repository := github.Repository{
+		Github:       c.Github,
+		Name:         key.RepositoryInstallations,
+		Organization: key.OrganizationGiantSwarm,
+		Branch:       c.InstallationsBranch,
+	}
+	if err := repository.Check(ctx); err != nil {
+		return nil, err
+	}
+	files, err := repository.GetFileNames(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files of %s.\n%w", key.RepositoryInstallations, err)
+	}
+
+	var entries []Entry
+	for _, file := range files {
+		cluster, ok := key.GetClusterFromInstallationsPath(file)
+		if !ok {
+			continue
+		}
+		data, err := repository.GetFile(ctx, file)
+		if err != nil {
+			return nil, err
+		}
+		i, err := installations.GetInstallations([]byte(data))
+		if err != nil {
+			log.Debug().Msgf("skipping %s.\n%s", file, err)
+			continue
+		}
+		entry := Entry{
+			Codename:      i.Codename,
+			Customer:      i.Customer,
+			Provider:      i.Provider,
+			Pipeline:      i.Pipeline,
+			CMCRepository: i.CmcRepository,
+			BaseDomain:    i.Base,
+		}
+		if entry.Codename == "" {
+			entry.Codename = cluster
+		}
+		match, err := c.matches(entry)
+		if err != nil {
+			return nil, err
+		}
+		if match {
+			entries = append(entries, entry)
+		}
+	}
+	Sort(entries, c.SortBy)
+	return entries, nil
+}
+
+func (c *Config) Validate() error {
+	if !isValid(c.SortBy, GetFields()) {
+		return fmt.Errorf("sort by %s is invalid. Valid values: %v\n%w", c.SortBy, GetFields(), ErrInvalidFlag)
+	}
+	if !isValid(c.Output, GetValidOutputs()) {
+		return fmt.Errorf("output %s is invalid. Valid values: %v\n%w", c.Output, GetValidOutputs(), ErrInvalidFlag)
+	}
+	for _, filter := range c.Filters {
+		field, pattern, ok := strings.Cut(filter, "=")
+		if !ok || !isValid(field, GetFields()) {
+			return fmt.Errorf("filter %s is invalid. Expected format: field=pattern with fields %v\n%w", filter, GetFields(), ErrInvalidFlag)
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("filter %s is invalid.\n%w\n%w", filter, err, ErrInvalidFlag)
+		}
+	}
+	return nil
+}
+
+func (c *Config) matches(entry Entry) (bool, error) {
+	for _, filter := range c.Filters {
+		field, pattern, _ := strings.Cut(filter, "=")
+		ok, err := path.Match(pattern, entry.Get(field))
+		if err != nil {
+			return false, err
+		}
+		if !ok {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func (e Entry) Get(field string) string {
+	switch field {
+	case FieldCustomer:
+		return e.Customer
+	case FieldProvider:
+		return e.Provider
+	case FieldPipeline:
+		return e.Pipeline
+	case FieldCMCRepository:
+		return e.CMCRepository
+	case FieldBaseDomain:
+		return e.BaseDomain
+	default:
+		return e.Codename
+	}
+}
+
+func GetFields() []string {
+	return []string{FieldCodename, FieldCustomer, FieldProvider, FieldPipeline, FieldCMCRepository, FieldBaseDomain}
+}
+
+func GetValidOutputs() []string {
+	return []string{OutputTable, OutputJSON, OutputCSV}
+}
+
+func isValid(value string, valid []string) bool {
+	for _, v := range valid {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Sort sorts the entries by the field and then by codename.
+func Sort(entries []Entry, sortBy string) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i].Get(sortBy), entries[j].Get(sortBy)
+		if a != b {
+			return a < b
+		}
+		return entries[i].Codename < entries[j].Codename
+	})
+}
+
+func Print(w io.Writer, entries []Entry, output string) error {
+	switch output {
+	case OutputJSON:
+		if entries == nil {
+			entries = []Entry{}
+		}
+		data, err := json.MarshalIndent(entries, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal management clusters.\n%w", err)
+		}
+		_, err = fmt.Fprintln(w, string(data))
+		return err
+	case OutputCSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write(GetFields()); err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			if err := cw.Write(entry.values()); err != nil {
+				return err
+			}
+		}
+		cw.Flush()
+		return cw.Error()
+	default:
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "CODENAME\tCUSTOMER\tPROVIDER\tPIPELINE\tCMC REPOSITORY\tBASE DOMAIN")
+		for _, entry := range entries {
+			fmt.Fprintln(tw, strings.Join(entry.values(), "\t"))
+		}
+		return tw.Flush()
+	}
+}
+
+func (e Entry) values() []string {
+	var values []string
+	for _, field := range GetFields() {
+		values = append(values, e.Get(field))
+	}
+	return values
+}
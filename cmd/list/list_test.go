@@ -0,0 +1,144 @@
+package list
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/giantswarm/mcli/pkg/github/githubtest"
+	"github.com/giantswarm/mcli/pkg/key"
+)
+
+func TestRun(t *testing.T) {
+	testCases := []struct {
+		name    string
+		filters []string
+		sortBy  string
+
+		expectCodenames []string
+		expectError     error
+	}{
+		{
+			name:   "case 0: all clusters by codename",
+			sortBy: FieldCodename,
+
+			expectCodenames: []string{"gazelle", "gigmac", "golem"},
+		},
+		{
+			name:   "case 1: sorted by provider",
+			sortBy: FieldProvider,
+
+			expectCodenames: []string{"gazelle", "golem", "gigmac"},
+		},
+		{
+			name:    "case 2: filtered by customer and codename pattern",
+			filters: []string{"customer=giantswarm", "codename=g*"},
+			sortBy:  FieldCodename,
+
+			expectCodenames: []string{"gigmac", "golem"},
+		},
+		{
+			name:    "case 3: invalid filter",
+			filters: []string{"unknown=x"},
+			sortBy:  FieldCodename,
+
+			expectError: ErrInvalidFlag,
+		},
+		{
+			name:   "case 4: invalid sort field",
+			sortBy: "unknown",
+
+			expectError: ErrInvalidFlag,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := githubtest.NewServer()
+			defer server.Close()
+			server.AddBranchRepository(key.OrganizationGiantSwarm, key.RepositoryInstallations, key.InstallationsMainBranch, map[string]string{
+				"README.md":                "installations\n",
+				key.SchemaFile:             "{}\n",
+				"gigmac/cluster.yaml":      "codename: gigmac\ncustomer: giantswarm\nprovider: capz\npipeline: testing\ncmc_repository: giantswarm-management-clusters\nbase: gigmac.example.com\n",
+				"golem/cluster.yaml":       "codename: golem\ncustomer: giantswarm\nprovider: capa\npipeline: stable\ncmc_repository: giantswarm-management-clusters\nbase: golem.example.com\n",
+				"gazelle/cluster.yaml":     "codename: gazelle\ncustomer: acme\nprovider: capa\npipeline: stable\ncmc_repository: acme-management-clusters\nbase: gazelle.example.com\n",
+				"golem/other/cluster.yaml": "codename: nested\n",
+			})
+			c := Config{
+				Github:              server.Client(),
+				InstallationsBranch: key.InstallationsMainBranch,
+				Filters:             tc.filters,
+				SortBy:              tc.sortBy,
+				Output:              OutputTable,
+			}
+			entries, err := c.Run(context.Background())
+			if tc.expectError != nil {
+				if !errors.Is(err, tc.expectError) {
+					t.Fatalf("expected %v but got %v", tc.expectError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var codenames []string
+			for _, entry := range entries {
+				codenames = append(codenames, entry.Codename)
+			}
+			if !reflect.DeepEqual(codenames, tc.expectCodenames) {
+				t.Fatalf("expected %v, got %v", tc.expectCodenames, codenames)
+			}
+		})
+	}
+}
+
+func TestPrint(t *testing.T) {
+	entries := []Entry{
+		{
+			Codename:      "gigmac",
+			Customer:      "giantswarm",
+			Provider:      "capz",
+			Pipeline:      "testing",
+			CMCRepository: "giantswarm-management-clusters",
+			BaseDomain:    "gigmac.example.com",
+		},
+	}
+	testCases := []struct {
+		name   string
+		output string
+
+		expected string
+	}{
+		{
+			name:   "case 0: table",
+			output: OutputTable,
+
+			expected: "CODENAME  CUSTOMER    PROVIDER  PIPELINE  CMC REPOSITORY                  BASE DOMAIN\ngigmac    giantswarm  capz      testing   giantswarm-management-clusters  gigmac.example.com\n",
+		},
+		{
+			name:   "case 1: csv",
+			output: OutputCSV,
+
+			expected: "codename,customer,provider,pipeline,cmcRepository,baseDomain\ngigmac,giantswarm,capz,testing,giantswarm-management-clusters,gigmac.example.com\n",
+		},
+		{
+			name:   "case 2: json",
+			output: OutputJSON,
+
+			expected: "[\n  {\n    \"codename\": \"gigmac\",\n    \"customer\": \"giantswarm\",\n    \"provider\": \"capz\",\n    \"pipeline\": \"testing\",\n    \"cmcRepository\": \"giantswarm-management-clusters\",\n    \"baseDomain\": \"gigmac.example.com\"\n  }\n]\n",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var b bytes.Buffer
+			if err := Print(&b, entries, tc.output); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if b.String() != tc.expected {
+				t.Fatalf("expected %q but got %q", tc.expected, b.String())
+			}
+		})
+	}
+}
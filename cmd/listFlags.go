@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/mcli/cmd/list"
+	"github.com/giantswarm/mcli/pkg/key"
+)
+
+const (
+	flagFilter = "filter"
+)
+
+var (
+	listFilters []string
+	listSortBy  string
+	listOutput  string
+)
+
+func addFlagsList() {
+	listCmd.Flags().StringArrayVar(&listFilters, flagFilter, []string{}, fmt.Sprintf("Filter as field=pattern. Patterns may contain shell wildcards. Valid fields: %v", list.GetFields()))
+	listCmd.Flags().StringVar(&listSortBy, flagSortBy, list.FieldCodename, fmt.Sprintf("Field to sort by. Valid values: %v", list.GetFields()))
+	listCmd.Flags().StringVarP(&listOutput, flagOutput, "o", list.OutputTable, fmt.Sprintf("Output format. Valid values: %v", list.GetValidOutputs()))
+}
+
+func defaultList() {
+	if installationsBranch == "" {
+		installationsBranch = key.InstallationsMainBranch
+	}
+}
+
+func validateList(cmd *cobra.Command, args []string) error {
+	if githubToken == "" {
+		return invalidFlagError(flagGithubToken)
+	}
+	return nil
+}
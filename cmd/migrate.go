@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/mcli/cmd/migrate"
+	"github.com/giantswarm/mcli/pkg/github"
+)
+
+// migrateCmd represents the migrate command
+var migrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Moves a Management Cluster to a different CMC repository",
+	Long: `Moves the CMC entry of a Management Cluster to a different CMC repository.
+The entry is read from the current CMC repository, re-encrypted with a new SOPS
+creation rule in the new repository and the CMC repository of the installations
+entry is updated. Pull requests to remove the entry from the old repository are
+prepared. For example:
+
+mcli migrate --cluster=gigmac --to-cmc-repository=acme-management-clusters
+
+mcli migrate --cluster=gigmac --to-cmc-repository=acme-management-clusters --age-pub-key=age1...`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		defaultMigrate()
+		err := validateMigrate(cmd, args)
+		if err != nil {
+			return err
+		}
+		ctx := context.Background()
+		client := github.New(github.Config{
+			Token: githubToken,
+		})
+		c := migrate.Config{
+			Cluster:             cluster,
+			ToCMCRepository:     toCMCRepository,
+			AgePubKey:           agePubKey,
+			Github:              client,
+			CMCBranch:           cmcBranch,
+			InstallationsBranch: installationsBranch,
+			Out:                 os.Stdout,
+		}
+		summary, err := c.Run(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to migrate management cluster.\n%w", err)
+		}
+		if err := summary.Print(); err != nil {
+			return err
+		}
+		return writeAudit(ctx, cmd, client, toCMCRepository, summary)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(migrateCmd)
+	addFlagsMigrate()
+}
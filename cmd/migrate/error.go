@@ -0,0 +1,7 @@
+package migrate
+
+import (
+	"errors"
+)
+
+var ErrInvalidFlag = errors.New("invalid flag")
@@ -0,0 +1,125 @@
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/giantswarm/mcli/cmd/decommission"
+	pullcmc "github.com/giantswarm/mcli/cmd/pull/cmc"
+	pullinstallations "github.com/giantswarm/mcli/cmd/pull/installations"
+	pushcmc "github.com/giantswarm/mcli/cmd/push/cmc"
+	pushinstallations "github.com/giantswarm/mcli/cmd/push/installations"
+	"github.com/giantswarm/mcli/pkg/github"
+	"github.com/giantswarm/mcli/pkg/key"
+	"github.com/giantswarm/mcli/pkg/managementcluster"
+)
+
+type Config struct {
+	Cluster         string
+	ToCMCRepository string
+	// AgePubKey replaces the age key of the cluster if the new repository uses a different one.
+	AgePubKey           string
+	Github              *github.Github
+	CMCBranch           string
+	InstallationsBranch string
+	Out                 io.Writer
+}
+
+// Run moves the CMC entry of the cluster to the new repository and prepares its removal from the old one.
+func (c *Config) Run(ctx context.Context) (*managementcluster.Summary, error) {
+	log.Debug().Msgf("migrating management cluster %s to %s", c.Cluster, c.ToCMCRepository)
+	summary := &managementcluster.Summary{
+		Cluster: c.Cluster,
+	}
+
+	pullInstallations := pullinstallations.Config{
+		Cluster:             c.Cluster,
+		Github:              c.Github,
+		InstallationsBranch: key.InstallationsMainBranch,
+	}
+	i, err := pullInstallations.Run(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pull installations.\n%w", err)
+	}
+	from := i.CmcRepository
+	if from == "" || from == c.ToCMCRepository {
+		return nil, fmt.Errorf("management cluster %s is already in %s.\n%w", c.Cluster, c.ToCMCRepository, ErrInvalidFlag)
+	}
+
+	// the removal is checked first so that nothing is pushed if the old entry is still referenced
+	remove := decommission.Config{
+		Cluster:       c.Cluster,
+		CMCRepository: from,
+		Github:        c.Github,
+		Skip:          []string{key.RepositoryInstallations},
+		Confirmation:  c.Cluster,
+		Out:           c.Out,
+	}
+	if _, err := remove.GetPlan(ctx); err != nil {
+		return nil, fmt.Errorf("failed to check removal from %s.\n%w", from, err)
+	}
+
+	pullCMC := pullcmc.Config{
+		Cluster:        c.Cluster,
+		Github:         c.Github,
+		CMCRepository:  from,
+		CMCBranch:      key.CMCMainBranch,
+		DisplaySecrets: true,
+	}
+	current, err := pullCMC.Run(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pull %s entry.\n%w", from, err)
+	}
+	mc := &managementcluster.ManagementCluster{
+		Installations: *i,
+		CMC:           *current,
+	}
+	Migrate(mc, c.ToCMCRepository, c.AgePubKey)
+
+	pushCMC := pushcmc.Config{
+		Cluster:       c.Cluster,
+		Github:        c.Github,
+		CMCRepository: c.ToCMCRepository,
+		CMCBranch:     c.CMCBranch,
+		Input:         &mc.CMC,
+	}
+	_, result, err := pushCMC.Run(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to push %s entry.\n%w", c.ToCMCRepository, err)
+	}
+	summary.Repositories = append(summary.Repositories, result)
+
+	pushInstallations := pushinstallations.Config{
+		Cluster:             c.Cluster,
+		Github:              c.Github,
+		InstallationsBranch: c.InstallationsBranch,
+		CMCRepository:       c.ToCMCRepository,
+		Input:               &mc.Installations,
+	}
+	_, result, err = pushInstallations.Run(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to push installations.\n%w", err)
+	}
+	summary.Repositories = append(summary.Repositories, result)
+
+	results, err := remove.Run(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare removal from %s.\n%w", from, err)
+	}
+	for _, r := range results {
+		summary.Repositories = append(summary.Repositories, &managementcluster.RepositorySummary{Result: *r})
+	}
+	return summary, nil
+}
+
+// Migrate points the configuration to the new CMC repository.
+func Migrate(mc *managementcluster.ManagementCluster, cmcRepository string, agePubKey string) {
+	mc.Installations.CmcRepository = cmcRepository
+	mc.CMC.GitOps.CMCRepository = cmcRepository
+	if agePubKey != "" {
+		mc.CMC.AgePubKey = agePubKey
+	}
+}
@@ -0,0 +1,63 @@
+package migrate
+
+import (
+	"testing"
+
+	"github.com/giantswarm/mcli/pkg/managementcluster"
+	"github.com/giantswarm/mcli/pkg/managementcluster/cmc"
+	"github.com/giantswarm/mcli/pkg/managementcluster/installations"
+)
+
+func TestMigrate(t *testing.T) {
+	testCases := []struct {
+		name      string
+		agePubKey string
+
+		expectedAgePubKey string
+	}{
+		{
+			name: "case 0: keep age key",
+
+			expectedAgePubKey: "age1old",
+		},
+		{
+			name:      "case 1: replace age key",
+			agePubKey: "age1new",
+
+			expectedAgePubKey: "age1new",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mc := &managementcluster.ManagementCluster{
+				Installations: installations.Installations{
+					Codename:      "gigmac",
+					CmcRepository: "giantswarm-management-clusters",
+				},
+				CMC: cmc.CMC{
+					Cluster:   "gigmac",
+					AgePubKey: "age1old",
+					GitOps: cmc.GitOps{
+						CMCRepository: "giantswarm-management-clusters",
+						CMCBranch:     "gigmac_auto_branch",
+					},
+				},
+			}
+			Migrate(mc, "acme-management-clusters", tc.agePubKey)
+
+			if mc.Installations.CmcRepository != "acme-management-clusters" {
+				t.Fatalf("expected installations cmc repository to be migrated, got %s", mc.Installations.CmcRepository)
+			}
+			if mc.CMC.GitOps.CMCRepository != "acme-management-clusters" {
+				t.Fatalf("expected gitops cmc repository to be migrated, got %s", mc.CMC.GitOps.CMCRepository)
+			}
+			if mc.CMC.GitOps.CMCBranch != "gigmac_auto_branch" {
+				t.Fatalf("expected cmc branch to be kept, got %s", mc.CMC.GitOps.CMCBranch)
+			}
+			if mc.CMC.AgePubKey != tc.expectedAgePubKey {
+				t.Fatalf("expected age key %s, got %s", tc.expectedAgePubKey, mc.CMC.AgePubKey)
+			}
+		})
+	}
+}
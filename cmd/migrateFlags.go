@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/giantswarm/mcli/pkg/key"
+)
+
+const (
+	flagToCMCRepository = "to-cmc-repository"
+)
+
+var (
+	toCMCRepository string
+)
+
+func addFlagsMigrate() {
+	viper.AutomaticEnv()
+
+	migrateCmd.Flags().StringVar(&toCMCRepository, flagToCMCRepository, "", "Name of the CMC repository to move the management cluster to")
+	migrateCmd.Flags().StringVar(&agePubKey, flagAgePubKey, viper.GetString(envAgePubKey), "Age public key of the cluster in the new CMC repository. (default: current age public key)")
+}
+
+func defaultMigrate() {
+	if installationsBranch == "" {
+		installationsBranch = key.GetDefaultPRBranch(cluster)
+	}
+	if cmcBranch == "" {
+		cmcBranch = key.GetDefaultPRBranch(cluster)
+	}
+}
+
+func validateMigrate(cmd *cobra.Command, args []string) error {
+	if cluster == "" {
+		return invalidFlagError(flagCluster)
+	}
+	if githubToken == "" {
+		return invalidFlagError(flagGithubToken)
+	}
+	if toCMCRepository == "" {
+		return invalidFlagError(flagToCMCRepository)
+	}
+	return nil
+}
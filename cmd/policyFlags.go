@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/mcli/pkg/github"
+	"github.com/giantswarm/mcli/pkg/policy"
+)
+
+const (
+	flagPolicy = "policy"
+)
+
+var (
+	policySources []string
+)
+
+func addFlagPolicy(cmd *cobra.Command) {
+	cmd.Flags().StringArrayVar(&policySources, flagPolicy, []string{}, fmt.Sprintf("Policy file or directory to evaluate before pushing. Sources prefixed with %s are read from the CMC repository. Can be repeated. (default: none)", policy.CMCPrefix))
+}
+
+func hasCMCPolicySource() bool {
+	for _, source := range policySources {
+		if strings.HasPrefix(source, policy.CMCPrefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// loadPolicy returns nil if no policy is given.
+func loadPolicy(ctx context.Context, client *github.Github, cmcRepository string) (*policy.Policy, error) {
+	if len(policySources) == 0 {
+		return nil, nil
+	}
+	c := policy.Config{
+		Sources:       policySources,
+		Github:        client,
+		CMCRepository: cmcRepository,
+	}
+	p, err := c.Load(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load policy.\n%w", err)
+	}
+	return p, nil
+}
@@ -22,7 +22,9 @@ var pullCmd = &cobra.Command{
 	Long: `Pulls the current configuration of a Management Cluster from all
 relevant git repositories. For example:
 
-mcli pull --cluster=gigmac`,
+mcli pull --cluster=gigmac
+
+mcli pull --cluster=gigmac --skip=installations --ref=4f2c1a9`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		defaultPull()
 		err := validateRoot(cmd, args)
@@ -38,6 +40,7 @@ mcli pull --cluster=gigmac`,
 			CMCRepository:       cmcRepository,
 			Skip:                skip,
 			DisplaySecrets:      displaySecrets,
+			Ref:                 pullRef,
 		}
 		err = pull.Run(c, ctx)
 		if err != nil {
@@ -69,6 +72,7 @@ mcli pull installations --cluster=gigmac`,
 			Cluster:             cluster,
 			Github:              client,
 			InstallationsBranch: installationsBranch,
+			Ref:                 pullRef,
 		}
 		installations, err := i.Run(ctx)
 		if err != nil {
@@ -101,6 +105,7 @@ mcli pull cmc --cluster=gigmac`,
 			CMCRepository:  cmcRepository,
 			CMCBranch:      cmcBranch,
 			DisplaySecrets: displaySecrets,
+			Ref:            pullRef,
 		}
 		cmc, err := c.Run(ctx)
 		if err != nil {
@@ -19,6 +19,8 @@ type Config struct {
 	CMCRepository  string
 	CMCBranch      string
 	DisplaySecrets bool
+	// Ref is pulled instead of the head of the branch, e.g. a commit SHA or tag.
+	Ref string
 }
 
 func (c *Config) Run(ctx context.Context) (*cmc.CMC, error) {
@@ -29,6 +31,7 @@ func (c *Config) Run(ctx context.Context) (*cmc.CMC, error) {
 		Name:         c.CMCRepository,
 		Organization: key.OrganizationGiantSwarm,
 		Branch:       c.CMCBranch,
+		Ref:          c.Ref,
 	}
 	if err := cmcRepository.Check(ctx); err != nil {
 		return nil, err
@@ -15,6 +15,8 @@ type Config struct {
 	Cluster             string
 	Github              *github.Github
 	InstallationsBranch string
+	// Ref is pulled instead of the head of the branch, e.g. a commit SHA or tag.
+	Ref string
 }
 
 func (c *Config) Run(ctx context.Context) (*installations.Installations, error) {
@@ -25,6 +27,7 @@ func (c *Config) Run(ctx context.Context) (*installations.Installations, error)
 		Name:         key.RepositoryInstallations,
 		Organization: key.OrganizationGiantSwarm,
 		Branch:       c.InstallationsBranch,
+		Ref:          c.Ref,
 	}
 	if err := installationsRepository.Check(ctx); err != nil {
 		return nil, err
@@ -21,6 +21,8 @@ type Config struct {
 	CMCRepository       string
 	Skip                []string
 	DisplaySecrets      bool
+	// Ref is pulled instead of the heads of the branches, e.g. a commit SHA or tag.
+	Ref string
 }
 
 func Run(c Config, ctx context.Context) error {
@@ -44,6 +46,7 @@ func (c *Config) Pull(ctx context.Context) (*managementcluster.ManagementCluster
 			Cluster:             c.Cluster,
 			Github:              client,
 			InstallationsBranch: c.InstallationsBranch,
+			Ref:                 c.Ref,
 		}
 		installations, err := i.Run(ctx)
 		if err != nil {
@@ -61,6 +64,7 @@ func (c *Config) Pull(ctx context.Context) (*managementcluster.ManagementCluster
 			CMCRepository:  c.CMCRepository,
 			CMCBranch:      c.CMCBranch,
 			DisplaySecrets: c.DisplaySecrets,
+			Ref:            c.Ref,
 		}
 		cmc, err := c.Run(ctx)
 		if err != nil {
@@ -9,7 +9,16 @@ import (
 	"github.com/giantswarm/mcli/pkg/key"
 )
 
+const (
+	flagRef = "ref"
+)
+
+var (
+	pullRef string
+)
+
 func addFlagsPull() {
+	pullCmd.PersistentFlags().StringVar(&pullRef, flagRef, "", "Commit SHA, tag or branch to pull instead of the head of the branches. (default: head of the branches)")
 	pullCmd.Flags().StringArrayVarP(&skip, flagSkip, "s", []string{}, fmt.Sprintf("List of repositories to skip. (default: none) Valid values: %s", key.GetValidRepositories()))
 }
 
@@ -6,6 +6,7 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"os"
 
 	"github.com/spf13/cobra"
 
@@ -13,6 +14,8 @@ import (
 	pushcmc "github.com/giantswarm/mcli/cmd/push/cmc"
 	pushinstallations "github.com/giantswarm/mcli/cmd/push/installations"
 	"github.com/giantswarm/mcli/pkg/github"
+	"github.com/giantswarm/mcli/pkg/key"
+	"github.com/giantswarm/mcli/pkg/managementcluster"
 	"github.com/giantswarm/mcli/pkg/managementcluster/cmc"
 	"github.com/giantswarm/mcli/pkg/managementcluster/installations"
 )
@@ -22,9 +25,12 @@ var pushCmd = &cobra.Command{
 	Use:   "push",
 	Short: "Pushes configuration of a Management Cluster",
 	Long: `Pushes configuration of a Management Cluster to all
-relevant git repositories. For example:
+relevant git repositories. With --dry-run the changed fields are printed and the
+policy is evaluated without pushing. For example:
 
-mcli push --cluster=gigmac --input=cluster.yaml`,
+mcli push --cluster=gigmac --input=cluster.yaml
+
+mcli push --cluster=gigmac --input=cluster.yaml --policy=cmc:policies --dry-run`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		defaultPush()
 		err := validateRoot(cmd, args)
@@ -36,6 +42,13 @@ mcli push --cluster=gigmac --input=cluster.yaml`,
 			return err
 		}
 		ctx := context.Background()
+		client := github.New(github.Config{
+			Token: githubToken,
+		})
+		p, err := loadPolicy(ctx, client, cmcRepository)
+		if err != nil {
+			return err
+		}
 		c := push.Config{
 			Cluster:             cluster,
 			GithubToken:         githubToken,
@@ -47,11 +60,15 @@ mcli push --cluster=gigmac --input=cluster.yaml`,
 			Provider:            provider,
 			DisplaySecrets:      displaySecrets,
 			BaseDomain:          baseDomain,
+			Output:              output,
+			DryRun:              pushDryRun,
+			Policy:              p,
 			InstallationsFlags: pushinstallations.InstallationsFlags{
 				Team:          team,
 				Customer:      customer,
 				CCRRepository: ccrRepository,
 				Pipeline:      pipeline,
+				Fields:        installationsFields,
 				AWS: pushinstallations.AWSFlags{
 					Region:                 awsRegion,
 					InstallationAWSAccount: awsAccountID,
@@ -73,9 +90,15 @@ mcli push --cluster=gigmac --input=cluster.yaml`,
 				PrivateCA:                    privateCA,
 				PrivateMC:                    privateMC,
 				CertManagerDNSChallenge:      certManagerDNSChallenge,
+				CertManagerDNSSolver:         certManagerDNSSolver,
 				MCCustomCoreDNSConfig:        mcCustomCoreDNSConfig,
 				MCProxyEnabled:               mcProxyEnabled,
 				MCHTTPSProxy:                 mcHTTPSProxy,
+				MCHTTPProxy:                  mcHTTPProxy,
+				MCNoProxy:                    mcNoProxy,
+				MCProxyCIDR:                  mcProxyCIDR,
+				MCProxyGithubPort:            mcProxyGithubPort,
+				CredentialExpiry:             credentialExpiry,
 				TaylorBotToken:               taylorBotToken,
 				RegistryDomain:               registryDomain,
 				MCBBranchSource:              mcbBranchSource,
@@ -114,14 +137,17 @@ mcli push --cluster=gigmac --input=cluster.yaml`,
 					CertManagerRoute53Role:            certManagerRoute53Role,
 					CertManagerRoute53AccessKeyID:     certManagerRoute53AccessKeyID,
 					CertManagerRoute53SecretAccessKey: certManagerRoute53SecretAccessKey,
+					CertManagerAzureDNS:               certManagerAzureDNS,
+					CertManagerCloudflareAPIToken:     certManagerCloudflareAPIToken,
+					CertManagerRFC2136:                certManagerRFC2136,
 				},
 			},
 		}
-		err = push.Run(c, ctx)
-		if err != nil {
+		summary, err := push.Run(c, ctx)
+		if err != nil || summary == nil {
 			return err
 		}
-		return nil
+		return writeAudit(ctx, cmd, client, cmcRepository, summary)
 	},
 }
 
@@ -159,6 +185,7 @@ mcli push installations --cluster=gigmac --input=cluster.yaml`,
 				Customer:      customer,
 				CCRRepository: ccrRepository,
 				Pipeline:      pipeline,
+				Fields:        installationsFields,
 				AWS: pushinstallations.AWSFlags{
 					Region:                 awsRegion,
 					InstallationAWSAccount: awsAccountID,
@@ -170,12 +197,28 @@ mcli push installations --cluster=gigmac --input=cluster.yaml`,
 			if err != nil {
 				return fmt.Errorf("failed to get new installations object from input file.\n%w", err)
 			}
+			i.Unset, err = getUnsetFields(input)
+			if err != nil {
+				return err
+			}
 		}
-		installations, err := i.Run(ctx)
+		installations, result, err := i.Run(ctx)
 		if err != nil {
 			return fmt.Errorf("failed to push installations.\n%w", err)
 		}
-		return installations.Print()
+		summary := &managementcluster.Summary{
+			Cluster:      cluster,
+			Repositories: []*managementcluster.RepositorySummary{result},
+		}
+		if output == key.OutputJSON {
+			err = summary.Print()
+		} else {
+			err = installations.Print()
+		}
+		if err != nil {
+			return err
+		}
+		return writeAudit(ctx, cmd, client, cmcRepository, summary)
 	},
 }
 
@@ -224,9 +267,15 @@ mcli push cmc --cluster=gigmac --input=cluster.yaml`,
 				PrivateCA:                    privateCA,
 				PrivateMC:                    privateMC,
 				CertManagerDNSChallenge:      certManagerDNSChallenge,
+				CertManagerDNSSolver:         certManagerDNSSolver,
 				MCCustomCoreDNSConfig:        mcCustomCoreDNSConfig,
 				MCProxyEnabled:               mcProxyEnabled,
 				MCHTTPSProxy:                 mcHTTPSProxy,
+				MCHTTPProxy:                  mcHTTPProxy,
+				MCNoProxy:                    mcNoProxy,
+				MCProxyCIDR:                  mcProxyCIDR,
+				MCProxyGithubPort:            mcProxyGithubPort,
+				CredentialExpiry:             credentialExpiry,
 				TaylorBotToken:               taylorBotToken,
 				RegistryDomain:               registryDomain,
 				MCBBranchSource:              mcbBranchSource,
@@ -265,6 +314,9 @@ mcli push cmc --cluster=gigmac --input=cluster.yaml`,
 					CertManagerRoute53Role:            certManagerRoute53Role,
 					CertManagerRoute53AccessKeyID:     certManagerRoute53AccessKeyID,
 					CertManagerRoute53SecretAccessKey: certManagerRoute53SecretAccessKey,
+					CertManagerAzureDNS:               certManagerAzureDNS,
+					CertManagerCloudflareAPIToken:     certManagerCloudflareAPIToken,
+					CertManagerRFC2136:                certManagerRFC2136,
 				},
 			},
 		}
@@ -273,12 +325,28 @@ mcli push cmc --cluster=gigmac --input=cluster.yaml`,
 			if err != nil {
 				return fmt.Errorf("failed to get new CMC object from input file.\n%w", err)
 			}
+			c.Unset, err = getUnsetFields(input)
+			if err != nil {
+				return err
+			}
 		}
-		cmc, err := c.Run(ctx)
+		cmc, result, err := c.Run(ctx)
 		if err != nil {
 			return fmt.Errorf("failed to push CMC.\n%w", err)
 		}
-		return cmc.Print()
+		summary := &managementcluster.Summary{
+			Cluster:      cluster,
+			Repositories: []*managementcluster.RepositorySummary{result},
+		}
+		if output == key.OutputJSON {
+			err = summary.Print()
+		} else {
+			err = cmc.Print()
+		}
+		if err != nil {
+			return err
+		}
+		return writeAudit(ctx, cmd, client, cmcRepository, summary)
 	},
 }
 
@@ -288,3 +356,16 @@ func init() {
 	pushCmd.AddCommand(pushCMCCmd)
 	addFlagsPush()
 }
+
+// getUnsetFields returns the fields which are explicitly emptied in the input file.
+func getUnsetFields(file string) ([]string, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read input file %s.\n%w", file, err)
+	}
+	unset, err := key.GetUnsetFields(data, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get unset fields of input file %s.\n%w", file, err)
+	}
+	return unset, nil
+}
@@ -4,27 +4,36 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"strings"
 
 	"github.com/rs/zerolog/log"
 
 	"github.com/giantswarm/mcli/pkg/github"
 	"github.com/giantswarm/mcli/pkg/key"
+	"github.com/giantswarm/mcli/pkg/managementcluster"
 	"github.com/giantswarm/mcli/pkg/managementcluster/cmc"
+	"github.com/giantswarm/mcli/pkg/managementcluster/cmc/certmanager"
+	"github.com/giantswarm/mcli/pkg/managementcluster/cmc/coredns"
 	"github.com/giantswarm/mcli/pkg/managementcluster/cmc/kustomization"
+	"github.com/giantswarm/mcli/pkg/managementcluster/cmc/mcproxy"
+	"github.com/giantswarm/mcli/pkg/managementcluster/cmc/registry"
 	"github.com/giantswarm/mcli/pkg/sops"
 )
 
 type Config struct {
-	Cluster        string
-	Github         *github.Github
-	BaseDomain     string
-	CMCRepository  string
-	CMCBranch      string
-	Provider       string
-	Input          *cmc.CMC
-	Flags          CMCFlags
-	DisplaySecrets bool
+	Cluster           string
+	Github            *github.Github
+	BaseDomain        string
+	CMCRepository     string
+	CMCBranch         string
+	Provider          string
+	Input             *cmc.CMC
+	InputSecretFolder string
+	Flags             CMCFlags
+	DisplaySecrets    bool
+	// Replace uses the input as desired state instead of overriding the non-empty fields of the current one.
+	Replace bool
+	// Unset are paths of fields which are explicitly emptied in the input file.
+	Unset []string
 }
 
 type CMCFlags struct {
@@ -45,9 +54,15 @@ type CMCFlags struct {
 	PrivateCA                    bool
 	PrivateMC                    bool
 	CertManagerDNSChallenge      bool
+	CertManagerDNSSolver         string
 	MCCustomCoreDNSConfig        string
 	MCProxyEnabled               bool
 	MCHTTPSProxy                 string
+	MCHTTPProxy                  string
+	MCNoProxy                    []string
+	MCProxyCIDR                  string
+	MCProxyGithubPort            string
+	CredentialExpiry             map[string]string
 	RegistryDomain               string
 	MCBBranchSource              string
 	ConfigBranch                 string
@@ -67,6 +82,12 @@ type SecretFlags struct {
 	CertManagerRoute53Role            string
 	CertManagerRoute53AccessKeyID     string
 	CertManagerRoute53SecretAccessKey string
+	CertManagerAzureDNS               certmanager.AzureDNS
+	CertManagerCloudflareAPIToken     string
+	CertManagerRFC2136                certmanager.RFC2136
+	PrivateCABundle                   string
+	PrivateCACertificate              string
+	PrivateCAKey                      string
 }
 
 type AzureFlags struct {
@@ -85,19 +106,19 @@ type DeployKey struct {
 	KnownHosts string
 }
 
-func (c *Config) Run(ctx context.Context) (*cmc.CMC, error) {
+func (c *Config) Run(ctx context.Context) (*cmc.CMC, *managementcluster.RepositorySummary, error) {
 	err := c.Validate()
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	err = c.ReadSecretFlags()
 	if err != nil {
-		return nil, fmt.Errorf("failed to set secret flags.\n%w", err)
+		return nil, nil, fmt.Errorf("failed to set secret flags.\n%w", err)
 	}
 	return c.PushCMC(ctx)
 }
 
-func (c *Config) PushCMC(ctx context.Context) (*cmc.CMC, error) {
+func (c *Config) PushCMC(ctx context.Context) (*cmc.CMC, *managementcluster.RepositorySummary, error) {
 	cmcRepository := github.Repository{
 		Github:       c.Github,
 		Name:         c.CMCRepository,
@@ -106,7 +127,7 @@ func (c *Config) PushCMC(ctx context.Context) (*cmc.CMC, error) {
 	}
 
 	if err := c.Branch(ctx, cmcRepository); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	// pulling current cmc
 	cmc, err := c.Pull(ctx, cmcRepository)
@@ -116,12 +137,12 @@ func (c *Config) PushCMC(ctx context.Context) (*cmc.CMC, error) {
 		if github.IsNotFound(err) {
 			sopsFile, err := c.pullSopsFile(ctx, cmcRepository)
 			if err != nil {
-				return nil, err
+				return nil, nil, err
 			}
 			log.Debug().Msg(fmt.Sprintf("no current %s entry for %s found, creating a new one", c.CMCRepository, c.Cluster))
 			return c.Create(ctx, sopsFile)
 		} else {
-			return nil, fmt.Errorf("failed to pull %s entry for %s.\n%w", c.CMCRepository, c.Cluster, err)
+			return nil, nil, fmt.Errorf("failed to pull %s entry for %s.\n%w", c.CMCRepository, c.Cluster, err)
 		}
 	}
 	return c.Update(ctx, cmc)
@@ -174,109 +195,199 @@ func (c *Config) pullSopsFile(ctx context.Context, cmcRepository github.Reposito
 	return sopsfile, nil
 }
 
-func (c *Config) Create(ctx context.Context, sopsFile string) (*cmc.CMC, error) {
-	var err error
+func (c *Config) Create(ctx context.Context, sopsFile string) (*cmc.CMC, *managementcluster.RepositorySummary, error) {
 	log.Debug().Msg(fmt.Sprintf("creating new %s entry for %s", c.CMCRepository, c.Cluster))
-	var desiredCMC *cmc.CMC
-	{
-		if c.Input == nil {
-			desiredCMC, err = getNewCMCFromFlags(*c)
-			if err != nil {
-				return nil, fmt.Errorf("failed to get new %s object from flags.\n%w", c.CMCRepository, err)
-			}
-		} else {
-			desiredCMC = c.Input
-		}
+	desiredCMC, err := c.getNewDesired()
+	if err != nil {
+		return nil, nil, err
+	}
+	changedFields, err := key.GetChangedFields(&cmc.CMC{}, desiredCMC)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get changed fields.\n%w", err)
 	}
 	template, err := c.PullTemplate()
 	if err != nil {
-		return nil, fmt.Errorf("failed to pull template.\n%w", err)
+		return nil, nil, fmt.Errorf("failed to pull template.\n%w", err)
 	}
 	template[cmc.SopsFile] = sopsFile
 	create, err := desiredCMC.GetMap(template)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get cmc map.\n%w", err)
+		return nil, nil, fmt.Errorf("failed to get cmc map.\n%w", err)
 	}
 
-	message := fmt.Sprintf("Create configuration of management cluster %s", c.Cluster)
+	message := key.GetCommitMessage(fmt.Sprintf("Create configuration of management cluster %s", c.Cluster), changedFields)
 
-	return c.Push(ctx, create, message)
+	return c.Push(ctx, create, message, changedFields)
 }
 
-func (c *Config) Update(ctx context.Context, currentCMCmap map[string]string) (*cmc.CMC, error) {
+func (c *Config) Update(ctx context.Context, currentCMCmap map[string]string) (*cmc.CMC, *managementcluster.RepositorySummary, error) {
 	var err error
 	log.Debug().Msg(fmt.Sprintf("updating %s entry for %s", c.CMCRepository, c.Cluster))
 	currentCMC, err := cmc.GetCMCFromMap(currentCMCmap, c.Cluster, c.CMCRepository)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get cmc from map.\n%w", err)
+		return nil, nil, fmt.Errorf("failed to get cmc from map.\n%w", err)
 	}
 
-	var desiredCMC *cmc.CMC
-	{
-		if c.Input == nil {
-			desiredCMC, err = overrideCMCWithFlags(currentCMC, *c)
-			if err != nil {
-				return nil, fmt.Errorf("failed to override cmc with flags.\n%w", err)
-			}
-		} else {
-			desiredCMC = currentCMC.Override(c.Input)
-		}
+	desiredCMC, err := c.getUpdatedDesired(currentCMC)
+	if err != nil {
+		return nil, nil, err
 	}
 	if currentCMC.Equals(desiredCMC) {
 		log.Debug().Msg(fmt.Sprintf("%s entry for %s is up to date", c.CMCRepository, c.Cluster))
 		if !c.DisplaySecrets {
 			desiredCMC.RedactSecrets()
 		}
-		return desiredCMC, nil
+		cmcRepository := c.getRepository()
+		return desiredCMC, &managementcluster.RepositorySummary{Result: *cmcRepository.NewResult()}, nil
+	}
+	changedFields, err := key.GetChangedFields(currentCMC, desiredCMC)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get changed fields.\n%w", err)
 	}
 	// if deny all netpol is being enabled, we need to get the file from the template
 	if currentCMC.DisableDenyAllNetPol && !desiredCMC.DisableDenyAllNetPol {
 		template, err := c.PullTemplateFile(kustomization.DenyNetPolFile)
 		if err != nil {
-			return nil, fmt.Errorf("failed to pull template file.\n%w", err)
+			return nil, nil, fmt.Errorf("failed to pull template file.\n%w", err)
 		}
 		currentCMCmap[fmt.Sprintf("%s/%s", key.GetCMCPath(c.Cluster), kustomization.DenyNetPolFile)] = template
 	}
 	update, err := desiredCMC.GetMap(currentCMCmap)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get cmc map.\n%w", err)
+		return nil, nil, fmt.Errorf("failed to get cmc map.\n%w", err)
 	}
 
 	update, err = cmc.MarkUnchangedSecretsInMap(currentCMC, desiredCMC, update)
 	if err != nil {
-		return nil, fmt.Errorf("failed to mark unchanged secrets.\n%w", err)
+		return nil, nil, fmt.Errorf("failed to mark unchanged secrets.\n%w", err)
 	}
-	message := fmt.Sprintf("Update configuration of management cluster %s", c.Cluster)
-	return c.Push(ctx, update, message)
+	message := key.GetCommitMessage(fmt.Sprintf("Update configuration of management cluster %s", c.Cluster), changedFields)
+	return c.Push(ctx, update, message, changedFields)
 }
 
-func (c *Config) Push(ctx context.Context, desiredCMC map[string]string, message string) (*cmc.CMC, error) {
-	log.Debug().Msg(fmt.Sprintf("pushing %s entry for %s", c.CMCRepository, c.Cluster))
+// GetCurrentAndDesired returns the current cmc entry and the one which would be pushed without changing the repository.
+// The current state is read from the branch if it exists and from the main branch otherwise. It is empty for new entries.
+func (c *Config) GetCurrentAndDesired(ctx context.Context) (*cmc.CMC, *cmc.CMC, error) {
+	// secrets are read into a copy, so the configuration can still be pushed afterwards
+	d := *c
+	if c.Input != nil {
+		input := *c.Input
+		d.Input = &input
+	}
+	if err := d.Validate(); err != nil {
+		return nil, nil, err
+	}
+	if err := d.ReadSecretFlags(); err != nil {
+		return nil, nil, fmt.Errorf("failed to set secret flags.\n%w", err)
+	}
+	cmcRepository := d.getRepository()
+	err := cmcRepository.CheckBranch(ctx)
+	if github.IsNotFound(err) {
+		cmcRepository.Branch = key.CMCMainBranch
+	} else if err != nil {
+		return nil, nil, fmt.Errorf("failed to check %s branch %s.\n%w", d.CMCRepository, d.CMCBranch, err)
+	}
+	current, err := d.Pull(ctx, cmcRepository)
+	if github.IsNotFound(err) {
+		desiredCMC, err := d.getNewDesired()
+		if err != nil {
+			return nil, nil, err
+		}
+		return &cmc.CMC{}, desiredCMC, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to pull %s entry for %s.\n%w", d.CMCRepository, d.Cluster, err)
+	}
+	currentCMC, err := cmc.GetCMCFromMap(current, d.Cluster, d.CMCRepository)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get cmc from map.\n%w", err)
+	}
+	desiredCMC, err := d.getUpdatedDesired(currentCMC)
+	if err != nil {
+		return nil, nil, err
+	}
+	return currentCMC, desiredCMC, nil
+}
 
-	cmcRepository := github.Repository{
-		Github:       c.Github,
-		Name:         c.CMCRepository,
-		Organization: key.OrganizationGiantSwarm,
-		Branch:       c.CMCBranch,
+func (c *Config) getNewDesired() (*cmc.CMC, error) {
+	var err error
+	desiredCMC := c.Input
+	if desiredCMC == nil {
+		desiredCMC, err = getNewCMCFromFlags(*c)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get new %s object from flags.\n%w", c.CMCRepository, err)
+		}
+	}
+	if err := desiredCMC.SetClusterValues(nil); err != nil {
+		return nil, err
 	}
+	return desiredCMC, nil
+}
+
+func (c *Config) getUpdatedDesired(currentCMC *cmc.CMC) (*cmc.CMC, error) {
+	var err error
+	var desiredCMC *cmc.CMC
+	if c.Input == nil {
+		desiredCMC, err = overrideCMCWithFlags(currentCMC, *c)
+		if err != nil {
+			return nil, fmt.Errorf("failed to override cmc with flags.\n%w", err)
+		}
+	} else if c.Replace {
+		desiredCMC = c.Input
+	} else {
+		// fields set from the secret folder are not unset
+		unset, err := key.FilterUnsetFields(c.Input, c.Unset)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get unset fields.\n%w", err)
+		}
+		desiredCMC, err = currentCMC.Override(c.Input).Unset(unset)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := desiredCMC.SetClusterValues(currentCMC); err != nil {
+		return nil, err
+	}
+	return desiredCMC, nil
+}
+
+func (c *Config) Push(ctx context.Context, desiredCMC map[string]string, message string, changedFields []string) (*cmc.CMC, *managementcluster.RepositorySummary, error) {
+	log.Debug().Msg(fmt.Sprintf("pushing %s entry for %s", c.CMCRepository, c.Cluster))
+
+	cmcRepository := c.getRepository()
 	err := cmcRepository.Check(ctx)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	err = cmcRepository.CreateDirectory(ctx, desiredCMC, message)
+	pushed, err := cmcRepository.CreateDirectory(ctx, desiredCMC, message)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create directory %s.\n%w", key.GetCMCPath(c.Cluster), err)
+		return nil, nil, fmt.Errorf("failed to create directory %s.\n%w", key.GetCMCPath(c.Cluster), err)
 	}
 
 	result, err := cmc.GetCMCFromMap(desiredCMC, c.Cluster, c.CMCRepository)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get cmc from map.\n%w", err)
+		return nil, nil, fmt.Errorf("failed to get cmc from map.\n%w", err)
 	}
 	if !c.DisplaySecrets {
 		result.RedactSecrets()
 	}
-	return result, nil
+	summary := &managementcluster.RepositorySummary{
+		Result: *pushed,
+	}
+	if pushed.Status != github.StatusUnchanged {
+		summary.ChangedFields = changedFields
+	}
+	return result, summary, nil
+}
+
+func (c *Config) getRepository() github.Repository {
+	return github.Repository{
+		Github:       c.Github,
+		Name:         c.CMCRepository,
+		Organization: key.OrganizationGiantSwarm,
+		Branch:       c.CMCBranch,
+	}
 }
 
 func (c *Config) Validate() error {
@@ -398,7 +509,14 @@ func (c *Config) EnsureFlagsAreSet() error {
 			return fmt.Errorf("container registry configuration is required\n%w", ErrInvalidFlag)
 		}
 	}
-	if c.Flags.CertManagerDNSChallenge {
+	if c.Flags.CertManagerDNSChallenge && !certmanager.IsValidSolver(certmanager.GetSolver(c.Flags.CertManagerDNSSolver)) {
+		return fmt.Errorf("invalid cert manager dns solver %s. Valid values: %v\n%w", c.Flags.CertManagerDNSSolver, certmanager.GetSolvers(), ErrInvalidFlag)
+	}
+	if c.Flags.CertManagerDNSChallenge && certmanager.GetSolver(c.Flags.CertManagerDNSSolver) != certmanager.SolverRoute53 {
+		if err := certmanager.Validate(c.getCertManagerDNSChallenge().GetConfig()); err != nil {
+			return fmt.Errorf("cert manager dns solver flags are incomplete.\n%v\n%w", err, ErrInvalidFlag)
+		}
+	} else if c.Flags.CertManagerDNSChallenge {
 		if c.Flags.Secrets.CertManagerRoute53Region == "" {
 			return fmt.Errorf("cert manager route53 region is required\n%w", ErrInvalidFlag)
 		}
@@ -475,8 +593,13 @@ func getCMC(c Config) (*cmc.CMC, error) {
 		},
 		ClusterIntegratesDefaultApps: c.Flags.ClusterIntegratesDefaultApps,
 		MCAppsPreventDeletion:        c.Flags.MCAppsPreventDeletion,
-		PrivateCA:                    c.Flags.PrivateCA,
-		PrivateMC:                    c.Flags.PrivateMC,
+		PrivateCA: cmc.PrivateCA{
+			Enabled:     c.Flags.PrivateCA,
+			Bundle:      c.Flags.Secrets.PrivateCABundle,
+			Certificate: c.Flags.Secrets.PrivateCACertificate,
+			Key:         c.Flags.Secrets.PrivateCAKey,
+		},
+		PrivateMC: c.Flags.PrivateMC,
 		Provider: cmc.Provider{
 			Name: c.Provider,
 		},
@@ -507,39 +630,43 @@ func getCMC(c Config) (*cmc.CMC, error) {
 		}
 	}
 	if c.Flags.ConfigureContainerRegistries {
+		registryConfig := registry.GetConfig(c.Flags.Secrets.ContainerRegistryConfiguration)
 		newCMC.ConfigureContainerRegistries = cmc.ConfigureContainerRegistries{
-			Enabled: true,
-			Values:  c.Flags.Secrets.ContainerRegistryConfiguration,
+			Enabled:    true,
+			Registries: registryConfig.Registries,
+			Values:     registryConfig.Raw,
 		}
 	}
 	if c.Flags.CertManagerDNSChallenge {
-		newCMC.CertManagerDNSChallenge = cmc.CertManagerDNSChallenge{
-			Enabled:         true,
-			Region:          c.Flags.Secrets.CertManagerRoute53Region,
-			Role:            c.Flags.Secrets.CertManagerRoute53Role,
-			AccessKeyID:     c.Flags.Secrets.CertManagerRoute53AccessKeyID,
-			SecretAccessKey: c.Flags.Secrets.CertManagerRoute53SecretAccessKey,
-		}
+		newCMC.CertManagerDNSChallenge = c.getCertManagerDNSChallenge()
 	}
 	if c.Flags.MCProxyEnabled {
-		proxy := strings.Split(c.Flags.MCHTTPSProxy, "/")
-		if len(proxy) != 3 {
-			return nil, fmt.Errorf("invalid mc https proxy format %s. Expected format: http://<hostname>:<port>.\n%w", c.Flags.MCHTTPSProxy, ErrInvalidFlag)
-		}
-		host := strings.Split(proxy[2], ":")
-		if len(host) != 2 {
+		hostname, port, err := mcproxy.GetProxyFromURL(c.Flags.MCHTTPSProxy)
+		if err != nil {
+			log.Debug().Msg(err.Error())
 			return nil, fmt.Errorf("invalid mc https proxy format %s. Expected format: http://<hostname>:<port>.\n%w", c.Flags.MCHTTPSProxy, ErrInvalidFlag)
 		}
 		newCMC.MCProxy = cmc.MCProxy{
-			Enabled:  true,
-			Hostname: host[0],
-			Port:     host[1],
+			Enabled:    true,
+			Hostname:   hostname,
+			Port:       port,
+			HTTPProxy:  c.Flags.MCHTTPProxy,
+			HTTPSProxy: c.Flags.MCHTTPSProxy,
+			NoProxy:    c.Flags.MCNoProxy,
+			CIDR:       c.Flags.MCProxyCIDR,
+			GithubPort: c.Flags.MCProxyGithubPort,
 		}
 	}
+	if len(c.Flags.CredentialExpiry) > 0 {
+		newCMC.CredentialExpiry = c.Flags.CredentialExpiry
+	}
 	if c.Flags.MCCustomCoreDNSConfig != "" {
+		coreDNSConfig := coredns.GetConfig(c.Flags.MCCustomCoreDNSConfig)
 		newCMC.CustomCoreDNS = cmc.CustomCoreDNS{
-			Enabled: true,
-			Values:  c.Flags.MCCustomCoreDNSConfig,
+			Enabled:     true,
+			Zones:       coreDNSConfig.Zones,
+			StubDomains: coreDNSConfig.StubDomains,
+			Values:      coreDNSConfig.Raw,
 		}
 	}
 
@@ -568,6 +695,27 @@ func getCMC(c Config) (*cmc.CMC, error) {
 	return newCMC, nil
 }
 
+func (c *Config) getCertManagerDNSChallenge() cmc.CertManagerDNSChallenge {
+	challenge := cmc.CertManagerDNSChallenge{
+		Enabled: true,
+		Solver:  c.Flags.CertManagerDNSSolver,
+	}
+	switch certmanager.GetSolver(c.Flags.CertManagerDNSSolver) {
+	case certmanager.SolverRoute53:
+		challenge.Region = c.Flags.Secrets.CertManagerRoute53Region
+		challenge.Role = c.Flags.Secrets.CertManagerRoute53Role
+		challenge.AccessKeyID = c.Flags.Secrets.CertManagerRoute53AccessKeyID
+		challenge.SecretAccessKey = c.Flags.Secrets.CertManagerRoute53SecretAccessKey
+	case certmanager.SolverAzureDNS:
+		challenge.AzureDNS = c.Flags.Secrets.CertManagerAzureDNS
+	case certmanager.SolverCloudflare:
+		challenge.Cloudflare = certmanager.Cloudflare{APIToken: c.Flags.Secrets.CertManagerCloudflareAPIToken}
+	case certmanager.SolverRFC2136:
+		challenge.RFC2136 = c.Flags.Secrets.CertManagerRFC2136
+	}
+	return challenge
+}
+
 func overrideCMCWithFlags(currentCMC *cmc.CMC, c Config) (*cmc.CMC, error) {
 	newCMC, err := getCMC(c)
 	if err != nil {
@@ -720,9 +720,10 @@ func TestGetNewCMCFromFlags(t *testing.T) {
 					KnownHosts: "test-deploy",
 				},
 				MCProxy: cmc.MCProxy{
-					Enabled:  true,
-					Hostname: "test-mc-https-proxy",
-					Port:     "443",
+					Enabled:    true,
+					Hostname:   "test-mc-https-proxy",
+					Port:       "443",
+					HTTPSProxy: "http://test-mc-https-proxy:443",
 				},
 			},
 		},
@@ -0,0 +1,390 @@
+package pushcmc
+
+import (
+	"context"
+	"flag"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/giantswarm/mcli/pkg/github"
+	"github.com/giantswarm/mcli/pkg/github/githubtest"
+	"github.com/giantswarm/mcli/pkg/key"
+	"github.com/giantswarm/mcli/pkg/managementcluster/cmc"
+	"github.com/giantswarm/mcli/pkg/sops"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+// the key is only used to encrypt the test data, so the recipients in .sops.yaml are stable
+const (
+	testAgeKey    = "AGE-SECRET-KEY-19RCZHT5TQD9ANDX03DQKPS3SZ0PGGLVJ6U509DFCKP4QR5L3TPASKWYTFJ"
+	testAgePubKey = "age105t0839qncajm5928fn69m9hv2gmaqxxdgh80q8ydrw7jykmdvwq3uwa5a"
+)
+
+func TestPushCMCEndToEnd(t *testing.T) {
+	var testCases = []struct {
+		name   string
+		create *cmc.CMC
+		update *cmc.CMC
+	}{
+		{
+			name:   "capa",
+			create: getTestCMC(key.ProviderAWS, cmc.Provider{Name: key.ProviderAWS}),
+			update: &cmc.CMC{
+				MCProxy: cmc.MCProxy{
+					Enabled:  true,
+					Hostname: "proxy.example.com",
+					Port:     "3128",
+				},
+			},
+		},
+		{
+			name: "capz",
+			create: getTestCMC(key.ProviderAzure, cmc.Provider{
+				Name: key.ProviderAzure,
+				CAPZ: cmc.CAPZ{
+					UAClientID:     "uaclientid",
+					UATenantID:     "uatenantid",
+					UAResourceID:   "uaresourceid",
+					ClientID:       "clientid",
+					ClientSecret:   "clientsecret",
+					TenantID:       "tenantid",
+					SubscriptionID: "subscriptionid",
+				},
+			}),
+			update: &cmc.CMC{
+				ClusterApp: cmc.App{Version: "2.0.0"},
+			},
+		},
+		{
+			name: "vsphere",
+			create: getTestCMC(key.ProviderVsphere, cmc.Provider{
+				Name: key.ProviderVsphere,
+				CAPV: cmc.CAPV{CloudConfig: "cloudconfig"},
+			}),
+			update: &cmc.CMC{
+				CustomCoreDNS: cmc.CustomCoreDNS{
+					Enabled: true,
+					Values:  "customcorednsvalues",
+				},
+			},
+		},
+		{
+			name: "cloud-director",
+			create: getTestCMC(key.ProviderVCD, cmc.Provider{
+				Name:   key.ProviderVCD,
+				CAPVCD: cmc.CAPVCD{RefreshToken: "refreshtoken"},
+			}),
+			update: &cmc.CMC{
+				DefaultApps: cmc.App{Version: "2.0.0"},
+			},
+		},
+	}
+
+	template := getTestTemplate(t)
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, ok := os.LookupEnv("CI"); ok { // we skip this test in CI since it needs sops binary to be present right now
+				t.Skip()
+			}
+			t.Setenv(sops.EnvAgeKey, testAgeKey)
+
+			server := githubtest.NewServer()
+			defer server.Close()
+			server.AddRepository(key.OrganizationGiantSwarm, key.RepositoryMCBootstrap, template)
+			server.AddRepository(key.OrganizationGiantSwarm, "test-management-clusters", map[string]string{
+				"README.md": "cmc",
+			})
+
+			cluster := "test"
+			branch := key.GetDefaultPRBranch(cluster)
+			ctx := context.Background()
+
+			steps := []struct {
+				name  string
+				input *cmc.CMC
+
+				expectStatus string
+			}{
+				{name: "create", input: tc.create, expectStatus: github.StatusCreated},
+				{name: "update", input: tc.update, expectStatus: github.StatusUpdated},
+				{name: "no-op", input: tc.update, expectStatus: github.StatusUnchanged},
+			}
+			var previous map[string]string
+			for _, step := range steps {
+				input := *step.input
+				c := Config{
+					Cluster:       cluster,
+					Github:        server.Client(),
+					CMCRepository: "test-management-clusters",
+					CMCBranch:     branch,
+					Input:         &input,
+				}
+
+				// the dry run reports the changes of the push without pushing
+				commits := server.Commits(key.OrganizationGiantSwarm, "test-management-clusters", branch)
+				current, desired, err := c.GetCurrentAndDesired(ctx)
+				if err != nil {
+					t.Fatalf("%s: unexpected error: %v", step.name, err)
+				}
+				dryRunFields, err := key.GetChangedFields(current, desired)
+				if err != nil {
+					t.Fatalf("%s: unexpected error: %v", step.name, err)
+				}
+				if after := server.Commits(key.OrganizationGiantSwarm, "test-management-clusters", branch); len(after) != len(commits) {
+					t.Fatalf("%s: expected no commit in dry run, got %v", step.name, after)
+				}
+
+				_, summary, err := c.Run(ctx)
+				if err != nil {
+					t.Fatalf("%s: unexpected error: %v", step.name, err)
+				}
+				if len(dryRunFields) != len(summary.ChangedFields) || (len(dryRunFields) > 0 && !reflect.DeepEqual(dryRunFields, summary.ChangedFields)) {
+					t.Fatalf("%s: expected dry run fields %v to match pushed fields %v", step.name, dryRunFields, summary.ChangedFields)
+				}
+				if summary.Status != step.expectStatus {
+					t.Fatalf("%s: expected status %s, got %s", step.name, step.expectStatus, summary.Status)
+				}
+
+				files := getDecryptedFiles(t, server.Files(key.OrganizationGiantSwarm, "test-management-clusters", branch))
+				if step.expectStatus == github.StatusUnchanged {
+					if summary.CommitSHA != "" || len(summary.Files) != 0 || len(summary.ChangedFields) != 0 {
+						t.Fatalf("%s: expected no commit, got %v", step.name, summary)
+					}
+					compareTrees(t, step.name, previous, files)
+					continue
+				}
+				if len(summary.Files) == 0 || len(summary.ChangedFields) == 0 {
+					t.Fatalf("%s: expected changed files and fields, got %v", step.name, summary)
+				}
+				compareGoldenTree(t, filepath.Join("testdata", "golden", tc.name, step.name), files)
+				previous = files
+			}
+
+			if commits := server.Commits(key.OrganizationGiantSwarm, "test-management-clusters", branch); len(commits) != 3 {
+				t.Fatalf("expected the base commit and 2 pushed commits, got %v", commits)
+			}
+			if files := server.Files(key.OrganizationGiantSwarm, "test-management-clusters", key.CMCMainBranch); len(files) != 1 {
+				t.Fatalf("expected %s branch to be unchanged, got %v", key.CMCMainBranch, files)
+			}
+		})
+	}
+}
+
+func TestPushKeepsManualClusterValues(t *testing.T) {
+	if _, ok := os.LookupEnv("CI"); ok { // we skip this test in CI since it needs sops binary to be present right now
+		t.Skip()
+	}
+	t.Setenv(sops.EnvAgeKey, testAgeKey)
+
+	server := githubtest.NewServer()
+	defer server.Close()
+	server.AddRepository(key.OrganizationGiantSwarm, key.RepositoryMCBootstrap, getTestTemplate(t))
+	server.AddRepository(key.OrganizationGiantSwarm, "test-management-clusters", map[string]string{
+		"README.md": "cmc",
+	})
+
+	// the proxy and the trust bundle are configured in the cluster values while the mc proxy and private CA are disabled
+	values := "global:\n  metadata:\n    name: test\n  connectivity:\n    proxy:\n      enabled: true\n      httpProxy: http://manual.example.com:3128\n      httpsProxy: http://manual.example.com:3128\n  components:\n    containerd:\n      trustedCertificateAuthorities: manualbundle\n"
+	create := getTestCMC(key.ProviderAWS, cmc.Provider{Name: key.ProviderAWS})
+	create.ClusterApp.Values = values
+
+	ctx := context.Background()
+	for _, input := range []*cmc.CMC{create, {ClusterApp: cmc.App{Version: "2.0.0"}}} {
+		c := Config{
+			Cluster:       "test",
+			Github:        server.Client(),
+			CMCRepository: "test-management-clusters",
+			CMCBranch:     key.GetDefaultPRBranch("test"),
+			Input:         input,
+		}
+		if _, _, err := c.Run(ctx); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	c := Config{
+		Cluster:       "test",
+		Github:        server.Client(),
+		CMCRepository: "test-management-clusters",
+		CMCBranch:     key.GetDefaultPRBranch("test"),
+		Input:         &cmc.CMC{},
+	}
+	current, _, err := c.GetCurrentAndDesired(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if current.ClusterApp.Version != "2.0.0" {
+		t.Fatalf("expected version 2.0.0, got %s", current.ClusterApp.Version)
+	}
+	if current.ClusterApp.Values != values {
+		t.Fatalf("expected cluster values %q, got %q", values, current.ClusterApp.Values)
+	}
+}
+
+func getTestCMC(provider string, p cmc.Provider) *cmc.CMC {
+	return &cmc.CMC{
+		Cluster:    "test",
+		BaseDomain: "test.gigantic.io",
+		AgePubKey:  testAgePubKey,
+		GitOps: cmc.GitOps{
+			CMCRepository:         "test-management-clusters",
+			MCBBranchSource:       "main",
+			ConfigBranch:          "main",
+			MCAppCollectionBranch: "main",
+		},
+		ClusterApp: cmc.App{
+			Name:    "cluster-" + provider,
+			AppName: "test",
+			Catalog: "cluster",
+			Version: "1.0.0",
+			Values:  "global:\n  metadata:\n    name: test\n",
+		},
+		DefaultApps: cmc.App{
+			Name:    "default-apps-" + provider,
+			AppName: "test-default-apps",
+			Catalog: "cluster",
+			Version: "1.0.0",
+			Values:  "clusterName: test\norganization: giantswarm\nmanagementCluster: test\n",
+		},
+		ClusterNamespace: "org-giantswarm",
+		Provider:         p,
+		TaylorBotToken:   "taylorbottoken",
+		SSHdeployKey: cmc.DeployKey{
+			Identity:   "identity",
+			Passphrase: "passphrase",
+			KnownHosts: "knownhosts",
+		},
+		CustomerDeployKey: cmc.DeployKey{
+			Identity:   "customeridentity",
+			Passphrase: "customerpassphrase",
+			KnownHosts: "customerknownhosts",
+		},
+		SharedDeployKey: cmc.DeployKey{
+			Identity:   "sharedidentity",
+			Passphrase: "sharedpassphrase",
+			KnownHosts: "sharedknownhosts",
+		},
+	}
+}
+
+func getTestTemplate(t *testing.T) map[string]string {
+	t.Helper()
+	template := map[string]string{}
+	root := filepath.Join("testdata", "template")
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, err := os.ReadFile(p) // #nosec G304
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		template[key.CMCEntryTemplatePath+"/"+filepath.ToSlash(rel)] = string(data)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("failed to read template: %v", err)
+	}
+	return template
+}
+
+// getDecryptedFiles decrypts the files since encrypting the same content twice does not give the same result.
+// Decrypted files are normalized, so the golden files do not depend on the output format of sops.
+func getDecryptedFiles(t *testing.T, files map[string]string) map[string]string {
+	t.Helper()
+	encrypted := map[string]bool{}
+	for k, v := range files {
+		encrypted[k] = sops.IsEncrypted(v)
+	}
+	decrypted, err := sops.DecryptDir(files)
+	if err != nil {
+		t.Fatalf("failed to decrypt files: %v", err)
+	}
+	for k, v := range decrypted {
+		if !encrypted[k] {
+			continue
+		}
+		var data any
+		if err := yaml.Unmarshal([]byte(v), &data); err != nil {
+			t.Fatalf("failed to parse %s: %v", k, err)
+		}
+		out, err := yaml.Marshal(data)
+		if err != nil {
+			t.Fatalf("failed to marshal %s: %v", k, err)
+		}
+		decrypted[k] = string(out)
+	}
+	return decrypted
+}
+
+func compareGoldenTree(t *testing.T, dir string, actual map[string]string) {
+	t.Helper()
+	if *update {
+		if err := os.RemoveAll(dir); err != nil {
+			t.Fatalf("failed to remove golden directory: %v", err)
+		}
+		for k, v := range actual {
+			p := filepath.Join(dir, filepath.FromSlash(k))
+			if err := os.MkdirAll(filepath.Dir(p), 0750); err != nil {
+				t.Fatalf("failed to create golden directory: %v", err)
+			}
+			if err := os.WriteFile(p, []byte(v), 0600); err != nil {
+				t.Fatalf("failed to update golden file: %v", err)
+			}
+		}
+	}
+	expected := map[string]string{}
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, err := os.ReadFile(p) // #nosec G304
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		expected[filepath.ToSlash(rel)] = string(data)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("failed to read golden directory: %v", err)
+	}
+	compareTrees(t, dir, expected, actual)
+}
+
+func compareTrees(t *testing.T, name string, expected map[string]string, actual map[string]string) {
+	t.Helper()
+	var diff []string
+	for k, v := range expected {
+		a, ok := actual[k]
+		if !ok {
+			diff = append(diff, "missing "+k)
+		} else if a != v {
+			diff = append(diff, "changed "+k+"\nexpected:\n"+v+"\ngot:\n"+a)
+		}
+	}
+	for k := range actual {
+		if _, ok := expected[k]; !ok {
+			diff = append(diff, "unexpected "+k)
+		}
+	}
+	if len(diff) > 0 {
+		sort.Strings(diff)
+		t.Fatalf("%s does not match.\n%s", name, strings.Join(diff, "\n"))
+	}
+}
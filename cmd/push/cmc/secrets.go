@@ -26,6 +26,21 @@ const (
 	azureResourceIDUA            = "UA_id"
 )
 
+const (
+	CertManagerAzureSubscriptionIDKey          = "cert_manager_azure_subscription_id"
+	CertManagerAzureResourceGroupKey           = "cert_manager_azure_resource_group"
+	CertManagerAzureHostedZoneKey              = "cert_manager_azure_hosted_zone"
+	CertManagerAzureManagedIdentityClientIDKey = "cert_manager_azure_managed_identity_client_id"
+	CertManagerAzureTenantIDKey                = "cert_manager_azure_tenant_id"
+	CertManagerAzureClientIDKey                = "cert_manager_azure_client_id"
+	CertManagerAzureClientSecretKey            = "cert_manager_azure_client_secret"  // #nosec G101
+	CertManagerCloudflareAPITokenKey           = "cert_manager_cloudflare_api_token" // #nosec G101
+	CertManagerRFC2136NameserverKey            = "cert_manager_rfc2136_nameserver"
+	CertManagerRFC2136TSIGKeyNameKey           = "cert_manager_rfc2136_tsig_key_name"
+	CertManagerRFC2136TSIGAlgorithmKey         = "cert_manager_rfc2136_tsig_algorithm"
+	CertManagerRFC2136TSIGSecretKey            = "cert_manager_rfc2136_tsig_secret" // #nosec G101
+)
+
 const (
 	CloudDirectorCredentialsFile = "cloud-director.sh"        // #nosec G101
 	VsphereCredentialsFile       = "vsphere-credentials.yaml" // #nosec G101
@@ -53,8 +68,7 @@ func GetSecrets(cluster string) []string {
 
 func (c *Config) ReadSecretFlags() error {
 	if c.Input != nil {
-		log.Debug().Msg("Input file provided, skipping reading secrets folder")
-		return nil
+		return c.readInputSecrets()
 	}
 
 	var secrets map[string]string
@@ -135,6 +149,21 @@ func (c *Config) ReadSecretFlags() error {
 		}
 		secrets[key.GetContainerRegistriesFile(c.Cluster)] = containerRegistry
 	}
+	if c.Flags.PrivateCA {
+		// the private CA files are optional, without them a self-signed CA is used
+		for _, f := range []string{
+			key.GetPrivateCABundleFile(c.Cluster),
+			key.GetPrivateCACertificateFile(c.Cluster),
+			key.GetPrivateCAKeyFile(c.Cluster),
+		} {
+			v, err := c.ReadFileFromSecretFolder(f)
+			if err != nil {
+				log.Debug().Msgf("no private CA file %s found in secret folder.\n%s", f, err)
+				continue
+			}
+			secrets[f] = v
+		}
+	}
 	return c.SetSecretFlags(secrets)
 }
 
@@ -157,6 +186,54 @@ func (c *Config) SetSecretFlags(secrets map[string]string) error {
 			if c.Flags.Secrets.CertManagerRoute53SecretAccessKey == "" {
 				c.Flags.Secrets.CertManagerRoute53SecretAccessKey = v
 			}
+		case CertManagerAzureSubscriptionIDKey:
+			if c.Flags.Secrets.CertManagerAzureDNS.SubscriptionID == "" {
+				c.Flags.Secrets.CertManagerAzureDNS.SubscriptionID = v
+			}
+		case CertManagerAzureResourceGroupKey:
+			if c.Flags.Secrets.CertManagerAzureDNS.ResourceGroupName == "" {
+				c.Flags.Secrets.CertManagerAzureDNS.ResourceGroupName = v
+			}
+		case CertManagerAzureHostedZoneKey:
+			if c.Flags.Secrets.CertManagerAzureDNS.HostedZoneName == "" {
+				c.Flags.Secrets.CertManagerAzureDNS.HostedZoneName = v
+			}
+		case CertManagerAzureManagedIdentityClientIDKey:
+			if c.Flags.Secrets.CertManagerAzureDNS.ManagedIdentity.ClientID == "" {
+				c.Flags.Secrets.CertManagerAzureDNS.ManagedIdentity.ClientID = v
+			}
+		case CertManagerAzureTenantIDKey:
+			if c.Flags.Secrets.CertManagerAzureDNS.TenantID == "" {
+				c.Flags.Secrets.CertManagerAzureDNS.TenantID = v
+			}
+		case CertManagerAzureClientIDKey:
+			if c.Flags.Secrets.CertManagerAzureDNS.ClientID == "" {
+				c.Flags.Secrets.CertManagerAzureDNS.ClientID = v
+			}
+		case CertManagerAzureClientSecretKey:
+			if c.Flags.Secrets.CertManagerAzureDNS.ClientSecret == "" {
+				c.Flags.Secrets.CertManagerAzureDNS.ClientSecret = v
+			}
+		case CertManagerCloudflareAPITokenKey:
+			if c.Flags.Secrets.CertManagerCloudflareAPIToken == "" {
+				c.Flags.Secrets.CertManagerCloudflareAPIToken = v
+			}
+		case CertManagerRFC2136NameserverKey:
+			if c.Flags.Secrets.CertManagerRFC2136.Nameserver == "" {
+				c.Flags.Secrets.CertManagerRFC2136.Nameserver = v
+			}
+		case CertManagerRFC2136TSIGKeyNameKey:
+			if c.Flags.Secrets.CertManagerRFC2136.TSIGKeyName == "" {
+				c.Flags.Secrets.CertManagerRFC2136.TSIGKeyName = v
+			}
+		case CertManagerRFC2136TSIGAlgorithmKey:
+			if c.Flags.Secrets.CertManagerRFC2136.TSIGAlgorithm == "" {
+				c.Flags.Secrets.CertManagerRFC2136.TSIGAlgorithm = v
+			}
+		case CertManagerRFC2136TSIGSecretKey:
+			if c.Flags.Secrets.CertManagerRFC2136.TSIGSecret == "" {
+				c.Flags.Secrets.CertManagerRFC2136.TSIGSecret = v
+			}
 		case key.ClusterValuesFile:
 			if c.Flags.Secrets.ClusterValues == "" {
 				c.Flags.Secrets.ClusterValues = v
@@ -234,6 +311,18 @@ func (c *Config) SetSecretFlags(secrets map[string]string) error {
 			if c.Flags.Secrets.ContainerRegistryConfiguration == "" {
 				c.Flags.Secrets.ContainerRegistryConfiguration = v
 			}
+		case key.GetPrivateCABundleFile(c.Cluster):
+			if c.Flags.Secrets.PrivateCABundle == "" {
+				c.Flags.Secrets.PrivateCABundle = v
+			}
+		case key.GetPrivateCACertificateFile(c.Cluster):
+			if c.Flags.Secrets.PrivateCACertificate == "" {
+				c.Flags.Secrets.PrivateCACertificate = v
+			}
+		case key.GetPrivateCAKeyFile(c.Cluster):
+			if c.Flags.Secrets.PrivateCAKey == "" {
+				c.Flags.Secrets.PrivateCAKey = v
+			}
 		default:
 			log.Debug().Msgf("secret flag %s does not exist or is already set", k)
 		}
@@ -242,6 +331,41 @@ func (c *Config) SetSecretFlags(secrets map[string]string) error {
 	return nil
 }
 
+// readInputSecrets sets secrets of the input file which are not set from the secret folder it references.
+// Other flags are ignored when an input file is used.
+func (c *Config) readInputSecrets() error {
+	if c.InputSecretFolder == "" {
+		log.Debug().Msg("Input file provided, skipping reading secrets folder")
+		return nil
+	}
+	log.Debug().Msgf("Input file provided, reading missing secrets from %s", c.InputSecretFolder)
+
+	secretConfig := Config{
+		Cluster:  c.Cluster,
+		Provider: c.Input.Provider.Name,
+		Flags: CMCFlags{
+			SecretFolder:                 c.InputSecretFolder,
+			ClusterIntegratesDefaultApps: c.Input.ClusterIntegratesDefaultApps,
+			ConfigureContainerRegistries: c.Input.ConfigureContainerRegistries.Enabled,
+			PrivateCA:                    c.Input.PrivateCA.Enabled,
+			PrivateMC:                    c.Input.PrivateMC,
+			CertManagerDNSChallenge:      c.Input.CertManagerDNSChallenge.Enabled,
+			CertManagerDNSSolver:         c.Input.CertManagerDNSChallenge.Solver,
+		},
+	}
+	if err := secretConfig.ReadSecretFlags(); err != nil {
+		return err
+	}
+	secretCMC, err := getCMC(secretConfig)
+	if err != nil {
+		return fmt.Errorf("failed to get secrets from %s.\n%w", c.InputSecretFolder, err)
+	}
+	// booleans are only overridden when true, the input decides
+	secretCMC.DisableDenyAllNetPol = false
+	c.Input = secretCMC.Override(c.Input)
+	return nil
+}
+
 func (c *Config) ReadFileFromSecretFolder(file string) (string, error) {
 	path := fmt.Sprintf("%s/%s", c.Flags.SecretFolder, file)
 	if _, err := os.Stat(path); err != nil {
@@ -232,3 +232,119 @@ func TestConfig_ReadSecretFlags(t *testing.T) {
 		})
 	}
 }
+
+func TestConfig_ReadInputSecrets(t *testing.T) {
+	testCases := []struct {
+		name          string
+		secretsFolder string
+		input         cmc.CMC
+
+		expectErr    bool
+		expectOutput cmc.CMC
+	}{
+		{
+			name: "no secret folder",
+			input: cmc.CMC{
+				Provider:   cmc.Provider{Name: "capa"},
+				ClusterApp: cmc.App{Name: "cluster-aws", Values: "input-values\n"},
+			},
+			expectOutput: cmc.CMC{
+				Provider:   cmc.Provider{Name: "capa"},
+				ClusterApp: cmc.App{Name: "cluster-aws", Values: "input-values\n"},
+			},
+		},
+		{
+			name:          "secrets are read from the secret folder",
+			secretsFolder: "testdata/valid",
+			input: cmc.CMC{
+				Cluster:    "test",
+				Provider:   cmc.Provider{Name: "capa"},
+				ClusterApp: cmc.App{Name: "cluster-aws", AppName: "test"},
+			},
+			expectOutput: cmc.CMC{
+				Cluster:    "test",
+				Provider:   cmc.Provider{Name: "capa"},
+				ClusterApp: cmc.App{Name: "cluster-aws", AppName: "test", Values: "cluster-values\n"},
+				DefaultApps: cmc.App{
+					AppName: "test-default-apps",
+					Values:  "clusterName: test\norganization: giantswarm\nmanagementCluster: test\n",
+				},
+				SSHdeployKey: cmc.DeployKey{
+					Passphrase: "deploy-key-passphrase\n",
+					Identity:   "deploy-key-identity\n",
+					KnownHosts: "deploy-key-known-hosts\n",
+				},
+				CustomerDeployKey: cmc.DeployKey{
+					Passphrase: "deploy-key-passphrase\n",
+					Identity:   "deploy-key-identity\n",
+					KnownHosts: "deploy-key-known-hosts\n",
+				},
+				SharedDeployKey: cmc.DeployKey{
+					Passphrase: "deploy-key-passphrase\n",
+					Identity:   "deploy-key-identity\n",
+					KnownHosts: "deploy-key-known-hosts\n",
+				},
+			},
+		},
+		{
+			name:          "secrets of the input are kept",
+			secretsFolder: "testdata/valid",
+			input: cmc.CMC{
+				Cluster:    "test",
+				Provider:   cmc.Provider{Name: "capa"},
+				ClusterApp: cmc.App{Name: "cluster-aws", AppName: "test", Values: "input-values\n"},
+			},
+			expectOutput: cmc.CMC{
+				Cluster:    "test",
+				Provider:   cmc.Provider{Name: "capa"},
+				ClusterApp: cmc.App{Name: "cluster-aws", AppName: "test", Values: "input-values\n"},
+				DefaultApps: cmc.App{
+					AppName: "test-default-apps",
+					Values:  "clusterName: test\norganization: giantswarm\nmanagementCluster: test\n",
+				},
+				SSHdeployKey: cmc.DeployKey{
+					Passphrase: "deploy-key-passphrase\n",
+					Identity:   "deploy-key-identity\n",
+					KnownHosts: "deploy-key-known-hosts\n",
+				},
+				CustomerDeployKey: cmc.DeployKey{
+					Passphrase: "deploy-key-passphrase\n",
+					Identity:   "deploy-key-identity\n",
+					KnownHosts: "deploy-key-known-hosts\n",
+				},
+				SharedDeployKey: cmc.DeployKey{
+					Passphrase: "deploy-key-passphrase\n",
+					Identity:   "deploy-key-identity\n",
+					KnownHosts: "deploy-key-known-hosts\n",
+				},
+			},
+		},
+		{
+			name:          "nonexistent secret folder",
+			secretsFolder: "testdata/nonexistent",
+			input:         cmc.CMC{Provider: cmc.Provider{Name: "capa"}},
+			expectErr:     true,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("case %d: %s", i, tc.name), func(t *testing.T) {
+			input := tc.input
+			c := &Config{
+				Cluster:           "test",
+				Input:             &input,
+				InputSecretFolder: tc.secretsFolder,
+			}
+
+			err := c.ReadSecretFlags()
+			if err != nil && !tc.expectErr {
+				t.Fatalf("unexpected error: %v", err)
+			} else if err == nil && tc.expectErr {
+				t.Fatalf("expected error, got nil")
+			}
+			if !tc.expectErr && !reflect.DeepEqual(*c.Input, tc.expectOutput) {
+				t.Fatalf("expected %#v, got %#v", tc.expectOutput, *c.Input)
+			}
+		})
+	}
+}
@@ -0,0 +1,297 @@
+package pushinstallations
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/giantswarm/mcli/pkg/github"
+	"github.com/giantswarm/mcli/pkg/github/githubtest"
+	"github.com/giantswarm/mcli/pkg/key"
+	"github.com/giantswarm/mcli/pkg/managementcluster/installations"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+func TestPushInstallationsEndToEnd(t *testing.T) {
+	var testCases = []struct {
+		name   string
+		create Config
+		update Config
+
+		expectChangedFields []string
+	}{
+		{
+			name: "capa",
+			create: Config{
+				Provider:      key.ProviderAWS,
+				BaseDomain:    "test.gigantic.io",
+				CMCRepository: "giantswarm-management-clusters",
+				Flags: InstallationsFlags{
+					Team:          "phoenix",
+					Customer:      "giantswarm",
+					CCRRepository: "giantswarm-customer-configs",
+					Pipeline:      "testing",
+					AWS: AWSFlags{
+						Region:                 "eu-west-1",
+						InstallationAWSAccount: "123456789012",
+					},
+				},
+			},
+			update: Config{
+				Flags: InstallationsFlags{Team: "turtles"},
+			},
+			expectChangedFields: []string{"accountEngineer"},
+		},
+		{
+			name: "capz",
+			create: Config{
+				Provider:      key.ProviderAzure,
+				BaseDomain:    "test.gigantic.io",
+				CMCRepository: "giantswarm-management-clusters",
+				Flags: InstallationsFlags{
+					Team:          "phoenix",
+					Customer:      "giantswarm",
+					CCRRepository: "giantswarm-customer-configs",
+					Pipeline:      "testing",
+				},
+			},
+			update: Config{
+				Flags: InstallationsFlags{Pipeline: "stable"},
+			},
+			expectChangedFields: []string{"pipeline"},
+		},
+		{
+			name: "vsphere",
+			create: Config{
+				Provider:      key.ProviderVsphere,
+				BaseDomain:    "test.gigantic.io",
+				CMCRepository: "giantswarm-management-clusters",
+				Flags: InstallationsFlags{
+					Team:          "rocket",
+					Customer:      "giantswarm",
+					CCRRepository: "giantswarm-customer-configs",
+					Pipeline:      "testing",
+				},
+			},
+			update: Config{
+				BaseDomain: "new.gigantic.io",
+			},
+			expectChangedFields: []string{"base"},
+		},
+		{
+			name: "cloud-director",
+			create: Config{
+				Provider:      key.ProviderVCD,
+				BaseDomain:    "test.gigantic.io",
+				CMCRepository: "giantswarm-management-clusters",
+				Flags: InstallationsFlags{
+					Team:          "rocket",
+					Customer:      "giantswarm",
+					CCRRepository: "giantswarm-customer-configs",
+					Pipeline:      "testing",
+				},
+			},
+			update: Config{
+				CMCRepository: "other-management-clusters",
+			},
+			expectChangedFields: []string{"cmc_repository"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := githubtest.NewServer()
+			defer server.Close()
+			server.AddBranchRepository(key.OrganizationGiantSwarm, key.RepositoryInstallations, key.InstallationsMainBranch, map[string]string{
+				"README.md": "installations",
+			})
+
+			cluster := "test"
+			branch := key.GetDefaultPRBranch(cluster)
+			path := key.GetInstallationsPath(cluster)
+			ctx := context.Background()
+
+			steps := []struct {
+				name   string
+				config Config
+
+				expectStatus        string
+				expectChangedFields []string
+			}{
+				{name: "create", config: tc.create, expectStatus: github.StatusCreated},
+				{name: "update", config: tc.update, expectStatus: github.StatusUpdated, expectChangedFields: tc.expectChangedFields},
+				{name: "no-op", config: tc.update, expectStatus: github.StatusUnchanged},
+			}
+			for _, step := range steps {
+				c := step.config
+				c.Cluster = cluster
+				c.Github = server.Client()
+				c.InstallationsBranch = branch
+
+				// the dry run reports the changes of the push without pushing
+				commits := server.Commits(key.OrganizationGiantSwarm, key.RepositoryInstallations, branch)
+				current, desired, err := c.GetCurrentAndDesired(ctx)
+				if err != nil {
+					t.Fatalf("%s: unexpected error: %v", step.name, err)
+				}
+				dryRunFields, err := key.GetChangedFields(current, desired)
+				if err != nil {
+					t.Fatalf("%s: unexpected error: %v", step.name, err)
+				}
+				if after := server.Commits(key.OrganizationGiantSwarm, key.RepositoryInstallations, branch); len(after) != len(commits) {
+					t.Fatalf("%s: expected no commit in dry run, got %v", step.name, after)
+				}
+
+				_, summary, err := c.Run(ctx)
+				if err != nil {
+					t.Fatalf("%s: unexpected error: %v", step.name, err)
+				}
+				if len(dryRunFields) != len(summary.ChangedFields) || (len(dryRunFields) > 0 && !reflect.DeepEqual(dryRunFields, summary.ChangedFields)) {
+					t.Fatalf("%s: expected dry run fields %v to match pushed fields %v", step.name, dryRunFields, summary.ChangedFields)
+				}
+				if summary.Status != step.expectStatus {
+					t.Fatalf("%s: expected status %s, got %s", step.name, step.expectStatus, summary.Status)
+				}
+				if step.expectStatus == github.StatusUnchanged {
+					if summary.CommitSHA != "" || len(summary.Files) != 0 {
+						t.Fatalf("%s: expected no commit, got %v", step.name, summary)
+					}
+				} else if !reflect.DeepEqual(summary.Files, []string{path}) {
+					t.Fatalf("%s: expected files %v, got %v", step.name, []string{path}, summary.Files)
+				}
+				if step.expectChangedFields != nil && !reflect.DeepEqual(summary.ChangedFields, step.expectChangedFields) {
+					t.Fatalf("%s: expected changed fields %v, got %v", step.name, step.expectChangedFields, summary.ChangedFields)
+				}
+
+				files := server.Files(key.OrganizationGiantSwarm, key.RepositoryInstallations, branch)
+				compareGolden(t, filepath.Join("testdata", "golden", fmt.Sprintf("%s-%s.yaml", tc.name, step.name)), files[path])
+			}
+
+			if commits := server.Commits(key.OrganizationGiantSwarm, key.RepositoryInstallations, branch); len(commits) != 3 {
+				t.Fatalf("expected the base commit and 2 pushed commits, got %v", commits)
+			}
+			if files := server.Files(key.OrganizationGiantSwarm, key.RepositoryInstallations, key.InstallationsMainBranch); len(files) != 1 {
+				t.Fatalf("expected %s branch to be unchanged, got %v", key.InstallationsMainBranch, files)
+			}
+		})
+	}
+}
+
+func TestPushInstallationsSchemaEndToEnd(t *testing.T) {
+	schema := `{
+  "type": "object",
+  "properties": {
+    "pipeline": {"type": "string", "enum": ["testing", "stable"]},
+    "replicas": {"type": "integer"},
+    "slack": {"type": "object", "properties": {"channel": {"type": "string"}}}
+  }
+}`
+	server := githubtest.NewServer()
+	defer server.Close()
+	server.AddBranchRepository(key.OrganizationGiantSwarm, key.RepositoryInstallations, key.InstallationsMainBranch, map[string]string{
+		key.SchemaFile: schema,
+	})
+
+	cluster := "test"
+	branch := key.GetDefaultPRBranch(cluster)
+	path := key.GetInstallationsPath(cluster)
+	ctx := context.Background()
+
+	steps := []struct {
+		name   string
+		config Config
+
+		expectError         error
+		expectChangedFields []string
+	}{
+		{
+			name: "create with fields",
+			config: Config{
+				Provider:      key.ProviderAzure,
+				BaseDomain:    "test.gigantic.io",
+				CMCRepository: "giantswarm-management-clusters",
+				Flags: InstallationsFlags{
+					Team:          "phoenix",
+					Customer:      "giantswarm",
+					CCRRepository: "giantswarm-customer-configs",
+					Pipeline:      "testing",
+					Fields:        []string{"replicas=3", "slack.channel=#alert-test"},
+				},
+			},
+		},
+		{
+			name: "invalid field",
+			config: Config{
+				Flags: InstallationsFlags{Fields: []string{"pipeline=unknown"}},
+			},
+			expectError: installations.ErrSchemaValidation,
+		},
+		{
+			name: "invalid field type",
+			config: Config{
+				Flags: InstallationsFlags{Fields: []string{"replicas=three"}},
+			},
+			expectError: installations.ErrInvalidField,
+		},
+		{
+			name: "update keeps fields",
+			config: Config{
+				Flags: InstallationsFlags{Team: "turtles"},
+			},
+			expectChangedFields: []string{"accountEngineer"},
+		},
+	}
+	for _, step := range steps {
+		c := step.config
+		c.Cluster = cluster
+		c.Github = server.Client()
+		c.InstallationsBranch = branch
+
+		_, summary, err := c.Run(ctx)
+		if step.expectError != nil {
+			if !errors.Is(err, step.expectError) {
+				t.Fatalf("%s: expected error %v, got %v", step.name, step.expectError, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", step.name, err)
+		}
+		if step.expectChangedFields != nil && !reflect.DeepEqual(summary.ChangedFields, step.expectChangedFields) {
+			t.Fatalf("%s: expected changed fields %v, got %v", step.name, step.expectChangedFields, summary.ChangedFields)
+		}
+
+		file := server.Files(key.OrganizationGiantSwarm, key.RepositoryInstallations, branch)[path]
+		for _, expected := range []string{key.GetSchemaHeader("../" + key.SchemaFile), "replicas: 3\n", "slack:\n  channel: '#alert-test'\n"} {
+			if !strings.Contains(file, expected) {
+				t.Fatalf("%s: expected %q in %s", step.name, expected, file)
+			}
+		}
+	}
+}
+
+func compareGolden(t *testing.T, path string, actual string) {
+	t.Helper()
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			t.Fatalf("failed to create golden directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(actual), 0600); err != nil {
+			t.Fatalf("failed to update golden file: %v", err)
+		}
+	}
+	expected, err := os.ReadFile(path) // #nosec G304
+	if err != nil {
+		t.Fatalf("failed to read golden file: %v", err)
+	}
+	if string(expected) != actual {
+		t.Fatalf("%s does not match.\nexpected:\n%s\ngot:\n%s", path, expected, actual)
+	}
+}
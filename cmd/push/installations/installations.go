@@ -9,6 +9,7 @@ import (
 
 	"github.com/giantswarm/mcli/pkg/github"
 	"github.com/giantswarm/mcli/pkg/key"
+	"github.com/giantswarm/mcli/pkg/managementcluster"
 	"github.com/giantswarm/mcli/pkg/managementcluster/installations"
 )
 
@@ -21,6 +22,13 @@ type Config struct {
 	InstallationsBranch string
 	Input               *installations.Installations
 	Flags               InstallationsFlags
+	// Replace uses the input as desired state instead of overriding the non-empty fields of the current one.
+	Replace bool
+	// Unset are paths of fields which are explicitly emptied in the input file.
+	Unset []string
+
+	schema       *installations.Schema
+	schemaLoaded bool
 }
 
 type InstallationsFlags struct {
@@ -29,6 +37,8 @@ type InstallationsFlags struct {
 	AWS           AWSFlags
 	Customer      string
 	Pipeline      string
+	// Fields sets further fields of the schema as path=value.
+	Fields []string
 }
 
 type AWSFlags struct {
@@ -36,19 +46,19 @@ type AWSFlags struct {
 	InstallationAWSAccount string
 }
 
-func (c *Config) Run(ctx context.Context) (*installations.Installations, error) {
+func (c *Config) Run(ctx context.Context) (*installations.Installations, *managementcluster.RepositorySummary, error) {
 	c.Default()
 	err := c.Validate()
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	return c.PushInstallations(ctx)
 }
 
-func (c *Config) PushInstallations(ctx context.Context) (*installations.Installations, error) {
+func (c *Config) PushInstallations(ctx context.Context) (*installations.Installations, *managementcluster.RepositorySummary, error) {
 	if err := c.Branch(ctx); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	// pulling current installations
 	installations, err := c.Pull(ctx)
@@ -59,45 +69,105 @@ func (c *Config) PushInstallations(ctx context.Context) (*installations.Installa
 			log.Debug().Msg(fmt.Sprintf("no current installations %s found, creating a new one", c.Cluster))
 			return c.Create(ctx)
 		} else {
-			return nil, fmt.Errorf("failed to pull installations.\n%w", err)
+			return nil, nil, fmt.Errorf("failed to pull installations.\n%w", err)
 		}
 	}
 	return c.Update(ctx, installations)
 }
 
-func (c *Config) Create(ctx context.Context) (*installations.Installations, error) {
-	var err error
-
+func (c *Config) Create(ctx context.Context) (*installations.Installations, *managementcluster.RepositorySummary, error) {
 	log.Debug().Msg(fmt.Sprintf("creating new installations %s", c.Cluster))
-	var desiredInstallations *installations.Installations
-	{
-		if c.Input == nil {
-			desiredInstallations, err = getNewInstallationsFromFlags(*c)
-			if err != nil {
-				return nil, fmt.Errorf("failed to get new installations object from flags.\n%w", err)
-			}
-		} else {
-			desiredInstallations = c.Input
-		}
+	desiredInstallations, err := c.getNewDesired(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	changedFields, err := key.GetChangedFields(&installations.Installations{}, desiredInstallations)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get changed fields.\n%w", err)
 	}
-	return c.Push(ctx, desiredInstallations)
+	message := key.GetCommitMessage(fmt.Sprintf("Create installations of management cluster %s", c.Cluster), changedFields)
+	return c.Push(ctx, desiredInstallations, message, changedFields)
 }
 
-func (c *Config) Update(ctx context.Context, currentInstallations *installations.Installations) (*installations.Installations, error) {
+func (c *Config) Update(ctx context.Context, currentInstallations *installations.Installations) (*installations.Installations, *managementcluster.RepositorySummary, error) {
 	log.Debug().Msg(fmt.Sprintf("updating installations %s", c.Cluster))
-	var desiredInstallations *installations.Installations
-	{
-		if c.Input == nil {
-			desiredInstallations = overrideInstallationsWithFlags(currentInstallations, *c)
-		} else {
-			desiredInstallations = currentInstallations.Override(c.Input)
-		}
+	var changedFields []string
+	desiredInstallations, err := c.getUpdatedDesired(ctx, currentInstallations)
+	if err != nil {
+		return nil, nil, err
 	}
 	if currentInstallations.Equals(desiredInstallations) {
 		log.Debug().Msg("installations are up to date")
-		return desiredInstallations, nil
+		installationsRepository := c.getRepository()
+		return desiredInstallations, &managementcluster.RepositorySummary{Result: *installationsRepository.NewResult()}, nil
+	}
+	changedFields, err = key.GetChangedFields(currentInstallations, desiredInstallations)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get changed fields.\n%w", err)
+	}
+	message := key.GetCommitMessage(fmt.Sprintf("Update installations of management cluster %s", c.Cluster), changedFields)
+	return c.Push(ctx, desiredInstallations, message, changedFields)
+}
+
+// GetCurrentAndDesired returns the current installations and those which would be pushed without changing the repository.
+// The current state is read from the branch if it exists and from the main branch otherwise. It is empty for new installations.
+func (c *Config) GetCurrentAndDesired(ctx context.Context) (*installations.Installations, *installations.Installations, error) {
+	c.Default()
+	if err := c.Validate(); err != nil {
+		return nil, nil, err
+	}
+	current := *c
+	repository := c.getRepository()
+	err := repository.CheckBranch(ctx)
+	if github.IsNotFound(err) {
+		current.InstallationsBranch = key.InstallationsMainBranch
+	} else if err != nil {
+		return nil, nil, fmt.Errorf("failed to check installations branch %s.\n%w", c.InstallationsBranch, err)
+	}
+	currentInstallations, err := current.Pull(ctx)
+	if github.IsNotFound(err) {
+		desiredInstallations, err := c.getNewDesired(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		return &installations.Installations{}, desiredInstallations, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to pull installations.\n%w", err)
+	}
+	desiredInstallations, err := c.getUpdatedDesired(ctx, currentInstallations)
+	if err != nil {
+		return nil, nil, err
+	}
+	return currentInstallations, desiredInstallations, nil
+}
+
+func (c *Config) getNewDesired(ctx context.Context) (*installations.Installations, error) {
+	var err error
+	desiredInstallations := c.Input
+	if desiredInstallations == nil {
+		desiredInstallations, err = getNewInstallationsFromFlags(*c)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get new installations object from flags.\n%w", err)
+		}
+	}
+	return c.setFields(ctx, desiredInstallations)
+}
+
+func (c *Config) getUpdatedDesired(ctx context.Context, currentInstallations *installations.Installations) (*installations.Installations, error) {
+	var desiredInstallations *installations.Installations
+	if c.Input == nil {
+		desiredInstallations = overrideInstallationsWithFlags(currentInstallations, *c)
+	} else if c.Replace {
+		desiredInstallations = c.Input
+	} else {
+		var err error
+		desiredInstallations, err = currentInstallations.Override(c.Input).Unset(c.Unset)
+		if err != nil {
+			return nil, err
+		}
 	}
-	return c.Push(ctx, desiredInstallations)
+	return c.setFields(ctx, desiredInstallations)
 }
 
 func (c *Config) Pull(ctx context.Context) (*installations.Installations, error) {
@@ -123,43 +193,106 @@ func (c *Config) Pull(ctx context.Context) (*installations.Installations, error)
 	return installations, nil
 }
 
-func (c *Config) Push(ctx context.Context, i *installations.Installations) (*installations.Installations, error) {
+func (c *Config) Push(ctx context.Context, i *installations.Installations, message string, changedFields []string) (*installations.Installations, *managementcluster.RepositorySummary, error) {
 	// check if i is valid
 	err := i.Validate()
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	if i.Codename != c.Cluster {
-		return nil, fmt.Errorf("cluster name %s does not match installations codename %s", c.Cluster, i.Codename)
+		return nil, nil, fmt.Errorf("cluster name %s does not match installations codename %s", c.Cluster, i.Codename)
 	}
 
 	log.Debug().Msg(fmt.Sprintf("pushing installations %s", c.Cluster))
 	data, err := installations.GetData(i)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	installationsRepository := github.Repository{
-		Github:       c.Github,
-		Name:         key.RepositoryInstallations,
-		Organization: key.OrganizationGiantSwarm,
-		Branch:       c.InstallationsBranch,
+	installationsRepository := c.getRepository()
+
+	// Validate and prepend schema header if schema.json exists in the repository
+	schema, err := c.getSchema(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	if schema != nil {
+		if err := schema.Validate(i); err != nil {
+			return nil, nil, fmt.Errorf("installations %s do not match %s.\n%w", c.Cluster, key.SchemaFile, err)
+		}
+		// Config files are at $cluster/cluster.yaml, schema is at schema.json
+		data = key.PrependSchemaHeader(data, "../"+key.SchemaFile)
+	}
+
+	pushed, err := installationsRepository.CreateFileWithMessage(ctx, data, key.GetInstallationsPath(c.Cluster), message)
+	if err != nil {
+		return nil, nil, err
+	}
+	summary := &managementcluster.RepositorySummary{
+		Result: *pushed,
 	}
+	if pushed.Status != github.StatusUnchanged {
+		summary.ChangedFields = changedFields
+	}
+	return i, summary, nil
+}
 
-	// Prepend schema header if schema.json exists in the repository
+// getSchema returns the schema of the installations repository or nil if there is none.
+func (c *Config) getSchema(ctx context.Context) (*installations.Schema, error) {
+	if c.schemaLoaded {
+		return c.schema, nil
+	}
+	installationsRepository := c.getRepository()
 	schemaExists, err := installationsRepository.FileExists(ctx, key.SchemaFile)
 	if err != nil {
 		log.Debug().Msg(fmt.Sprintf("failed to check if schema.json exists: %v", err))
-	} else if schemaExists {
-		// Config files are at $cluster/cluster.yaml, schema is at schema.json
-		data = key.PrependSchemaHeader(data, "../"+key.SchemaFile)
+		return nil, nil
 	}
+	c.schemaLoaded = true
+	if !schemaExists {
+		return nil, nil
+	}
+	data, err := installationsRepository.GetFile(ctx, key.SchemaFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s.\n%w", key.SchemaFile, err)
+	}
+	c.schema, err = installations.GetSchema([]byte(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s.\n%w", key.SchemaFile, err)
+	}
+	return c.schema, nil
+}
 
-	err = installationsRepository.CreateFile(ctx, data, key.GetInstallationsPath(c.Cluster))
+// setFields sets the fields given as path=value. Values are typed according to the schema.
+func (c *Config) setFields(ctx context.Context, i *installations.Installations) (*installations.Installations, error) {
+	if len(c.Flags.Fields) == 0 {
+		return i, nil
+	}
+	schema, err := c.getSchema(ctx)
 	if err != nil {
 		return nil, err
 	}
-	return i, nil
+	if schema == nil {
+		schema = &installations.Schema{}
+	}
+	fields := map[string]any{}
+	for _, field := range c.Flags.Fields {
+		path, value, _ := strings.Cut(field, "=")
+		fields[path], err = schema.ParseValue(path, value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse field %s.\n%w", path, err)
+		}
+	}
+	return i.SetFields(fields)
+}
+
+func (c *Config) getRepository() github.Repository {
+	return github.Repository{
+		Github:       c.Github,
+		Name:         key.RepositoryInstallations,
+		Organization: key.OrganizationGiantSwarm,
+		Branch:       c.InstallationsBranch,
+	}
 }
 
 func (c *Config) Branch(ctx context.Context) error {
@@ -273,8 +406,13 @@ func (c *Config) Validate() error {
 	if c.InstallationsBranch == "main" || c.InstallationsBranch == "master" {
 		return fmt.Errorf("cannot push to installations branch %s.\n%w", c.InstallationsBranch, ErrInvalidFlag)
 	}
+	for _, field := range c.Flags.Fields {
+		if path, _, ok := strings.Cut(field, "="); !ok || path == "" {
+			return fmt.Errorf("invalid field %s. Expected format: path=value.\n%w", field, ErrInvalidFlag)
+		}
+	}
 	if c.Input != nil {
-		log.Debug().Msg("using input file. Other installations flags will be ignored")
+		log.Debug().Msg("using input file. Other installations flags except fields will be ignored")
 		return nil
 	}
 	if c.BaseDomain == "" &&
@@ -285,7 +423,8 @@ func (c *Config) Validate() error {
 		c.Provider == "" &&
 		c.Flags.AWS.Region == "" &&
 		c.Flags.Customer == "" &&
-		c.Flags.AWS.InstallationAWSAccount == "" {
+		c.Flags.AWS.InstallationAWSAccount == "" &&
+		len(c.Flags.Fields) == 0 {
 		return fmt.Errorf("no input file or flags specified.\n%w", ErrInvalidFlag)
 	}
 
@@ -3,6 +3,8 @@ package push
 import (
 	"context"
 	"fmt"
+	"os"
+	"path/filepath"
 
 	"github.com/rs/zerolog/log"
 
@@ -11,6 +13,7 @@ import (
 	"github.com/giantswarm/mcli/pkg/github"
 	"github.com/giantswarm/mcli/pkg/key"
 	"github.com/giantswarm/mcli/pkg/managementcluster"
+	"github.com/giantswarm/mcli/pkg/policy"
 )
 
 type Config struct {
@@ -26,26 +29,45 @@ type Config struct {
 	CMCRepository       string
 	CMCFlags            pushcmc.CMCFlags
 	DisplaySecrets      bool
+	Output              string
+	// DryRun prints the changed fields and evaluates the policy without pushing.
+	DryRun bool
+	// Policy is evaluated before anything is pushed. Violations with severity deny abort the push.
+	Policy *policy.Policy
 }
 
-func Run(c Config, ctx context.Context) error {
-	mc, err := c.Push(ctx)
+func Run(c Config, ctx context.Context) (*managementcluster.Summary, error) {
+	mc, summary, err := c.Push(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to push management cluster configuration.\n%w", err)
+		return nil, fmt.Errorf("failed to push management cluster configuration.\n%w", err)
 	}
-	return mc.Print()
+	if c.DryRun {
+		return nil, nil
+	}
+	if c.Output == key.OutputJSON {
+		return summary, summary.Print()
+	}
+	return summary, mc.Print()
 }
 
-func (c *Config) Push(ctx context.Context) (*managementcluster.ManagementCluster, error) {
+func (c *Config) Push(ctx context.Context) (*managementcluster.ManagementCluster, *managementcluster.Summary, error) {
 	var err error
 
 	log.Debug().Msg(fmt.Sprintf("pushing management cluster %s", c.Cluster))
 	mc := &managementcluster.ManagementCluster{}
+	summary := &managementcluster.Summary{
+		Cluster: c.Cluster,
+	}
 
+	var data []byte
 	if c.Input != "" {
-		mc, err = managementcluster.GetManagementClusterFromFile(c.Input)
+		data, err = os.ReadFile(c.Input)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read input file %s.\n%w", c.Input, err)
+		}
+		mc, err = managementcluster.GetManagementCluster(data)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get new management cluster object from input file.\n%w", err)
+			return nil, nil, fmt.Errorf("failed to get new management cluster object from input file.\n%w", err)
 		}
 	}
 
@@ -53,9 +75,9 @@ func (c *Config) Push(ctx context.Context) (*managementcluster.ManagementCluster
 		Token: c.GithubToken,
 	})
 
+	var i *pushinstallations.Config
 	if !key.Skip(key.RepositoryInstallations, c.Skip) {
-
-		i := pushinstallations.Config{
+		i = &pushinstallations.Config{
 			Cluster:             c.Cluster,
 			Github:              client,
 			InstallationsBranch: c.InstallationsBranch,
@@ -66,15 +88,15 @@ func (c *Config) Push(ctx context.Context) (*managementcluster.ManagementCluster
 		}
 		if c.Input != "" {
 			i.Input = &mc.Installations
+			i.Unset, err = key.GetUnsetFields(data, "installations")
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to get unset installations fields.\n%w", err)
+			}
 		}
-		installations, err := i.Run(ctx)
-		if err != nil {
-			return nil, fmt.Errorf("failed to push installations.\n%w", err)
-		}
-		mc.Installations = *installations
 	}
+	var m *pushcmc.Config
 	if !key.Skip(key.RepositoryCMC, c.Skip) {
-		i := pushcmc.Config{
+		m = &pushcmc.Config{
 			Cluster:        c.Cluster,
 			Github:         client,
 			CMCBranch:      c.CMCBranch,
@@ -84,13 +106,82 @@ func (c *Config) Push(ctx context.Context) (*managementcluster.ManagementCluster
 			BaseDomain:     c.BaseDomain,
 		}
 		if c.Input != "" {
-			i.Input = &mc.CMC
+			m.Input = &mc.CMC
+			m.InputSecretFolder = getInputSecretFolder(c.Input, mc.SecretFolder)
+			m.Unset, err = key.GetUnsetFields(data, "cmc")
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to get unset cmc fields.\n%w", err)
+			}
+		}
+	}
+	if c.DryRun || c.Policy != nil {
+		current, desired, err := c.getCurrentAndDesired(ctx, i, m)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := c.checkPolicy(desired); err != nil {
+			return nil, nil, err
+		}
+		if c.DryRun {
+			return nil, nil, managementcluster.PrintChanges(os.Stdout, current, desired, c.DisplaySecrets)
+		}
+	}
+
+	if i != nil {
+		installations, result, err := i.Run(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to push installations.\n%w", err)
 		}
-		cmc, err := i.Run(ctx)
+		mc.Installations = *installations
+		summary.Repositories = append(summary.Repositories, result)
+	}
+	if m != nil {
+		cmc, result, err := m.Run(ctx)
 		if err != nil {
-			return nil, fmt.Errorf("failed to push cmc.\n%w", err)
+			return nil, nil, fmt.Errorf("failed to push cmc.\n%w", err)
 		}
 		mc.CMC = *cmc
+		summary.Repositories = append(summary.Repositories, result)
+	}
+	return mc, summary, nil
+}
+
+// getCurrentAndDesired returns the current state and the state which would be pushed before any repository is changed.
+// Skipped repositories are left empty.
+func (c *Config) getCurrentAndDesired(ctx context.Context, i *pushinstallations.Config, m *pushcmc.Config) (*managementcluster.ManagementCluster, *managementcluster.ManagementCluster, error) {
+	current := &managementcluster.ManagementCluster{}
+	desired := &managementcluster.ManagementCluster{}
+	if i != nil {
+		currentInstallations, desiredInstallations, err := i.GetCurrentAndDesired(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get desired installations.\n%w", err)
+		}
+		current.Installations = *currentInstallations
+		desired.Installations = *desiredInstallations
+	}
+	if m != nil {
+		currentCMC, desiredCMC, err := m.GetCurrentAndDesired(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get desired cmc.\n%w", err)
+		}
+		current.CMC = *currentCMC
+		desired.CMC = *desiredCMC
+	}
+	return current, desired, nil
+}
+
+// checkPolicy evaluates the policy against the desired state. Rules on fields of skipped repositories are not evaluated.
+func (c *Config) checkPolicy(desired *managementcluster.ManagementCluster) error {
+	if c.Policy == nil {
+		return nil
+	}
+	return c.Policy.Without(c.Skip).Check(os.Stderr, desired)
+}
+
+// getInputSecretFolder resolves the secret folder relative to the input file.
+func getInputSecretFolder(input string, secretFolder string) string {
+	if secretFolder == "" || filepath.IsAbs(secretFolder) {
+		return secretFolder
 	}
-	return mc, nil
+	return filepath.Join(filepath.Dir(input), secretFolder)
 }
@@ -12,15 +12,18 @@ import (
 	"github.com/spf13/viper"
 
 	"github.com/giantswarm/mcli/pkg/key"
+	"github.com/giantswarm/mcli/pkg/managementcluster/cmc/certmanager"
+	"github.com/giantswarm/mcli/pkg/managementcluster/cmc/mcproxy"
 )
 
 // installations flags
 const (
-	flagCCRRepository = "ccr-repository"
-	flagPipeline      = "pipeline"
-	flagTeam          = "team"
-	flagAWSRegion     = "aws-region"
-	flagAWSAccountID  = "aws-account-id"
+	flagCCRRepository      = "ccr-repository"
+	flagPipeline           = "pipeline"
+	flagTeam               = "team"
+	flagAWSRegion          = "aws-region"
+	flagAWSAccountID       = "aws-account-id"
+	flagInstallationsField = "installations-field"
 )
 
 const (
@@ -32,11 +35,12 @@ const (
 )
 
 var (
-	ccrRepository string
-	pipeline      string
-	team          string
-	awsRegion     string
-	awsAccountID  string
+	ccrRepository       string
+	pipeline            string
+	team                string
+	awsRegion           string
+	awsAccountID        string
+	installationsFields []string
 )
 
 // cmc flags
@@ -55,15 +59,22 @@ const (
 	flagPrivateCA                    = "private-ca"
 	flagPrivateMC                    = "private-mc"
 	flagCertManagerDNSChallenge      = "cert-manager-dns-challenge"
+	flagCertManagerDNSSolver         = "cert-manager-dns-solver"
 	flagMCCustomCoreDNSConfig        = "mc-custom-coredns-config"
 	flagMCProxyEnabled               = "mc-proxy-enabled"
 	flagMCHTTPSProxy                 = "mc-https-proxy"
+	flagMCHTTPProxy                  = "mc-http-proxy"
+	flagMCNoProxy                    = "mc-no-proxy"
+	flagMCProxyCIDR                  = "mc-proxy-cidr"
+	flagMCProxyGithubPort            = "mc-proxy-github-port"
+	flagCredentialExpiry             = "credential-expiry"
 	flagAgePubKey                    = "age-pub-key"
 	flagTaylorBotToken               = "taylor-bot-token"
 	flagMCBBranchSource              = "mcb-branch-source"
 	flagConfigBranch                 = "config-branch"
 	flagMCAppCollectionBranch        = "mc-app-collection-branch"
 	flagRegistryDomain               = "registry-domain"
+	flagOutput                       = "output"
 )
 
 const (
@@ -81,9 +92,15 @@ const (
 	envPrivateCA                    = "PRIVATE_CA"
 	envPrivateMC                    = "MC_PRIVATE"
 	envCertManagerDNSChallenge      = "CERT_MANAGER_DNS01_CHALLENGE"
+	envCertManagerDNSSolver         = "CERT_MANAGER_DNS01_SOLVER"
 	envMCCustomCoreDNSConfig        = "MC_CUSTOM_COREDNS_CONFIG"
 	envMCProxyEnabled               = "MC_PROXY_ENABLED"
 	envMCHTTPSProxy                 = "MC_HTTPS_PROXY"
+	envMCHTTPProxy                  = "MC_HTTP_PROXY"
+	envMCNoProxy                    = "MC_NO_PROXY"
+	envMCProxyCIDR                  = "MC_PROXY_CIDR"
+	envMCProxyGithubPort            = "MC_PROXY_GITHUB_PORT"
+	envCredentialExpiry             = "CREDENTIAL_EXPIRY"
 	envAgePubKey                    = "AGE_PUBKEY"
 	envMCBBranchSource              = "MCB_BRANCH_SOURCE"
 	envConfigBranch                 = "CONFIG_BRANCH"
@@ -106,14 +123,22 @@ var (
 	privateCA                    bool
 	privateMC                    bool
 	certManagerDNSChallenge      bool
+	certManagerDNSSolver         string
 	mcCustomCoreDNSConfig        string
 	mcProxyEnabled               bool
 	mcHTTPSProxy                 string
+	mcHTTPProxy                  string
+	mcNoProxy                    []string
+	mcProxyCIDR                  string
+	mcProxyGithubPort            string
+	credentialExpiry             map[string]string
 	agePubKey                    string
 	mcbBranchSource              string
 	configBranch                 string
 	mcAppCollectionBranch        string
 	registryDomain               string
+	output                       string
+	pushDryRun                   bool
 )
 
 // extra cmc flags that are read from the secrets folder and not exposed
@@ -144,6 +169,22 @@ const (
 	flagCertManagerRoute53SecretAccessKey = "cert-manager-route53-secret-access-key" // #nosec G101
 )
 
+// cert-manager DNS01 solver flags
+const (
+	flagCertManagerAzureSubscriptionID          = "cert-manager-azure-subscription-id"
+	flagCertManagerAzureResourceGroup           = "cert-manager-azure-resource-group"
+	flagCertManagerAzureHostedZone              = "cert-manager-azure-hosted-zone"
+	flagCertManagerAzureManagedIdentityClientID = "cert-manager-azure-managed-identity-client-id"
+	flagCertManagerAzureTenantID                = "cert-manager-azure-tenant-id"
+	flagCertManagerAzureClientID                = "cert-manager-azure-client-id"
+	flagCertManagerAzureClientSecret            = "cert-manager-azure-client-secret"  // #nosec G101
+	flagCertManagerCloudflareAPIToken           = "cert-manager-cloudflare-api-token" // #nosec G101
+	flagCertManagerRFC2136Nameserver            = "cert-manager-rfc2136-nameserver"
+	flagCertManagerRFC2136TSIGKeyName           = "cert-manager-rfc2136-tsig-key-name"
+	flagCertManagerRFC2136TSIGAlgorithm         = "cert-manager-rfc2136-tsig-algorithm"
+	flagCertManagerRFC2136TSIGSecret            = "cert-manager-rfc2136-tsig-secret" // #nosec G101
+)
+
 var (
 	taylorBotToken                    string
 	deployKeyPassphrase               string
@@ -170,6 +211,9 @@ var (
 	certManagerRoute53Role            string
 	certManagerRoute53AccessKeyID     string
 	certManagerRoute53SecretAccessKey string
+	certManagerAzureDNS               certmanager.AzureDNS
+	certManagerCloudflareAPIToken     string
+	certManagerRFC2136                certmanager.RFC2136
 )
 
 func addFlagsPush() {
@@ -178,8 +222,11 @@ func addFlagsPush() {
 	// add general flags
 	pushCmd.Flags().StringArrayVarP(&skip, flagSkip, "s", []string{}, fmt.Sprintf("List of repositories to skip. (default: none) Valid values: %s", key.GetValidRepositories()))
 	pushCmd.PersistentFlags().StringVarP(&input, flagInput, "i", "", "Input configuration file to use. If not specified, configuration is read from other flags.")
+	addFlagPolicy(pushCmd)
+	pushCmd.Flags().BoolVar(&pushDryRun, flagDryRun, false, "Print the changed fields and evaluate the policy without pushing. (default: false)")
 	pushCmd.PersistentFlags().StringVar(&provider, flagProvider, viper.GetString(envProvider), "Provider of the cluster")
 	pushCmd.PersistentFlags().StringVar(&baseDomain, flagBaseDomain, viper.GetString(envBaseDomain), "Base domain to use for the cluster")
+	pushCmd.PersistentFlags().StringVarP(&output, flagOutput, "o", key.OutputYAML, fmt.Sprintf("Output format. %s prints the resulting configuration, %s a summary of the changes. Valid values: %s", key.OutputYAML, key.OutputJSON, key.GetValidOutputs()))
 
 	// add installations flags
 	pushCmd.PersistentFlags().StringVar(&ccrRepository, flagCCRRepository, viper.GetString(envCCRRepository), "CCR repository to use for the cluster")
@@ -187,6 +234,7 @@ func addFlagsPush() {
 	pushCmd.PersistentFlags().StringVar(&team, flagTeam, viper.GetString(envTeam), "Name of the team that owns the cluster")
 	pushCmd.PersistentFlags().StringVar(&awsRegion, flagAWSRegion, viper.GetString(envAWSRegion), "AWS region of the cluster")
 	pushCmd.PersistentFlags().StringVar(&awsAccountID, flagAWSAccountID, viper.GetString(envAWSAccountID), "AWS account ID of the cluster")
+	pushCmd.PersistentFlags().StringArrayVar(&installationsFields, flagInstallationsField, []string{}, "Field of the installations schema to set as path=value, e.g. aws.hostCluster.guardDuty=true. Values are typed according to schema.json of the installations repository.")
 
 	// add cmc flags
 	pushCmd.PersistentFlags().StringVar(&secretFolder, flagSecretFolder, viper.GetString(envSecretFolder), "Secrets folder to use for the cluster")
@@ -203,9 +251,15 @@ func addFlagsPush() {
 	pushCmd.PersistentFlags().BoolVar(&privateCA, flagPrivateCA, viper.GetBool(envPrivateCA), "Use private CA")
 	pushCmd.PersistentFlags().BoolVar(&privateMC, flagPrivateMC, viper.GetBool(envPrivateMC), "MC is private")
 	pushCmd.PersistentFlags().BoolVar(&certManagerDNSChallenge, flagCertManagerDNSChallenge, viper.GetBool(envCertManagerDNSChallenge), "Use cert-manager DNS01 challenge")
+	pushCmd.PersistentFlags().StringVar(&certManagerDNSSolver, flagCertManagerDNSSolver, viper.GetString(envCertManagerDNSSolver), fmt.Sprintf("Cert-manager DNS01 solver. Valid values: %v (default: %s)", certmanager.GetSolvers(), certmanager.SolverRoute53))
 	pushCmd.PersistentFlags().StringVar(&mcCustomCoreDNSConfig, flagMCCustomCoreDNSConfig, viper.GetString(envMCCustomCoreDNSConfig), "Custom CoreDNS configuration")
 	pushCmd.PersistentFlags().BoolVar(&mcProxyEnabled, flagMCProxyEnabled, viper.GetBool(envMCProxyEnabled), "Use proxy")
 	pushCmd.PersistentFlags().StringVar(&mcHTTPSProxy, flagMCHTTPSProxy, viper.GetString(envMCHTTPSProxy), "HTTPS proxy to use")
+	pushCmd.PersistentFlags().StringVar(&mcHTTPProxy, flagMCHTTPProxy, viper.GetString(envMCHTTPProxy), "HTTP proxy to use. Defaults to the HTTPS proxy")
+	pushCmd.PersistentFlags().StringSliceVar(&mcNoProxy, flagMCNoProxy, viper.GetStringSlice(envMCNoProxy), "Addresses that should not use the proxy")
+	pushCmd.PersistentFlags().StringVar(&mcProxyCIDR, flagMCProxyCIDR, viper.GetString(envMCProxyCIDR), "CIDR of the proxy. Used for the network policy instead of the proxy hostname")
+	pushCmd.PersistentFlags().StringVar(&mcProxyGithubPort, flagMCProxyGithubPort, viper.GetString(envMCProxyGithubPort), fmt.Sprintf("Port flux uses to reach github through the proxy. (default: %s)", mcproxy.DefaultGithubPort))
+	pushCmd.PersistentFlags().StringToStringVar(&credentialExpiry, flagCredentialExpiry, viper.GetStringMapString(envCredentialExpiry), "Expiry date of credentials that can not be inspected, e.g. azureClientSecret=2025-12-31")
 	pushCmd.PersistentFlags().StringVar(&mcbBranchSource, flagMCBBranchSource, viper.GetString(envMCBBranchSource), "Branch to use for the mcb repository")
 	pushCmd.PersistentFlags().StringVar(&configBranch, flagConfigBranch, viper.GetString(envConfigBranch), "Branch to use for the config repository")
 	pushCmd.PersistentFlags().StringVar(&mcAppCollectionBranch, flagMCAppCollectionBranch, viper.GetString(envMCAppCollectionBranch), "Branch to use for the MC app collection repository")
@@ -293,9 +347,39 @@ func addFlagsPush() {
 	if err != nil {
 		panic(err)
 	}
+
+	pushCmd.PersistentFlags().StringVar(&certManagerAzureDNS.SubscriptionID, flagCertManagerAzureSubscriptionID, "", "Cert Manager Azure DNS subscription ID")
+	pushCmd.PersistentFlags().StringVar(&certManagerAzureDNS.ResourceGroupName, flagCertManagerAzureResourceGroup, "", "Cert Manager Azure DNS resource group")
+	pushCmd.PersistentFlags().StringVar(&certManagerAzureDNS.HostedZoneName, flagCertManagerAzureHostedZone, "", "Cert Manager Azure DNS hosted zone")
+	pushCmd.PersistentFlags().StringVar(&certManagerAzureDNS.ManagedIdentity.ClientID, flagCertManagerAzureManagedIdentityClientID, "", "Cert Manager Azure DNS managed identity client ID")
+	pushCmd.PersistentFlags().StringVar(&certManagerAzureDNS.TenantID, flagCertManagerAzureTenantID, "", "Cert Manager Azure DNS service principal tenant ID")
+	pushCmd.PersistentFlags().StringVar(&certManagerAzureDNS.ClientID, flagCertManagerAzureClientID, "", "Cert Manager Azure DNS service principal client ID")
+	pushCmd.PersistentFlags().StringVar(&certManagerAzureDNS.ClientSecret, flagCertManagerAzureClientSecret, "", "Cert Manager Azure DNS service principal client secret")
+	err = pushCmd.PersistentFlags().MarkHidden(flagCertManagerAzureClientSecret)
+	if err != nil {
+		panic(err)
+	}
+
+	pushCmd.PersistentFlags().StringVar(&certManagerCloudflareAPIToken, flagCertManagerCloudflareAPIToken, "", "Cert Manager Cloudflare API token")
+	err = pushCmd.PersistentFlags().MarkHidden(flagCertManagerCloudflareAPIToken)
+	if err != nil {
+		panic(err)
+	}
+
+	pushCmd.PersistentFlags().StringVar(&certManagerRFC2136.Nameserver, flagCertManagerRFC2136Nameserver, "", "Cert Manager RFC2136 nameserver")
+	pushCmd.PersistentFlags().StringVar(&certManagerRFC2136.TSIGKeyName, flagCertManagerRFC2136TSIGKeyName, "", "Cert Manager RFC2136 TSIG key name")
+	pushCmd.PersistentFlags().StringVar(&certManagerRFC2136.TSIGAlgorithm, flagCertManagerRFC2136TSIGAlgorithm, "", "Cert Manager RFC2136 TSIG algorithm")
+	pushCmd.PersistentFlags().StringVar(&certManagerRFC2136.TSIGSecret, flagCertManagerRFC2136TSIGSecret, "", "Cert Manager RFC2136 TSIG secret")
+	err = pushCmd.PersistentFlags().MarkHidden(flagCertManagerRFC2136TSIGSecret)
+	if err != nil {
+		panic(err)
+	}
 }
 
 func validatePush(cmd *cobra.Command, args []string) error {
+	if !key.IsValidOutput(output) {
+		return fmt.Errorf("invalid output %s. Valid values: %s:\n%w", output, key.GetValidOutputs(), ErrInvalidFlag)
+	}
 	if input != "" {
 		_, err := os.Stat(input)
 		if err != nil {
@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/mcli/cmd/registry"
+	"github.com/giantswarm/mcli/pkg/github"
+	cmcregistry "github.com/giantswarm/mcli/pkg/managementcluster/cmc/registry"
+)
+
+// registryCmd represents the registry command
+var registryCmd = &cobra.Command{
+	Use:   "registry",
+	Short: "Edits the container registries of a Management Cluster",
+}
+
+// registryAddCmd represents the registry add command
+var registryAddCmd = &cobra.Command{
+	Use:   "add",
+	Short: "Adds an endpoint to a container registry of a Management Cluster",
+	Long: `Adds an endpoint (mirror) to a container registry in the CMC repository entry
+of a Management Cluster. An existing endpoint with the same address is replaced.
+Credentials are either a username and password or a token. For example:
+
+mcli registry add --cluster=gigmac --registry=docker.io --endpoint=registry-1.docker.io --username=gigmac --password=$PASSWORD`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c, err := getRegistryConfig(cmd, args)
+		if err != nil {
+			return err
+		}
+		ctx := context.Background()
+		registries, result, err := c.Add(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to add container registry endpoint.\n%w", err)
+		}
+		if err := registry.Print(registries); err != nil {
+			return err
+		}
+		return writeAudit(ctx, cmd, c.Github, cmcRepository, getSummary(result))
+	},
+}
+
+// registryRemoveCmd represents the registry remove command
+var registryRemoveCmd = &cobra.Command{
+	Use:   "remove",
+	Short: "Removes an endpoint or a container registry of a Management Cluster",
+	Long: `Removes an endpoint (mirror) of a container registry in the CMC repository entry
+of a Management Cluster. Without --endpoint the whole registry is removed. For example:
+
+mcli registry remove --cluster=gigmac --registry=docker.io --endpoint=registry-1.docker.io`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c, err := getRegistryConfig(cmd, args)
+		if err != nil {
+			return err
+		}
+		ctx := context.Background()
+		registries, result, err := c.Remove(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to remove container registry.\n%w", err)
+		}
+		if err := registry.Print(registries); err != nil {
+			return err
+		}
+		return writeAudit(ctx, cmd, c.Github, cmcRepository, getSummary(result))
+	},
+}
+
+func getRegistryConfig(cmd *cobra.Command, args []string) (*registry.Config, error) {
+	defaultRegistry()
+	err := validateRegistry(cmd, args)
+	if err != nil {
+		return nil, err
+	}
+	client := github.New(github.Config{
+		Token: githubToken,
+	})
+	return &registry.Config{
+		Cluster:       cluster,
+		Github:        client,
+		CMCRepository: cmcRepository,
+		CMCBranch:     cmcBranch,
+		Registry:      registryName,
+		Endpoint: cmcregistry.Endpoint{
+			Endpoint: registryEndpoint,
+			Username: registryUsername,
+			Password: registryPassword,
+			Token:    registryToken,
+		},
+		DisplaySecrets: displaySecrets,
+	}, nil
+}
+
+func init() {
+	rootCmd.AddCommand(registryCmd)
+	registryCmd.AddCommand(registryAddCmd)
+	registryCmd.AddCommand(registryRemoveCmd)
+	addFlagsRegistry()
+}
@@ -0,0 +1,9 @@
+package registry
+
+import (
+	"errors"
+)
+
+var ErrInvalidFlag = errors.New("invalid flag")
+
+var ErrLastRegistry = errors.New("last container registry can not be removed")
@@ -0,0 +1,127 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/rs/zerolog/log"
+
+	pushcmc "github.com/giantswarm/mcli/cmd/push/cmc"
+	"github.com/giantswarm/mcli/pkg/github"
+	"github.com/giantswarm/mcli/pkg/key"
+	"github.com/giantswarm/mcli/pkg/managementcluster"
+	"github.com/giantswarm/mcli/pkg/managementcluster/cmc"
+	"github.com/giantswarm/mcli/pkg/managementcluster/cmc/registry"
+	"github.com/giantswarm/mcli/pkg/sops"
+)
+
+type Config struct {
+	Cluster        string
+	Github         *github.Github
+	CMCRepository  string
+	CMCBranch      string
+	Registry       string
+	Endpoint       registry.Endpoint
+	DisplaySecrets bool
+}
+
+// Add adds or replaces an endpoint of a container registry in the CMC entry of the management cluster.
+func (c *Config) Add(ctx context.Context) ([]registry.Registry, *managementcluster.RepositorySummary, error) {
+	if c.Endpoint.Endpoint == "" {
+		return nil, nil, fmt.Errorf("endpoint is required\n%w", ErrInvalidFlag)
+	}
+	if c.Endpoint.Token != "" && (c.Endpoint.Username != "" || c.Endpoint.Password != "") {
+		return nil, nil, fmt.Errorf("token can not be combined with username and password\n%w", ErrInvalidFlag)
+	}
+	return c.edit(ctx, func(current cmc.ConfigureContainerRegistries) ([]registry.Registry, error) {
+		return registry.Add(current.Registries, c.Registry, c.Endpoint), nil
+	})
+}
+
+// Remove removes an endpoint or a whole container registry from the CMC entry of the management cluster.
+func (c *Config) Remove(ctx context.Context) ([]registry.Registry, *managementcluster.RepositorySummary, error) {
+	return c.edit(ctx, func(current cmc.ConfigureContainerRegistries) ([]registry.Registry, error) {
+		registries, err := registry.Remove(current.Registries, c.Registry, c.Endpoint.Endpoint)
+		if err != nil {
+			return nil, err
+		}
+		if len(registries) == 0 && current.Values == "" {
+			return nil, fmt.Errorf("registry %s is the only configured registry\n%w", c.Registry, ErrLastRegistry)
+		}
+		return registries, nil
+	})
+}
+
+func (c *Config) Validate() error {
+	// check if environment variable age key is set
+	if val, present := os.LookupEnv(sops.EnvAgeKey); !present || val == "" {
+		return fmt.Errorf("environment variable %s is not set\n%w", sops.EnvAgeKey, ErrInvalidFlag)
+	}
+	if c.Registry == "" {
+		return fmt.Errorf("registry is required\n%w", ErrInvalidFlag)
+	}
+	if c.CMCBranch == key.CMCMainBranch || c.CMCBranch == "master" {
+		return fmt.Errorf("cannot push to cmc branch %s\n%w", c.CMCBranch, ErrInvalidFlag)
+	}
+	return nil
+}
+
+// edit pulls the current entry, replaces its registries and pushes the result.
+func (c *Config) edit(ctx context.Context, update func(cmc.ConfigureContainerRegistries) ([]registry.Registry, error)) ([]registry.Registry, *managementcluster.RepositorySummary, error) {
+	if err := c.Validate(); err != nil {
+		return nil, nil, err
+	}
+	p := pushcmc.Config{
+		Cluster:        c.Cluster,
+		Github:         c.Github,
+		CMCRepository:  c.CMCRepository,
+		CMCBranch:      c.CMCBranch,
+		DisplaySecrets: c.DisplaySecrets,
+	}
+	cmcRepository := github.Repository{
+		Github:       c.Github,
+		Name:         c.CMCRepository,
+		Organization: key.OrganizationGiantSwarm,
+		Branch:       c.CMCBranch,
+	}
+	if err := p.Branch(ctx, cmcRepository); err != nil {
+		return nil, nil, err
+	}
+	currentMap, err := p.Pull(ctx, cmcRepository)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to pull %s entry for %s.\n%w", c.CMCRepository, c.Cluster, err)
+	}
+	current, err := cmc.GetCMCFromMap(currentMap, c.Cluster, c.CMCRepository)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get cmc from map.\n%w", err)
+	}
+
+	registries, err := update(current.ConfigureContainerRegistries)
+	if err != nil {
+		return nil, nil, err
+	}
+	log.Debug().Msgf("updating container registries of %s", c.Cluster)
+	desired := *current
+	desired.ConfigureContainerRegistries = cmc.ConfigureContainerRegistries{
+		Enabled:    true,
+		Registries: registries,
+		Values:     current.ConfigureContainerRegistries.Values,
+	}
+	p.Input = &desired
+
+	result, summary, err := p.Update(ctx, currentMap)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to push %s entry for %s.\n%w", c.CMCRepository, c.Cluster, err)
+	}
+	return result.ConfigureContainerRegistries.Registries, summary, nil
+}
+
+func Print(registries []registry.Registry) error {
+	data, err := key.GetData(registries)
+	if err != nil {
+		return err
+	}
+	fmt.Print(string(data))
+	return nil
+}
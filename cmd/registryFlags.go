@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/giantswarm/mcli/pkg/key"
+)
+
+const (
+	flagRegistry         = "registry"
+	flagRegistryEndpoint = "endpoint"
+	flagRegistryUsername = "username"
+	flagRegistryPassword = "password"
+	flagRegistryToken    = "token"
+)
+
+const (
+	envRegistryUsername = "REGISTRY_USERNAME"
+	envRegistryPassword = "REGISTRY_PASSWORD" // #nosec G101
+	envRegistryToken    = "REGISTRY_TOKEN"    // #nosec G101
+)
+
+var (
+	registryName     string
+	registryEndpoint string
+	registryUsername string
+	registryPassword string
+	registryToken    string
+)
+
+func addFlagsRegistry() {
+	viper.AutomaticEnv()
+
+	registryCmd.PersistentFlags().StringVar(&registryName, flagRegistry, "", "Name of the container registry, e.g. docker.io")
+	registryCmd.PersistentFlags().StringVar(&registryEndpoint, flagRegistryEndpoint, "", "Endpoint (mirror) of the container registry")
+	registryAddCmd.Flags().StringVar(&registryUsername, flagRegistryUsername, viper.GetString(envRegistryUsername), "Username to authenticate at the endpoint")
+	registryAddCmd.Flags().StringVar(&registryPassword, flagRegistryPassword, viper.GetString(envRegistryPassword), "Password to authenticate at the endpoint")
+	registryAddCmd.Flags().StringVar(&registryToken, flagRegistryToken, viper.GetString(envRegistryToken), "Token to authenticate at the endpoint")
+}
+
+func defaultRegistry() {
+	if cmcBranch == "" {
+		cmcBranch = key.GetDefaultPRBranch(cluster)
+	}
+	if customer == "" {
+		customer = key.OrganizationGiantSwarm
+	}
+	if cmcRepository == "" {
+		cmcRepository = key.GetCMCName(customer)
+	}
+}
+
+func validateRegistry(cmd *cobra.Command, args []string) error {
+	err := validateRoot(cmd, args)
+	if err != nil {
+		return err
+	}
+	if registryName == "" {
+		return invalidFlagError(flagRegistry)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/mcli/cmd/revert"
+	"github.com/giantswarm/mcli/pkg/github"
+)
+
+// revertCmd represents the revert command
+var revertCmd = &cobra.Command{
+	Use:   "revert",
+	Short: "Reverts the configuration of a Management Cluster to a previous revision",
+	Long: `Reverts the configuration of a Management Cluster to a previous revision.
+The revision is a commit of the installations or the CMC repository. The other
+repository is reverted to its last commit before it. The configuration at the
+revision is pushed like any other input onto the revert branches, so secrets
+are encrypted again and generated files are kept consistent. For example:
+
+mcli revert --cluster=gigmac --to=1a2b3c4
+
+mcli revert --cluster=gigmac --to=1a2b3c4 --create-pr`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		defaultRevert()
+		err := validateRoot(cmd, args)
+		if err != nil {
+			return err
+		}
+		err = validateRevert()
+		if err != nil {
+			return err
+		}
+		ctx := context.Background()
+		client := github.New(github.Config{
+			Token: githubToken,
+		})
+		c := revert.Config{
+			Cluster:             cluster,
+			Github:              client,
+			To:                  revertTo,
+			CMCRepository:       cmcRepository,
+			InstallationsBranch: installationsBranch,
+			CMCBranch:           cmcBranch,
+			Skip:                skip,
+			CreatePR:            revertCreatePR,
+		}
+		summary, err := c.Run(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to revert management cluster.\n%w", err)
+		}
+		if err := summary.Print(); err != nil {
+			return err
+		}
+		return writeAudit(ctx, cmd, client, c.CMCRepository, summary)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(revertCmd)
+	addFlagsRevert()
+}
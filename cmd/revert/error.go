@@ -0,0 +1,11 @@
+package revert
+
+import (
+	"errors"
+)
+
+var ErrInvalidFlag = errors.New("invalid flag")
+
+var ErrNotFound = errors.New("not found")
+
+var ErrBranchExists = errors.New("branch exists")
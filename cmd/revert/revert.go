@@ -0,0 +1,223 @@
+package revert
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+
+	pullcmc "github.com/giantswarm/mcli/cmd/pull/cmc"
+	pullinstallations "github.com/giantswarm/mcli/cmd/pull/installations"
+	pushcmc "github.com/giantswarm/mcli/cmd/push/cmc"
+	pushinstallations "github.com/giantswarm/mcli/cmd/push/installations"
+	"github.com/giantswarm/mcli/pkg/github"
+	"github.com/giantswarm/mcli/pkg/key"
+	"github.com/giantswarm/mcli/pkg/managementcluster"
+)
+
+type Config struct {
+	Cluster string
+	Github  *github.Github
+	// To is a commit of the installations or the CMC repository.
+	// The other repository is reverted to its last commit before it.
+	To            string
+	CMCRepository string
+	// InstallationsBranch and CMCBranch default to a branch named after the revision. They must not exist yet.
+	InstallationsBranch string
+	CMCBranch           string
+	Skip                []string
+	CreatePR            bool
+}
+
+// Run pushes the configuration of the management cluster at the revision onto the branches.
+// The state is pushed like any other input, so secrets are encrypted again and files are generated from it.
+func (c *Config) Run(ctx context.Context) (*managementcluster.Summary, error) {
+	if c.To == "" {
+		return nil, fmt.Errorf("no revision given.\n%w", ErrInvalidFlag)
+	}
+	installationsRef, cmcRef, revision, err := c.getRefs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if c.InstallationsBranch == "" {
+		c.InstallationsBranch = key.GetRevertBranch(c.Cluster, revision)
+	}
+	if c.CMCBranch == "" {
+		c.CMCBranch = key.GetRevertBranch(c.Cluster, revision)
+	}
+	if err := c.checkBranches(ctx); err != nil {
+		return nil, err
+	}
+
+	summary := &managementcluster.Summary{
+		Cluster: c.Cluster,
+	}
+	if !key.Skip(key.RepositoryInstallations, c.Skip) {
+		p := pullinstallations.Config{
+			Cluster:             c.Cluster,
+			Github:              c.Github,
+			InstallationsBranch: key.InstallationsMainBranch,
+			Ref:                 installationsRef,
+		}
+		desired, err := p.Run(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to pull installations at %s.\n%w", installationsRef, err)
+		}
+		i := pushinstallations.Config{
+			Cluster:             c.Cluster,
+			Github:              c.Github,
+			InstallationsBranch: c.InstallationsBranch,
+			CMCRepository:       c.CMCRepository,
+			Input:               desired,
+			Replace:             true,
+		}
+		_, result, err := i.Run(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to push installations.\n%w", err)
+		}
+		summary.Repositories = append(summary.Repositories, result)
+		if err := c.createPullRequest(ctx, result, c.getRepository(key.RepositoryInstallations, c.InstallationsBranch), key.InstallationsMainBranch, installationsRef); err != nil {
+			return nil, err
+		}
+	}
+	if !key.Skip(key.RepositoryCMC, c.Skip) {
+		p := pullcmc.Config{
+			Cluster:        c.Cluster,
+			Github:         c.Github,
+			CMCRepository:  c.CMCRepository,
+			CMCBranch:      key.CMCMainBranch,
+			DisplaySecrets: true,
+			Ref:            cmcRef,
+		}
+		desired, err := p.Run(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to pull %s at %s.\n%w", c.CMCRepository, cmcRef, err)
+		}
+		i := pushcmc.Config{
+			Cluster:       c.Cluster,
+			Github:        c.Github,
+			CMCRepository: c.CMCRepository,
+			CMCBranch:     c.CMCBranch,
+			Input:         desired,
+			Replace:       true,
+		}
+		_, result, err := i.Run(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to push cmc.\n%w", err)
+		}
+		summary.Repositories = append(summary.Repositories, result)
+		if err := c.createPullRequest(ctx, result, c.getRepository(c.CMCRepository, c.CMCBranch), key.CMCMainBranch, cmcRef); err != nil {
+			return nil, err
+		}
+	}
+	return summary, nil
+}
+
+// checkBranches fails if a branch already exists, since the revert would be pushed on top of its state.
+func (c *Config) checkBranches(ctx context.Context) error {
+	var repositories []github.Repository
+	if !key.Skip(key.RepositoryInstallations, c.Skip) {
+		repositories = append(repositories, c.getRepository(key.RepositoryInstallations, c.InstallationsBranch))
+	}
+	if !key.Skip(key.RepositoryCMC, c.Skip) {
+		repositories = append(repositories, c.getRepository(c.CMCRepository, c.CMCBranch))
+	}
+	for _, repository := range repositories {
+		err := repository.CheckBranch(ctx)
+		if err == nil {
+			return fmt.Errorf("branch %s of %s already exists. Merge or delete it before reverting again.\n%w", repository.Branch, repository.Name, ErrBranchExists)
+		}
+		if !github.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// getRefs returns the commits of both repositories for the revision and the commit the revision was found as.
+// The CMC repository is taken from the current installations entry.
+func (c *Config) getRefs(ctx context.Context) (string, string, string, error) {
+	p := pullinstallations.Config{
+		Cluster:             c.Cluster,
+		Github:              c.Github,
+		InstallationsBranch: key.InstallationsMainBranch,
+	}
+	current, err := p.Run(ctx)
+	if err != nil {
+		return "", "", "", fmt.Errorf("failed to pull installations.\n%w", err)
+	}
+	if current.CmcRepository != "" {
+		c.CMCRepository = current.CmcRepository
+	}
+
+	installationsRepository := c.getRepository(key.RepositoryInstallations, key.InstallationsMainBranch)
+	cmcRepository := c.getRepository(c.CMCRepository, key.CMCMainBranch)
+	installationsRepository.Ref = c.To
+	commit, err := installationsRepository.GetCommit(ctx)
+	if err == nil {
+		log.Debug().Msgf("revision %s found in %s", c.To, key.RepositoryInstallations)
+		if key.Skip(key.RepositoryCMC, c.Skip) {
+			return commit.SHA, "", commit.SHA, nil
+		}
+		other, err := cmcRepository.GetCommitBefore(ctx, commit.Time)
+		if err != nil {
+			return "", "", "", err
+		}
+		return commit.SHA, other.SHA, commit.SHA, nil
+	}
+	if !github.IsNotFound(err) {
+		return "", "", "", err
+	}
+
+	cmcRepository.Ref = c.To
+	commit, err = cmcRepository.GetCommit(ctx)
+	if github.IsNotFound(err) {
+		return "", "", "", fmt.Errorf("revision %s in %s or %s\n%w", c.To, key.RepositoryInstallations, c.CMCRepository, ErrNotFound)
+	}
+	if err != nil {
+		return "", "", "", err
+	}
+	log.Debug().Msgf("revision %s found in %s", c.To, c.CMCRepository)
+	if key.Skip(key.RepositoryInstallations, c.Skip) {
+		return "", commit.SHA, commit.SHA, nil
+	}
+	installationsRepository.Ref = ""
+	other, err := installationsRepository.GetCommitBefore(ctx, commit.Time)
+	if err != nil {
+		return "", "", "", err
+	}
+	return other.SHA, commit.SHA, commit.SHA, nil
+}
+
+// createPullRequest opens a pull request for the branch unless the revision equals its current state.
+func (c *Config) createPullRequest(ctx context.Context, result *managementcluster.RepositorySummary, repository github.Repository, base string, ref string) error {
+	if !c.CreatePR || result.Status == github.StatusUnchanged {
+		return nil
+	}
+	title := fmt.Sprintf("Revert management cluster %s to %s", c.Cluster, getShortSHA(ref))
+	exists, err := repository.PullRequestExists(ctx, title)
+	if err != nil {
+		return err
+	}
+	if exists {
+		log.Debug().Msgf("pull request %q already exists", title)
+		return nil
+	}
+	return repository.CreatePullRequest(ctx, title, base)
+}
+
+func (c *Config) getRepository(name string, branch string) github.Repository {
+	return github.Repository{
+		Github:       c.Github,
+		Name:         name,
+		Organization: key.OrganizationGiantSwarm,
+		Branch:       branch,
+	}
+}
+
+func getShortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
@@ -0,0 +1,146 @@
+package revert
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/giantswarm/mcli/pkg/github"
+	"github.com/giantswarm/mcli/pkg/github/githubtest"
+	"github.com/giantswarm/mcli/pkg/key"
+)
+
+const installationsFile = `base: gigmac.gigantic.io
+codename: gigmac
+customer: giantswarm
+cmc_repository: giantswarm-management-clusters
+ccr_repository: giantswarm-customer-configs
+accountEngineer: phoenix
+pipeline: testing
+provider: capz
+`
+
+func TestRun(t *testing.T) {
+	testCases := []struct {
+		name           string
+		to             string
+		createPR       bool
+		existingBranch bool
+
+		expectedPipeline string
+		expectedStatus   string
+		expectedPRs      int
+		expectedError    error
+	}{
+		{
+			name:     "case 0: revert to initial revision",
+			to:       "initial",
+			createPR: true,
+
+			expectedPipeline: "pipeline: testing",
+			expectedStatus:   github.StatusUpdated,
+			expectedPRs:      1,
+		},
+		{
+			name: "case 1: revert to current revision",
+			to:   "current",
+
+			expectedPipeline: "pipeline: stable",
+			expectedStatus:   github.StatusUnchanged,
+		},
+		{
+			name: "case 2: unknown revision",
+			to:   "unknown",
+
+			expectedError: ErrNotFound,
+		},
+		{
+			name:           "case 3: revert branch exists",
+			to:             "initial",
+			existingBranch: true,
+
+			expectedError: ErrBranchExists,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := githubtest.NewServer()
+			defer server.Close()
+			server.AddBranchRepository(key.OrganizationGiantSwarm, key.RepositoryInstallations, key.InstallationsMainBranch, map[string]string{
+				"gigmac/cluster.yaml": installationsFile,
+			})
+			server.AddRepository(key.OrganizationGiantSwarm, "giantswarm-management-clusters", map[string]string{
+				"README.md": "cmc",
+			})
+			repository := github.Repository{
+				Github:       server.Client(),
+				Name:         key.RepositoryInstallations,
+				Organization: key.OrganizationGiantSwarm,
+				Branch:       key.InstallationsMainBranch,
+			}
+			ctx := context.Background()
+			initial, err := repository.GetCommit(ctx)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if _, err := repository.CreateFileWithMessage(ctx, []byte(strings.Replace(installationsFile, "testing", "stable", 1)), "gigmac/cluster.yaml", "move to stable"); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			current, err := repository.GetCommit(ctx)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			to := map[string]string{"initial": initial.SHA, "current": current.SHA}[tc.to]
+			if to == "" {
+				to = tc.to
+			}
+
+			branch := key.GetRevertBranch("gigmac", to)
+			if tc.existingBranch {
+				stale := repository
+				stale.Branch = branch
+				if err := stale.CreateBranch(ctx, key.InstallationsMainBranch); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+			c := Config{
+				Cluster:  "gigmac",
+				Github:   server.Client(),
+				To:       to,
+				Skip:     []string{key.RepositoryCMC},
+				CreatePR: tc.createPR,
+			}
+			summary, err := c.Run(ctx)
+			if tc.expectedError != nil {
+				if !errors.Is(err, tc.expectedError) {
+					t.Fatalf("expected error %v, got %v", tc.expectedError, err)
+				}
+				if pulls := server.PullRequests(key.OrganizationGiantSwarm, key.RepositoryInstallations); len(pulls) != 0 {
+					t.Fatalf("expected no pull requests, got %+v", pulls)
+				}
+				return
+			}
+			if c.InstallationsBranch != branch {
+				t.Fatalf("expected branch %s, got %s", branch, c.InstallationsBranch)
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if c.CMCRepository != "giantswarm-management-clusters" {
+				t.Fatalf("expected CMC repository from installations, got %s", c.CMCRepository)
+			}
+			if len(summary.Repositories) != 1 || summary.Repositories[0].Status != tc.expectedStatus {
+				t.Fatalf("expected status %s, got %+v", tc.expectedStatus, summary.Repositories)
+			}
+			files := server.Files(key.OrganizationGiantSwarm, key.RepositoryInstallations, branch)
+			if !strings.Contains(files["gigmac/cluster.yaml"], tc.expectedPipeline) {
+				t.Fatalf("expected %q on branch, got\n%s", tc.expectedPipeline, files["gigmac/cluster.yaml"])
+			}
+			if pulls := server.PullRequests(key.OrganizationGiantSwarm, key.RepositoryInstallations); len(pulls) != tc.expectedPRs {
+				t.Fatalf("expected %d pull requests, got %+v", tc.expectedPRs, pulls)
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/giantswarm/mcli/pkg/key"
+)
+
+const (
+	flagCreatePR = "create-pr"
+)
+
+var (
+	revertTo       string
+	revertCreatePR bool
+)
+
+func addFlagsRevert() {
+	revertCmd.Flags().StringVar(&revertTo, flagTo, "", "Commit of the installations or CMC repository to revert to")
+	revertCmd.Flags().BoolVar(&revertCreatePR, flagCreatePR, false, "Open pull requests for the reverted branches. (default: false)")
+	revertCmd.Flags().StringArrayVarP(&skip, flagSkip, "s", []string{}, fmt.Sprintf("List of repositories to skip. (default: none) Valid values: %s", key.GetValidRepositories()))
+}
+
+func defaultRevert() {
+	if customer == "" {
+		customer = key.OrganizationGiantSwarm
+	}
+	if cmcRepository == "" {
+		cmcRepository = key.GetCMCName(customer)
+	}
+}
+
+func validateRevert() error {
+	if revertTo == "" {
+		return invalidFlagError(flagTo)
+	}
+	return nil
+}
@@ -4,10 +4,17 @@ Copyright © 2023 NAME HERE <EMAIL ADDRESS>
 package cmd
 
 import (
+	"context"
+	"fmt"
 	"os"
 
 	log "github.com/rs/zerolog"
 	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/mcli/pkg/audit"
+	"github.com/giantswarm/mcli/pkg/github"
+	"github.com/giantswarm/mcli/pkg/managementcluster"
+	"github.com/giantswarm/mcli/pkg/project"
 )
 
 // rootCmd represents the base command when called without any subcommands
@@ -17,7 +24,11 @@ var rootCmd = &cobra.Command{
 	Long: `A CLI tool to manage Giant Swarm Management Cluster Configuration.
 Configuration is stored across multiple git repositories.
 This tool allows you to pull and push configuration for new and existing clusters.`,
-	PersistentPreRun: toggleVerbose,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		toggleVerbose(cmd, args)
+		return applyProfile(cmd)
+	},
+	Version: project.Version(),
 	// Uncomment the following line if your bare application
 	// has an action associated with it:
 	//Run: func(cmd *cobra.Command, args []string) {},
@@ -26,6 +37,7 @@ This tool allows you to pull and push configuration for new and existing cluster
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
+	registerCompletions()
 	err := rootCmd.Execute()
 	if err != nil {
 		os.Exit(1)
@@ -43,3 +55,24 @@ func toggleVerbose(cmd *cobra.Command, args []string) {
 		log.SetGlobalLevel(log.ErrorLevel)
 	}
 }
+
+// writeAudit records the changes of a mutating command.
+func writeAudit(ctx context.Context, cmd *cobra.Command, client *github.Github, cmcRepository string, summary *managementcluster.Summary) error {
+	c := audit.Config{
+		File:          auditFile,
+		CMC:           auditCMC,
+		CMCRepository: cmcRepository,
+		Github:        client,
+	}
+	if err := c.Write(ctx, cmd.CommandPath(), summary); err != nil {
+		return fmt.Errorf("failed to write audit record.\n%w", err)
+	}
+	return nil
+}
+
+func getSummary(results ...*managementcluster.RepositorySummary) *managementcluster.Summary {
+	return &managementcluster.Summary{
+		Cluster:      cluster,
+		Repositories: results,
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
+	"github.com/giantswarm/mcli/pkg/audit"
+	"github.com/giantswarm/mcli/pkg/config"
 	"github.com/giantswarm/mcli/pkg/key"
 )
 
@@ -24,6 +26,19 @@ const (
 	flagDisplaySecrets      = "display-secrets"
 )
 
+const (
+	flagAuditFile = "audit-file"
+	flagAuditCMC  = "audit-cmc"
+	envAuditFile  = "MCLI_AUDIT_FILE"
+	envAuditCMC   = "MCLI_AUDIT_CMC"
+)
+
+const (
+	flagProfile = "profile"
+	flagConfig  = "config"
+	envConfig   = "MCLI_CONFIG"
+)
+
 const (
 	envBaseDomain          = "BASE_DOMAIN"
 	envCluster             = "INSTALLATION"
@@ -48,6 +63,10 @@ var (
 	provider            string
 	input               string
 	displaySecrets      bool
+	auditFile           string
+	auditCMC            bool
+	profileName         string
+	configFile          string
 )
 
 func addFlagsRoot() {
@@ -61,6 +80,10 @@ func addFlagsRoot() {
 	rootCmd.PersistentFlags().StringVar(&cmcBranch, flagCMCBranch, viper.GetString(envCMCBranch), "Branch to use for the CMC repository")
 	rootCmd.PersistentFlags().StringVar(&customer, flagCustomer, viper.GetString(envCustomer), "Name of the customer who owns the management cluster")
 	rootCmd.PersistentFlags().BoolVar(&displaySecrets, flagDisplaySecrets, false, "Unsafe: display secrets in the output. (default: false)")
+	rootCmd.PersistentFlags().StringVar(&auditFile, flagAuditFile, getDefaultAuditFile(), "File to append audit records of changes to. Empty disables the local audit log")
+	rootCmd.PersistentFlags().StringVar(&profileName, flagProfile, viper.GetString(config.EnvProfile), "Profile of the configuration file to use. (default: profile set in the configuration file)")
+	rootCmd.PersistentFlags().StringVar(&configFile, flagConfig, getDefaultConfigFile(), "Configuration file with profiles. A .mcli.yaml file in the current directory overrides it")
+	rootCmd.PersistentFlags().BoolVar(&auditCMC, flagAuditCMC, viper.GetBool(envAuditCMC), "Also commit audit records to the CMC repository. (default: false)")
 
 	err := rootCmd.PersistentFlags().MarkHidden(flagGithubToken)
 	if err != nil {
@@ -88,3 +111,17 @@ func validateRoot(cmd *cobra.Command, args []string) error {
 	}
 	return nil
 }
+
+func getDefaultAuditFile() string {
+	if file := viper.GetString(envAuditFile); file != "" {
+		return file
+	}
+	return audit.GetDefaultFile()
+}
+
+func getDefaultConfigFile() string {
+	if file := viper.GetString(envConfig); file != "" {
+		return file
+	}
+	return config.GetDefaultFile()
+}
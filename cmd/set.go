@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/mcli/cmd/field"
+	"github.com/giantswarm/mcli/pkg/github"
+)
+
+// setCmd represents the set command
+var setCmd = &cobra.Command{
+	Use:   "set <path>=<value>...",
+	Short: "Sets fields of the configuration of a Management Cluster",
+	Long: `Sets fields of the configuration of a Management Cluster and pushes the result.
+The current state is pulled from the branches if they exist, the fields are
+applied and only the repositories of the given fields are pushed. Values are
+parsed as YAML and have to match the type of the field. For example:
+
+mcli set cmc.clusterApp.version=1.2.3 installations.pipeline=stable --cluster=gigmac
+
+mcli set cmc.mcProxy.noProxy='[a.example.com, b.example.com]' --cluster=gigmac --dry-run`,
+	Args:         cobra.MinimumNArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		defaultSet()
+		err := validateRoot(cmd, args)
+		if err != nil {
+			return err
+		}
+		ctx := context.Background()
+		client := github.New(github.Config{
+			Token: githubToken,
+		})
+		p, err := loadPolicy(ctx, client, cmcRepository)
+		if err != nil {
+			return err
+		}
+		c := field.Config{
+			Cluster:             cluster,
+			Github:              client,
+			CMCRepository:       cmcRepository,
+			InstallationsBranch: installationsBranch,
+			CMCBranch:           cmcBranch,
+			Fields:              args,
+			DryRun:              setDryRun,
+			DisplaySecrets:      displaySecrets,
+			Out:                 os.Stdout,
+			Policy:              p,
+		}
+		summary, err := c.Set(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to set fields.\n%w", err)
+		}
+		if summary == nil {
+			return nil
+		}
+		if err := summary.Print(); err != nil {
+			return err
+		}
+		return writeAudit(ctx, cmd, client, c.CMCRepository, summary)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(setCmd)
+	addFlagsSet()
+}
@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"github.com/giantswarm/mcli/pkg/key"
+)
+
+const (
+	flagDryRun = "dry-run"
+)
+
+var (
+	setDryRun bool
+)
+
+func addFlagsSet() {
+	setCmd.Flags().BoolVar(&setDryRun, flagDryRun, false, "Print the changed fields without pushing. (default: false)")
+	addFlagPolicy(setCmd)
+}
+
+func defaultSet() {
+	if installationsBranch == "" {
+		installationsBranch = key.GetDefaultPRBranch(cluster)
+	}
+	if cmcBranch == "" {
+		cmcBranch = key.GetDefaultPRBranch(cluster)
+	}
+	if customer == "" {
+		customer = key.OrganizationGiantSwarm
+	}
+	if cmcRepository == "" {
+		cmcRepository = key.GetCMCName(customer)
+	}
+}
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/mcli/cmd/pull"
+	"github.com/giantswarm/mcli/pkg/github"
+	"github.com/giantswarm/mcli/pkg/managementcluster"
+	"github.com/giantswarm/mcli/pkg/policy"
+)
+
+// validateCmd represents the validate command
+var validateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Validates the configuration of a Management Cluster against policies",
+	Long: `Validates the configuration of a Management Cluster against policy rules written in CEL.
+The configuration is read from an input file or pulled from the repositories.
+Violations are printed and the command exits with a non-zero exit code if any
+rule with severity deny is violated. For example:
+
+mcli validate --input=cluster.yaml --policy=policies/
+
+mcli validate --cluster=gigmac --policy=cmc:policies`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		defaultValidate()
+		err := validateValidate(cmd, args)
+		if err != nil {
+			return err
+		}
+		ctx := context.Background()
+		client := github.New(github.Config{
+			Token: githubToken,
+		})
+		p, err := loadPolicy(ctx, client, cmcRepository)
+		if err != nil {
+			return err
+		}
+		var mc *managementcluster.ManagementCluster
+		if input != "" {
+			mc, err = managementcluster.GetManagementClusterFromFile(input)
+		} else {
+			c := pull.Config{
+				Cluster:             cluster,
+				GithubToken:         githubToken,
+				InstallationsBranch: installationsBranch,
+				CMCBranch:           cmcBranch,
+				CMCRepository:       cmcRepository,
+				Skip:                skip,
+				DisplaySecrets:      displaySecrets,
+			}
+			mc, err = c.Pull(ctx)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read management cluster configuration.\n%w", err)
+		}
+		violations := p.Without(skip).Evaluate(mc)
+		if len(violations) == 0 {
+			fmt.Println("no policy violations")
+			return nil
+		}
+		policy.Print(os.Stdout, violations)
+		if policy.IsDenied(violations) {
+			return fmt.Errorf("configuration of management cluster violates policy.\n%w", policy.ErrDenied)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(validateCmd)
+	addFlagsValidate()
+}
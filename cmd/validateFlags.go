@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/mcli/pkg/key"
+)
+
+func addFlagsValidate() {
+	validateCmd.Flags().StringVarP(&input, flagInput, "i", "", "Input configuration file to validate. If not specified, the configuration of the cluster is pulled.")
+	validateCmd.Flags().StringArrayVarP(&skip, flagSkip, "s", []string{}, fmt.Sprintf("List of repositories to skip. (default: none) Valid values: %s", key.GetValidRepositories()))
+	addFlagPolicy(validateCmd)
+}
+
+func defaultValidate() {
+	defaultPull()
+}
+
+func validateValidate(cmd *cobra.Command, args []string) error {
+	if len(policySources) == 0 {
+		return invalidFlagError(flagPolicy)
+	}
+	if input != "" {
+		if !hasCMCPolicySource() {
+			return nil
+		}
+		if githubToken == "" {
+			return invalidFlagError(flagGithubToken)
+		}
+		if cmcRepository == "" {
+			return invalidFlagError(flagCMCRepository)
+		}
+		return nil
+	}
+	return validateRoot(cmd, args)
+}
@@ -0,0 +1,154 @@
+// Package audit records the changes made by mcli as JSON lines.
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/giantswarm/mcli/pkg/github"
+	"github.com/giantswarm/mcli/pkg/key"
+	"github.com/giantswarm/mcli/pkg/managementcluster"
+	"github.com/giantswarm/mcli/pkg/project"
+)
+
+const (
+	UnknownUser = "unknown"
+)
+
+type Record struct {
+	Time    time.Time `json:"time"`
+	User    string    `json:"user"`
+	Command string    `json:"command"`
+	Version string    `json:"version"`
+	managementcluster.Summary
+}
+
+type Config struct {
+	// File is the local file records are appended to. No local record is written if it is empty.
+	File string
+	// CMC also commits the record to the audit file of the cluster in the CMC repository.
+	CMC           bool
+	CMCRepository string
+	Github        *github.Github
+	// Now defaults to time.Now.
+	Now func() time.Time
+}
+
+// Write records the command and the changes of the summary.
+func (c *Config) Write(ctx context.Context, command string, summary *managementcluster.Summary) error {
+	record := c.NewRecord(ctx, command, summary)
+	line, err := record.Line()
+	if err != nil {
+		return err
+	}
+	if c.File != "" {
+		if err := appendFile(c.File, line); err != nil {
+			return err
+		}
+	}
+	if c.CMC {
+		if err := c.writeCMC(ctx, summary, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Config) NewRecord(ctx context.Context, command string, summary *managementcluster.Summary) *Record {
+	now := time.Now
+	if c.Now != nil {
+		now = c.Now
+	}
+	return &Record{
+		Time:    now().UTC(),
+		User:    c.getUser(ctx),
+		Command: command,
+		Version: project.Version(),
+		Summary: *summary,
+	}
+}
+
+// Line returns the record as a single line of JSON.
+func (r *Record) Line() ([]byte, error) {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal audit record.\n%w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+// writeCMC appends the record to the branch the CMC entry was changed on.
+// Records of changes that do not belong to a management cluster, e.g. creating the CMC repository, are only written locally.
+func (c *Config) writeCMC(ctx context.Context, summary *managementcluster.Summary, line []byte) error {
+	if summary.Cluster == "" {
+		log.Debug().Msg("no management cluster in summary, skipping audit record in CMC repository")
+		return nil
+	}
+	name := fmt.Sprintf("%s/%s", key.OrganizationGiantSwarm, c.CMCRepository)
+	for _, r := range summary.Repositories {
+		if r.Repository != name || r.Status == github.StatusUnchanged {
+			continue
+		}
+		repository := github.Repository{
+			Github:       c.Github,
+			Name:         c.CMCRepository,
+			Organization: key.OrganizationGiantSwarm,
+			Branch:       r.Branch,
+		}
+		file := key.GetAuditFile(summary.Cluster)
+		content, err := repository.GetFile(ctx, file)
+		if err != nil && !github.IsNotFound(err) {
+			return fmt.Errorf("failed to get %s.\n%w", file, err)
+		}
+		message := key.GetCommitMessage(fmt.Sprintf("Add audit record of management cluster %s", summary.Cluster), nil)
+		if _, err := repository.CreateFileWithMessage(ctx, append([]byte(content), line...), file, message); err != nil {
+			return fmt.Errorf("failed to write %s.\n%w", file, err)
+		}
+		return nil
+	}
+	log.Debug().Msgf("no changes in %s, skipping audit record", name)
+	return nil
+}
+
+// getUser looks up the user of the GitHub token. The record is still written if the lookup fails.
+func (c *Config) getUser(ctx context.Context) string {
+	if c.Github == nil {
+		return UnknownUser
+	}
+	user, err := c.Github.GetUser(ctx)
+	if err != nil {
+		log.Debug().Msgf("failed to get user.\n%s", err)
+		return UnknownUser
+	}
+	return user
+}
+
+func appendFile(file string, line []byte) error {
+	if err := os.MkdirAll(filepath.Dir(file), 0700); err != nil {
+		return fmt.Errorf("failed to create directory of %s.\n%w", file, err)
+	}
+	f, err := os.OpenFile(file, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600) // #nosec G304
+	if err != nil {
+		return fmt.Errorf("failed to open %s.\n%w", file, err)
+	}
+	defer f.Close()
+	if _, err := f.Write(line); err != nil {
+		return fmt.Errorf("failed to write %s.\n%w", file, err)
+	}
+	return nil
+}
+
+// GetDefaultFile returns the audit file in the home directory of the user.
+func GetDefaultFile() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".mcli", "audit.jsonl")
+}
@@ -0,0 +1,122 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/giantswarm/mcli/pkg/github"
+	"github.com/giantswarm/mcli/pkg/github/githubtest"
+	"github.com/giantswarm/mcli/pkg/key"
+	"github.com/giantswarm/mcli/pkg/managementcluster"
+)
+
+const cmcRepository = "giantswarm-management-clusters"
+
+func TestWrite(t *testing.T) {
+	testCases := []struct {
+		name   string
+		cmc    bool
+		status string
+
+		expectCMCRecords int
+	}{
+		{
+			name:   "case 0: local file only",
+			status: github.StatusUpdated,
+
+			expectCMCRecords: 0,
+		},
+		{
+			name:   "case 1: local file and cmc repository",
+			cmc:    true,
+			status: github.StatusUpdated,
+
+			expectCMCRecords: 2,
+		},
+		{
+			name:   "case 2: unchanged cmc entry",
+			cmc:    true,
+			status: github.StatusUnchanged,
+
+			expectCMCRecords: 0,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := githubtest.NewServer()
+			defer server.Close()
+			server.AddRepository(key.OrganizationGiantSwarm, cmcRepository, map[string]string{"README.md": "cmc\n"})
+			if err := server.AddBranch(key.OrganizationGiantSwarm, cmcRepository, key.GetDefaultPRBranch("gigmac"), githubtest.MainBranch); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			file := filepath.Join(t.TempDir(), "audit", "audit.jsonl")
+			c := Config{
+				File:          file,
+				CMC:           tc.cmc,
+				CMCRepository: cmcRepository,
+				Github:        server.Client(),
+				Now: func() time.Time {
+					return time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+				},
+			}
+			summary := &managementcluster.Summary{
+				Cluster: "gigmac",
+				Repositories: []*managementcluster.RepositorySummary{
+					{
+						Result: github.Result{
+							Repository: "giantswarm/" + cmcRepository,
+							Branch:     key.GetDefaultPRBranch("gigmac"),
+							Status:     tc.status,
+							CommitSHA:  "abc",
+						},
+						ChangedFields: []string{"baseDomain"},
+					},
+				},
+			}
+			for i := 0; i < 2; i++ {
+				if err := c.Write(context.Background(), "push", summary); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+
+			data, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+			if len(lines) != 2 {
+				t.Fatalf("expected 2 records, got %d", len(lines))
+			}
+			var record Record
+			if err := json.Unmarshal([]byte(lines[0]), &record); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if record.User != githubtest.User || record.Command != "push" || record.Cluster != "gigmac" || record.Version == "" {
+				t.Fatalf("unexpected record %+v", record)
+			}
+			if !record.Time.Equal(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)) {
+				t.Fatalf("unexpected time %s", record.Time)
+			}
+			if len(record.Repositories) != 1 || record.Repositories[0].CommitSHA != "abc" || record.Repositories[0].ChangedFields[0] != "baseDomain" {
+				t.Fatalf("unexpected repositories %+v", record.Repositories)
+			}
+
+			files := server.Files(key.OrganizationGiantSwarm, cmcRepository, key.GetDefaultPRBranch("gigmac"))
+			content := strings.TrimSpace(files[key.GetAuditFile("gigmac")])
+			records := 0
+			if content != "" {
+				records = len(strings.Split(content, "\n"))
+			}
+			if records != tc.expectCMCRecords {
+				t.Fatalf("expected %d records in the cmc repository, got %d", tc.expectCMCRecords, records)
+			}
+		})
+	}
+}
@@ -0,0 +1,260 @@
+// Package config reads mcli configuration files with named profiles of default settings.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	EnvProfile = "MCLI_PROFILE"
+	LocalFile  = ".mcli.yaml"
+	// ClusterPlaceholder is replaced by the cluster name in branch settings.
+	ClusterPlaceholder = "{cluster}"
+)
+
+const (
+	SourceFlag    = "flag"
+	SourceEnv     = "env"
+	SourceProfile = "profile"
+	SourceDefault = "default"
+)
+
+// annotationSource marks flags which are set from the profile.
+const annotationSource = "mcli_source"
+
+type Config struct {
+	// Profile is used if no profile is selected.
+	Profile  string             `yaml:"profile,omitempty"`
+	Profiles map[string]Profile `yaml:"profiles,omitempty"`
+}
+
+type Profile struct {
+	CMCRepository       string `yaml:"cmcRepository,omitempty"`
+	Customer            string `yaml:"customer,omitempty"`
+	Provider            string `yaml:"provider,omitempty"`
+	ClusterAppCatalog   string `yaml:"clusterAppCatalog,omitempty"`
+	ClusterAppVersion   string `yaml:"clusterAppVersion,omitempty"`
+	DefaultAppsCatalog  string `yaml:"defaultAppsCatalog,omitempty"`
+	DefaultAppsVersion  string `yaml:"defaultAppsVersion,omitempty"`
+	CMCBranch           string `yaml:"cmcBranch,omitempty"`
+	InstallationsBranch string `yaml:"installationsBranch,omitempty"`
+	SecretFolder        string `yaml:"secretFolder,omitempty"`
+}
+
+// View shows the effective settings.
+type View struct {
+	Files    []string `yaml:"files,omitempty"`
+	Profile  string   `yaml:"profile,omitempty"`
+	Profiles []string `yaml:"profiles,omitempty"`
+	Values   []Value  `yaml:"values"`
+}
+
+// Setting links a flag and its environment variable to a field of the profile.
+type Setting struct {
+	Flag    string
+	Env     string
+	Profile func(Profile) string
+}
+
+// Value is the effective value of a setting.
+type Value struct {
+	Flag   string `yaml:"flag"`
+	Value  string `yaml:"value"`
+	Source string `yaml:"source"`
+}
+
+// GetDefaultFile returns the user configuration file, usually ~/.config/mcli/config.yaml.
+func GetDefaultFile() string {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "mcli", "config.yaml")
+}
+
+// Read returns an empty configuration if the file does not exist.
+func Read(file string) (*Config, error) {
+	c := &Config{}
+	if file == "" {
+		return c, nil
+	}
+	data, err := os.ReadFile(file) // #nosec G304
+	if errors.Is(err, fs.ErrNotExist) {
+		log.Debug().Msgf("configuration file %s does not exist", file)
+		return c, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read configuration file %s.\n%w", file, err)
+	}
+	if err := yaml.Unmarshal(data, c); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal configuration file %s.\n%w", file, err)
+	}
+	return c, nil
+}
+
+// Load reads the files in order. Settings of later files override those of earlier files.
+func Load(files ...string) (*Config, error) {
+	c := &Config{
+		Profiles: map[string]Profile{},
+	}
+	for _, file := range files {
+		f, err := Read(file)
+		if err != nil {
+			return nil, err
+		}
+		c.Override(f)
+	}
+	return c, nil
+}
+
+func (c *Config) Override(override *Config) {
+	if override.Profile != "" {
+		c.Profile = override.Profile
+	}
+	if c.Profiles == nil {
+		c.Profiles = map[string]Profile{}
+	}
+	for name, profile := range override.Profiles {
+		c.Profiles[name] = c.Profiles[name].Override(profile)
+	}
+}
+
+// GetProfile returns the named profile or the default profile if name is empty.
+// An empty profile is returned if neither is set.
+func (c *Config) GetProfile(name string) (string, Profile, error) {
+	if name == "" {
+		name = c.Profile
+	}
+	if name == "" {
+		return "", Profile{}, nil
+	}
+	profile, ok := c.Profiles[name]
+	if !ok {
+		return "", Profile{}, fmt.Errorf("profile %s. Available profiles: %s.\n%w", name, strings.Join(c.GetProfileNames(), ", "), ErrProfileNotFound)
+	}
+	return name, profile, nil
+}
+
+func (c *Config) GetProfileNames() []string {
+	var names []string
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (p Profile) Override(override Profile) Profile {
+	if override.CMCRepository != "" {
+		p.CMCRepository = override.CMCRepository
+	}
+	if override.Customer != "" {
+		p.Customer = override.Customer
+	}
+	if override.Provider != "" {
+		p.Provider = override.Provider
+	}
+	if override.ClusterAppCatalog != "" {
+		p.ClusterAppCatalog = override.ClusterAppCatalog
+	}
+	if override.ClusterAppVersion != "" {
+		p.ClusterAppVersion = override.ClusterAppVersion
+	}
+	if override.DefaultAppsCatalog != "" {
+		p.DefaultAppsCatalog = override.DefaultAppsCatalog
+	}
+	if override.DefaultAppsVersion != "" {
+		p.DefaultAppsVersion = override.DefaultAppsVersion
+	}
+	if override.CMCBranch != "" {
+		p.CMCBranch = override.CMCBranch
+	}
+	if override.InstallationsBranch != "" {
+		p.InstallationsBranch = override.InstallationsBranch
+	}
+	if override.SecretFolder != "" {
+		p.SecretFolder = override.SecretFolder
+	}
+	return p
+}
+
+// Resolve returns the effective value of the setting. Flags take precedence over environment variables,
+// environment variables over the profile and the profile over the default of the flag.
+func Resolve(flags *pflag.FlagSet, setting Setting, profile Profile, cluster string) Value {
+	value := Value{
+		Flag:   setting.Flag,
+		Source: SourceDefault,
+	}
+	f := flags.Lookup(setting.Flag)
+	if f != nil && f.Changed && !isSetFromProfile(f) {
+		value.Value = f.Value.String()
+		value.Source = SourceFlag
+		return value
+	}
+	if env, ok := os.LookupEnv(setting.Env); ok && env != "" {
+		value.Value = env
+		value.Source = SourceEnv
+		return value
+	}
+	if p := setting.Profile(profile); p != "" {
+		value.Value = strings.ReplaceAll(p, ClusterPlaceholder, cluster)
+		value.Source = SourceProfile
+		return value
+	}
+	if f != nil {
+		value.Value = f.Value.String()
+	}
+	return value
+}
+
+// ResolveAll returns the effective values of the settings whose flag is defined in flags.
+func ResolveAll(flags *pflag.FlagSet, settings []Setting, profile Profile, cluster string) []Value {
+	var values []Value
+	for _, setting := range settings {
+		if flags.Lookup(setting.Flag) == nil {
+			continue
+		}
+		values = append(values, Resolve(flags, setting, profile, cluster))
+	}
+	return values
+}
+
+// Apply sets the flags which are neither set by flag nor by environment variable to the values of the profile.
+func Apply(flags *pflag.FlagSet, settings []Setting, profile Profile, cluster string) error {
+	for _, setting := range settings {
+		if flags.Lookup(setting.Flag) == nil {
+			continue
+		}
+		value := Resolve(flags, setting, profile, cluster)
+		if value.Source != SourceProfile {
+			continue
+		}
+		log.Debug().Msgf("setting %s to %s from profile", setting.Flag, value.Value)
+		if err := flags.Set(setting.Flag, value.Value); err != nil {
+			return fmt.Errorf("failed to set %s from profile.\n%w", setting.Flag, err)
+		}
+		if err := flags.SetAnnotation(setting.Flag, annotationSource, []string{SourceProfile}); err != nil {
+			return fmt.Errorf("failed to mark %s as set from profile.\n%w", setting.Flag, err)
+		}
+	}
+	return nil
+}
+
+func isSetFromProfile(f *pflag.Flag) bool {
+	source := f.Annotations[annotationSource]
+	return len(source) == 1 && source[0] == SourceProfile
+}
@@ -0,0 +1,197 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/pflag"
+)
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	user := filepath.Join(dir, "config.yaml")
+	local := filepath.Join(dir, LocalFile)
+	writeFile(t, user, `profile: acme
+profiles:
+  acme:
+    customer: acme
+    cmcRepository: acme-management-clusters
+    provider: capa
+  giantswarm:
+    customer: giantswarm
+`)
+	writeFile(t, local, `profiles:
+  acme:
+    provider: capz
+`)
+
+	c, err := Load(user, local, filepath.Join(dir, "missing.yaml"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	testCases := []struct {
+		name    string
+		profile string
+
+		expectName    string
+		expectProfile Profile
+		expectError   error
+	}{
+		{
+			name: "case 0: default profile merged with local file",
+
+			expectName: "acme",
+			expectProfile: Profile{
+				Customer:      "acme",
+				CMCRepository: "acme-management-clusters",
+				Provider:      "capz",
+			},
+		},
+		{
+			name:    "case 1: named profile",
+			profile: "giantswarm",
+
+			expectName:    "giantswarm",
+			expectProfile: Profile{Customer: "giantswarm"},
+		},
+		{
+			name:    "case 2: unknown profile",
+			profile: "unknown",
+
+			expectError: ErrProfileNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			name, profile, err := c.GetProfile(tc.profile)
+			if tc.expectError != nil {
+				if !errors.Is(err, tc.expectError) {
+					t.Fatalf("expected %v but got %v", tc.expectError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if name != tc.expectName {
+				t.Fatalf("expected profile %s, got %s", tc.expectName, name)
+			}
+			if profile != tc.expectProfile {
+				t.Fatalf("expected %+v, got %+v", tc.expectProfile, profile)
+			}
+		})
+	}
+}
+
+func TestResolve(t *testing.T) {
+	setting := Setting{
+		Flag:    "cmc-branch",
+		Env:     "MCLI_TEST_CMC_BRANCH",
+		Profile: func(p Profile) string { return p.CMCBranch },
+	}
+
+	testCases := []struct {
+		name    string
+		args    []string
+		env     string
+		profile Profile
+
+		expectValue  string
+		expectSource string
+	}{
+		{
+			name: "case 0: default",
+
+			expectValue:  "main",
+			expectSource: SourceDefault,
+		},
+		{
+			name:    "case 1: profile with cluster placeholder",
+			profile: Profile{CMCBranch: "{cluster}_acme"},
+
+			expectValue:  "gigmac_acme",
+			expectSource: SourceProfile,
+		},
+		{
+			name:    "case 2: environment variable over profile",
+			env:     "from-env",
+			profile: Profile{CMCBranch: "{cluster}_acme"},
+
+			expectValue:  "from-env",
+			expectSource: SourceEnv,
+		},
+		{
+			name:    "case 3: flag over environment variable",
+			args:    []string{"--cmc-branch=from-flag"},
+			env:     "from-env",
+			profile: Profile{CMCBranch: "{cluster}_acme"},
+
+			expectValue:  "from-flag",
+			expectSource: SourceFlag,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv(setting.Env, tc.env)
+			flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+			flags.String(setting.Flag, "main", "")
+			if err := flags.Parse(tc.args); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			value := Resolve(flags, setting, tc.profile, "gigmac")
+			if value.Value != tc.expectValue || value.Source != tc.expectSource {
+				t.Fatalf("expected %s from %s, got %s from %s", tc.expectValue, tc.expectSource, value.Value, value.Source)
+			}
+
+			if err := Apply(flags, []Setting{setting}, tc.profile, "gigmac"); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			applied := Resolve(flags, setting, tc.profile, "gigmac")
+			if applied != value {
+				t.Fatalf("expected %+v after applying the profile, got %+v", value, applied)
+			}
+			if tc.expectSource != SourceEnv {
+				if flag := flags.Lookup(setting.Flag).Value.String(); flag != tc.expectValue {
+					t.Fatalf("expected flag to be %s, got %s", tc.expectValue, flag)
+				}
+			}
+		})
+	}
+}
+
+func TestResolveAll(t *testing.T) {
+	settings := []Setting{
+		{Flag: "cmc-branch", Env: "MCLI_TEST_CMC_BRANCH", Profile: func(p Profile) string { return p.CMCBranch }},
+		{Flag: "customer", Env: "MCLI_TEST_CUSTOMER", Profile: func(p Profile) string { return p.Customer }},
+		{Flag: "provider", Env: "MCLI_TEST_PROVIDER", Profile: func(p Profile) string { return p.Provider }},
+	}
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	flags.String("cmc-branch", "main", "")
+	flags.String("provider", "capa", "")
+
+	values := ResolveAll(flags, settings, Profile{Customer: "acme", Provider: "capz"}, "gigmac")
+	expected := []Value{
+		{Flag: "cmc-branch", Value: "main", Source: SourceDefault},
+		{Flag: "provider", Value: "capz", Source: SourceProfile},
+	}
+	if len(values) != len(expected) {
+		t.Fatalf("expected %+v, got %+v", expected, values)
+	}
+	for i := range expected {
+		if values[i] != expected[i] {
+			t.Fatalf("expected %+v, got %+v", expected[i], values[i])
+		}
+	}
+}
+
+func writeFile(t *testing.T, file string, content string) {
+	if err := os.WriteFile(file, []byte(content), 0600); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,7 @@
+package config
+
+import (
+	"errors"
+)
+
+var ErrProfileNotFound = errors.New("profile not found")
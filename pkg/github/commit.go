@@ -0,0 +1,103 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v90/github"
+	"github.com/rs/zerolog/log"
+)
+
+// Commit describes a commit which changed a path of a repository.
+type Commit struct {
+	SHA string
+	// Parent is the first parent of the commit. It is empty for the initial commit.
+	Parent  string
+	Author  string
+	Message string
+	Time    time.Time
+	URL     string
+}
+
+// ListCommits returns up to limit commits of the branch which changed the path, newest first.
+// A limit of 0 returns all commits.
+func (r *Repository) ListCommits(ctx context.Context, path string, limit int) ([]Commit, error) {
+	log.Debug().Msg(fmt.Sprintf("listing commits of %s on ref %s of repository %s/%s", path, r.getRef(), r.Organization, r.Name))
+	options := &github.CommitsListOptions{
+		SHA:         r.getRef(),
+		Path:        path,
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+	var commits []Commit
+	for {
+		result, resp, err := r.Repositories.ListCommits(ctx, r.Organization, r.Name, options)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list commits of %s on ref %s of repository %s/%s.\n%w", path, r.getRef(), r.Organization, r.Name, err)
+		}
+		for _, c := range result {
+			commits = append(commits, getCommit(c))
+			if limit > 0 && len(commits) == limit {
+				return commits, nil
+			}
+		}
+		if resp.NextPage == 0 {
+			return commits, nil
+		}
+		options.Page = resp.NextPage
+	}
+}
+
+// GetCommit returns the commit of the ref.
+func (r *Repository) GetCommit(ctx context.Context) (Commit, error) {
+	log.Debug().Msg(fmt.Sprintf("getting commit %s of repository %s/%s", r.getRef(), r.Organization, r.Name))
+	c, resp, err := r.Repositories.GetCommit(ctx, r.Organization, r.Name, r.getRef(), nil)
+	if err != nil {
+		if resp != nil && (resp.StatusCode == 404 || resp.StatusCode == 422) {
+			return Commit{}, fmt.Errorf("commit %s of repository %s/%s does not exist.\n%w\n%w", r.getRef(), r.Organization, r.Name, err, ErrNotFound)
+		}
+		return Commit{}, fmt.Errorf("failed to get commit %s of repository %s/%s.\n%w", r.getRef(), r.Organization, r.Name, err)
+	}
+	return getCommit(c), nil
+}
+
+// GetCommitBefore returns the newest commit of the ref at or before the time.
+func (r *Repository) GetCommitBefore(ctx context.Context, t time.Time) (Commit, error) {
+	log.Debug().Msg(fmt.Sprintf("getting commit of ref %s of repository %s/%s before %s", r.getRef(), r.Organization, r.Name, t))
+	result, _, err := r.Repositories.ListCommits(ctx, r.Organization, r.Name, &github.CommitsListOptions{
+		SHA:         r.getRef(),
+		Until:       t,
+		ListOptions: github.ListOptions{PerPage: 1},
+	})
+	if err != nil {
+		return Commit{}, fmt.Errorf("failed to list commits of ref %s of repository %s/%s.\n%w", r.getRef(), r.Organization, r.Name, err)
+	}
+	if len(result) == 0 {
+		return Commit{}, fmt.Errorf("no commit of ref %s of repository %s/%s before %s.\n%w", r.getRef(), r.Organization, r.Name, t, ErrNotFound)
+	}
+	return getCommit(result[0]), nil
+}
+
+func getCommit(c *github.RepositoryCommit) Commit {
+	commit := Commit{
+		SHA:     c.GetSHA(),
+		Author:  c.GetAuthor().GetLogin(),
+		Message: c.GetCommit().GetMessage(),
+		Time:    c.GetCommit().GetAuthor().GetDate().Time,
+		URL:     c.GetHTMLURL(),
+	}
+	if commit.Author == "" {
+		commit.Author = c.GetCommit().GetAuthor().GetName()
+	}
+	if len(c.Parents) > 0 {
+		commit.Parent = c.Parents[0].GetSHA()
+	}
+	return commit
+}
+
+// GetSubject returns the first line of the commit message.
+func (c Commit) GetSubject() string {
+	subject, _, _ := strings.Cut(c.Message, "\n")
+	return subject
+}
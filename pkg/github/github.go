@@ -29,10 +29,14 @@ type Repository struct {
 	Name         string
 	Organization string
 	Branch       string
+	// Ref is read instead of the head of the branch, e.g. a commit SHA or tag.
+	Ref string
 }
 
 type Config struct {
 	Token string
+	// BaseURL of the API. Defaults to api.github.com. The GraphQL API is expected at BaseURL/graphql.
+	BaseURL string
 }
 
 func New(config Config) *Github {
@@ -40,14 +44,53 @@ func New(config Config) *Github {
 	src := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: config.Token})
 	httpClient := oauth2.NewClient(context.Background(), src)
 
-	client, err := github.NewClient(github.WithHTTPClient(httpClient))
+	options := []github.ClientOptionsFunc{github.WithHTTPClient(httpClient)}
+	if config.BaseURL != "" {
+		options = append(options, github.WithURLs(&config.BaseURL, &config.BaseURL))
+	}
+	client, err := github.NewClient(options...)
 	if err != nil {
 		log.Fatal().Err(err).Msg("failed to create github client")
 	}
 
+	graph := githubv4.NewClient(httpClient)
+	if config.BaseURL != "" {
+		graph = githubv4.NewEnterpriseClient(strings.TrimSuffix(config.BaseURL, "/")+"/graphql", httpClient)
+	}
+
 	return &Github{
 		Client: client,
-		Graph:  githubv4.NewClient(httpClient),
+		Graph:  graph,
+	}
+}
+
+// GetUser returns the login of the user the token belongs to.
+func (g *Github) GetUser(ctx context.Context) (string, error) {
+	user, _, err := g.Users.Get(ctx, "")
+	if err != nil {
+		return "", fmt.Errorf("failed to get authenticated user.\n%w", err)
+	}
+	return user.GetLogin(), nil
+}
+
+// ListRepositories returns the names of all repositories of the organization.
+func (g *Github) ListRepositories(ctx context.Context, organization string) ([]string, error) {
+	var names []string
+	options := &github.RepositoryListByOrgOptions{
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+	for {
+		repositories, resp, err := g.Repositories.ListByOrg(ctx, organization, options)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list repositories of organization %s.\n%w", organization, err)
+		}
+		for _, repository := range repositories {
+			names = append(names, repository.GetName())
+		}
+		if resp.NextPage == 0 {
+			return names, nil
+		}
+		options.Page = resp.NextPage
 	}
 }
 
@@ -62,6 +105,10 @@ func (r *Repository) Check(ctx context.Context) error {
 		return err
 	}
 
+	if r.Ref != "" {
+		return r.CheckRef(ctx)
+	}
+
 	// check if Branch exists
 	if err := r.CheckBranch(ctx); err != nil {
 		return err
@@ -71,9 +118,9 @@ func (r *Repository) Check(ctx context.Context) error {
 }
 
 func (r *Repository) getContents(ctx context.Context, path string) (fileContent *github.RepositoryContent, directoryContent []*github.RepositoryContent, resp *github.Response, err error) {
-	log.Debug().Msg(fmt.Sprintf("getting contents %s of branch %s of repository %s/%s", path, r.Branch, r.Organization, r.Name))
+	log.Debug().Msg(fmt.Sprintf("getting contents %s of ref %s of repository %s/%s", path, r.getRef(), r.Organization, r.Name))
 	return r.Repositories.GetContents(ctx, r.Organization, r.Name, path, &github.RepositoryContentGetOptions{
-		Ref: r.Branch,
+		Ref: r.getRef(),
 	})
 }
 
@@ -134,6 +181,26 @@ func (r *Repository) GetDirectory(ctx context.Context, path string) (map[string]
 	return files, nil
 }
 
+func (r *Repository) GetDirectoryNames(ctx context.Context, path string) ([]string, error) {
+	log.Debug().Msg(fmt.Sprintf("getting directory names in %s of branch %s of repository %s/%s", path, r.Branch, r.Organization, r.Name))
+	_, directory, resp, err := r.getContents(ctx, path)
+	if err != nil {
+		if resp != nil && resp.StatusCode == 404 {
+			return nil, fmt.Errorf("directory %s of branch %s of repository %s/%s does not exist.\n%w\n%w", path, r.Branch, r.Organization, r.Name, err, ErrNotFound)
+		} else {
+			return nil, fmt.Errorf("failed to get directory %s of branch %s of repository %s/%s.\n%w", path, r.Branch, r.Organization, r.Name, err)
+		}
+	}
+
+	var names []string
+	for _, file := range directory {
+		if file.GetType() == "dir" {
+			names = append(names, file.GetName())
+		}
+	}
+	return names, nil
+}
+
 func (r *Repository) GetStringFromFile(file *github.RepositoryContent) (string, error) {
 	//ensure file is not nil
 	if file == nil {
@@ -164,64 +231,81 @@ func (r *Repository) GetStringFromFile(file *github.RepositoryContent) (string,
 	return content, nil
 }
 
-func (r *Repository) CreateFile(ctx context.Context, content []byte, path string) error {
+func (r *Repository) CreateFile(ctx context.Context, content []byte, path string) (*Result, error) {
 	if err := r.Check(ctx); err != nil {
-		return err
+		return nil, err
 	}
 
-	return r.createFile(ctx, content, path)
+	return r.createFile(ctx, content, path, "")
 }
 
-func (r *Repository) createFile(ctx context.Context, content []byte, path string) error {
+// CreateFileWithMessage creates or updates the file with the given commit message.
+func (r *Repository) CreateFileWithMessage(ctx context.Context, content []byte, path string, message string) (*Result, error) {
+	if err := r.Check(ctx); err != nil {
+		return nil, err
+	}
+
+	return r.createFile(ctx, content, path, message)
+}
+
+func (r *Repository) createFile(ctx context.Context, content []byte, path string, message string) (*Result, error) {
+	result := r.NewResult()
 
 	// get the SHA in case the file already exists
 	fileSHA, err := r.GetFileSHA(ctx, path)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	var message string
 	if fileSHA == "" {
-		message = fmt.Sprintf("creating %s", path)
+		if message == "" {
+			message = fmt.Sprintf("creating %s", path)
+		}
 	} else {
 		// check if there are changes in the file
 		oldContents, err := r.GetFile(ctx, path)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		if oldContents == string(content) {
 			log.Debug().Msg(fmt.Sprintf("no changes in file %s of branch %s of repository %s/%s", path, r.Branch, r.Organization, r.Name))
-			return nil
+			return result, nil
 		}
 		if sops.IsEncrypted(oldContents) && !sops.IsEncrypted(string(content)) {
 			log.Debug().Msg(fmt.Sprintf("file %s of branch %s of repository %s/%s is currently encrypted. Unable to update with unencrypted content", path, r.Branch, r.Organization, r.Name))
-			return nil
+			return result, nil
 		}
 		if noChangesMade(string(content)) {
 			log.Debug().Msg(fmt.Sprintf("no changes in file %s of branch %s of repository %s/%s", path, r.Branch, r.Organization, r.Name))
-			return nil
+			return result, nil
+		}
+		if message == "" {
+			message = fmt.Sprintf("updating %s", path)
 		}
-		message = fmt.Sprintf("updating %s", path)
 	}
 
 	// create the file and the directory structure if necessary
 	log.Debug().Msg(fmt.Sprintf("creating file %s of branch %s of repository %s/%s", path, r.Branch, r.Organization, r.Name))
-	_, _, err = r.Repositories.CreateFile(ctx, r.Organization, r.Name, path, &github.RepositoryContentFileOptions{
+	response, _, err := r.Repositories.CreateFile(ctx, r.Organization, r.Name, path, &github.RepositoryContentFileOptions{
 		Message: github.String(message),
 		Content: content,
 		Branch:  github.String(r.Branch),
 		SHA:     github.String(fileSHA),
 	})
 	if err != nil {
-		return fmt.Errorf("failed to create file %s of branch %s of repository %s/%s.\n%w", path, r.Branch, r.Organization, r.Name, err)
+		return nil, fmt.Errorf("failed to create file %s of branch %s of repository %s/%s.\n%w", path, r.Branch, r.Organization, r.Name, err)
 	}
 
-	return nil
+	result.addFile(path, fileSHA == "")
+	result.CommitSHA = response.GetSHA()
+	result.CommitURL = response.GetHTMLURL()
+	return result, nil
 }
 
-func (r *Repository) CreateDirectory(ctx context.Context, content map[string]string, message string) error {
+func (r *Repository) CreateDirectory(ctx context.Context, content map[string]string, message string) (*Result, error) {
+	result := r.NewResult()
 	if err := r.Check(ctx); err != nil {
-		return err
+		return nil, err
 	}
 
 	var baseSHA string
@@ -230,7 +314,7 @@ func (r *Repository) CreateDirectory(ctx context.Context, content map[string]str
 		log.Debug().Msg(fmt.Sprintf("getting base tree of branch %s of repository %s/%s", r.Branch, r.Organization, r.Name))
 		base, _, err := r.Git.GetTree(ctx, r.Organization, r.Name, r.Branch, false)
 		if err != nil {
-			return fmt.Errorf("failed to get base tree of branch %s of repository %s/%s.\n%w", r.Branch, r.Organization, r.Name, err)
+			return nil, fmt.Errorf("failed to get base tree of branch %s of repository %s/%s.\n%w", r.Branch, r.Organization, r.Name, err)
 		}
 		baseSHA = *base.SHA
 	}
@@ -238,20 +322,100 @@ func (r *Repository) CreateDirectory(ctx context.Context, content map[string]str
 	// create the tree entries
 	var entries []*github.TreeEntry
 	for path, value := range content {
-		entry, err := r.createEntry(ctx, path, value)
+		entry, created, err := r.createEntry(ctx, path, value)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		if entry != nil {
 			entries = append(entries, entry)
+			result.addFile(path, created)
+		}
+	}
+	if len(entries) == 0 {
+		log.Debug().Msg(fmt.Sprintf("no changes in branch %s of repository %s/%s", r.Branch, r.Organization, r.Name))
+		return result, nil
+	}
+
+	commit, err := r.commitEntries(ctx, baseSHA, entries, message)
+	if err != nil {
+		return nil, err
+	}
+	result.CommitSHA = commit.GetSHA()
+	result.CommitURL = commit.GetHTMLURL()
+	return result, nil
+}
+
+// DeleteFiles removes the files from the branch in a single commit. Files that do not exist are skipped.
+func (r *Repository) DeleteFiles(ctx context.Context, paths []string, message string) (*Result, error) {
+	result := r.NewResult()
+	if err := r.Check(ctx); err != nil {
+		return nil, err
+	}
+
+	log.Debug().Msg(fmt.Sprintf("getting base tree of branch %s of repository %s/%s", r.Branch, r.Organization, r.Name))
+	base, _, err := r.Git.GetTree(ctx, r.Organization, r.Name, r.Branch, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get base tree of branch %s of repository %s/%s.\n%w", r.Branch, r.Organization, r.Name, err)
+	}
+
+	var entries []*github.TreeEntry
+	for _, path := range paths {
+		exists, err := r.FileExists(ctx, path)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			log.Debug().Msg(fmt.Sprintf("file %s of branch %s of repository %s/%s is already deleted", path, r.Branch, r.Organization, r.Name))
+			continue
+		}
+		// entries without SHA and content delete the file
+		entries = append(entries, &github.TreeEntry{
+			Path: github.String(path),
+			Mode: github.String("100644"),
+			Type: github.String("blob"),
+		})
+		result.addFile(path, false)
+	}
+	if len(entries) == 0 {
+		log.Debug().Msg(fmt.Sprintf("no changes in branch %s of repository %s/%s", r.Branch, r.Organization, r.Name))
+		return result, nil
+	}
+
+	commit, err := r.commitEntries(ctx, base.GetSHA(), entries, message)
+	if err != nil {
+		return nil, err
+	}
+	result.CommitSHA = commit.GetSHA()
+	result.CommitURL = commit.GetHTMLURL()
+	return result, nil
+}
+
+// GetFileNames returns the paths of all files of the branch.
+func (r *Repository) GetFileNames(ctx context.Context) ([]string, error) {
+	log.Debug().Msg(fmt.Sprintf("getting file names of branch %s of repository %s/%s", r.Branch, r.Organization, r.Name))
+	tree, _, err := r.Git.GetTree(ctx, r.Organization, r.Name, r.Branch, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tree of branch %s of repository %s/%s.\n%w", r.Branch, r.Organization, r.Name, err)
+	}
+	if tree.GetTruncated() {
+		return nil, fmt.Errorf("tree of branch %s of repository %s/%s is truncated.\n%w", r.Branch, r.Organization, r.Name, ErrInvalidFormat)
+	}
+	var names []string
+	for _, entry := range tree.Entries {
+		if entry.GetType() == "blob" {
+			names = append(names, entry.GetPath())
 		}
 	}
+	return names, nil
+}
 
+// commitEntries commits the tree entries on top of the base and moves the branch to the commit.
+func (r *Repository) commitEntries(ctx context.Context, baseSHA string, entries []*github.TreeEntry, message string) (*github.Commit, error) {
 	// create the tree
 	log.Debug().Msg(fmt.Sprintf("creating tree of branch %s of repository %s/%s", r.Branch, r.Organization, r.Name))
 	tree, _, err := r.Git.CreateTree(ctx, r.Organization, r.Name, baseSHA, entries)
 	if err != nil {
-		return fmt.Errorf("failed to create tree of branch %s of repository %s/%s.\n%w", r.Branch, r.Organization, r.Name, err)
+		return nil, fmt.Errorf("failed to create tree of branch %s of repository %s/%s.\n%w", r.Branch, r.Organization, r.Name, err)
 	}
 
 	// create the commit
@@ -262,7 +426,7 @@ func (r *Repository) CreateDirectory(ctx context.Context, content map[string]str
 		Parents: []*github.Commit{{SHA: &baseSHA}},
 	}, &github.CreateCommitOptions{})
 	if err != nil {
-		return fmt.Errorf("failed to create commit of branch %s of repository %s/%s.\n%w", r.Branch, r.Organization, r.Name, err)
+		return nil, fmt.Errorf("failed to create commit of branch %s of repository %s/%s.\n%w", r.Branch, r.Organization, r.Name, err)
 	}
 
 	// update the branch
@@ -272,37 +436,39 @@ func (r *Repository) CreateDirectory(ctx context.Context, content map[string]str
 		Force: github.Bool(false),
 	})
 	if err != nil {
-		return fmt.Errorf("failed to update branch %s of repository %s/%s.\n%w", r.Branch, r.Organization, r.Name, err)
+		return nil, fmt.Errorf("failed to update branch %s of repository %s/%s.\n%w", r.Branch, r.Organization, r.Name, err)
 	}
-	return nil
+	return commit, nil
 }
 
-func (r *Repository) createEntry(ctx context.Context, path string, content string) (*github.TreeEntry, error) {
+// createEntry returns the tree entry for the file and whether the file is new.
+// No entry is returned if the file is unchanged.
+func (r *Repository) createEntry(ctx context.Context, path string, content string) (*github.TreeEntry, bool, error) {
 	log.Debug().Msg(fmt.Sprintf("creating entry %s of branch %s of repository %s/%s", path, r.Branch, r.Organization, r.Name))
 
 	// get the SHA in case the file already exists
 	fileSHA, err := r.GetFileSHA(ctx, path)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 
 	if fileSHA != "" {
 		// check if there are changes in the file
 		oldContents, err := r.GetFile(ctx, path)
 		if err != nil {
-			return nil, err
+			return nil, false, err
 		}
 		if oldContents == string(content) {
 			log.Debug().Msg(fmt.Sprintf("no changes in file %s of branch %s of repository %s/%s", path, r.Branch, r.Organization, r.Name))
-			return nil, nil
+			return nil, false, nil
 		}
 		if sops.IsEncrypted(oldContents) && !sops.IsEncrypted(string(content)) {
 			log.Debug().Msg(fmt.Sprintf("file %s of branch %s of repository %s/%s is currently encrypted. Unable to update with unencrypted content", path, r.Branch, r.Organization, r.Name))
-			return nil, nil
+			return nil, false, nil
 		}
 		if noChangesMade(string(content)) {
 			log.Debug().Msg(fmt.Sprintf("no changes in file %s of branch %s of repository %s/%s", path, r.Branch, r.Organization, r.Name))
-			return nil, nil
+			return nil, false, nil
 		}
 	}
 
@@ -313,7 +479,7 @@ func (r *Repository) createEntry(ctx context.Context, path string, content strin
 		Mode:    github.String("100644"),
 		Type:    github.String("blob"),
 		Content: github.String(content),
-	}, nil
+	}, fileSHA == "", nil
 }
 
 func (r *Repository) CheckOrganization(ctx context.Context) error {
@@ -358,6 +524,27 @@ func (r *Repository) CheckBranch(ctx context.Context) error {
 	return nil
 }
 
+// CheckRef checks if the ref of the repository exists.
+func (r *Repository) CheckRef(ctx context.Context) error {
+	log.Debug().Msg(fmt.Sprintf("checking if ref %s of repository %s/%s exists", r.Ref, r.Organization, r.Name))
+	_, resp, err := r.Repositories.GetCommit(ctx, r.Organization, r.Name, r.Ref, nil)
+	if err != nil {
+		if resp != nil && (resp.StatusCode == 404 || resp.StatusCode == 422) {
+			return fmt.Errorf("ref %s of repository %s/%s does not exist.\n%w\n%w", r.Ref, r.Organization, r.Name, err, ErrNotFound)
+		} else {
+			return fmt.Errorf("failed to get ref %s of repository %s/%s.\n%w", r.Ref, r.Organization, r.Name, err)
+		}
+	}
+	return nil
+}
+
+func (r *Repository) getRef() string {
+	if r.Ref != "" {
+		return r.Ref
+	}
+	return r.Branch
+}
+
 func (r *Repository) CreateBranch(ctx context.Context, mainbranch string) error {
 	// get main branch sha
 	log.Debug().Msg(fmt.Sprintf("getting sha of %s branch of repository %s/%s", mainbranch, r.Organization, r.Name))
@@ -383,7 +570,7 @@ func (r *Repository) GetFileSHA(ctx context.Context, path string) (string, error
 	{
 		log.Debug().Msg(fmt.Sprintf("getting sha of file %s of branch %s of repository %s/%s", path, r.Branch, r.Organization, r.Name))
 		file, _, resp, err := r.Repositories.GetContents(ctx, r.Organization, r.Name, path, &github.RepositoryContentGetOptions{
-			Ref: r.Branch,
+			Ref: r.getRef(),
 		})
 		if err != nil {
 			if resp.StatusCode == 404 {
@@ -403,7 +590,7 @@ func (r *Repository) GetFileSHA(ctx context.Context, path string) (string, error
 func (r *Repository) FileExists(ctx context.Context, path string) (bool, error) {
 	log.Debug().Msg(fmt.Sprintf("checking if file %s exists in branch %s of repository %s/%s", path, r.Branch, r.Organization, r.Name))
 	_, _, resp, err := r.Repositories.GetContents(ctx, r.Organization, r.Name, path, &github.RepositoryContentGetOptions{
-		Ref: r.Branch,
+		Ref: r.getRef(),
 	})
 	if err != nil {
 		if resp.StatusCode == 404 {
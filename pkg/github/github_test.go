@@ -0,0 +1,272 @@
+package github_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/giantswarm/mcli/pkg/github"
+	"github.com/giantswarm/mcli/pkg/github/githubtest"
+)
+
+func TestCreateDirectory(t *testing.T) {
+	var testCases = []struct {
+		name    string
+		files   map[string]string
+		content map[string]string
+
+		expectStatus string
+		expectFiles  []string
+	}{
+		{
+			name:         "case 0: new files",
+			files:        map[string]string{"README.md": "readme"},
+			content:      map[string]string{"a/b.yaml": "b", "a/c.yaml": "c"},
+			expectStatus: github.StatusCreated,
+			expectFiles:  []string{"a/b.yaml", "a/c.yaml"},
+		},
+		{
+			name:         "case 1: changed and new files",
+			files:        map[string]string{"a/b.yaml": "b"},
+			content:      map[string]string{"a/b.yaml": "changed", "a/c.yaml": "c"},
+			expectStatus: github.StatusUpdated,
+			expectFiles:  []string{"a/b.yaml", "a/c.yaml"},
+		},
+		{
+			name:         "case 2: unchanged files",
+			files:        map[string]string{"a/b.yaml": "b"},
+			content:      map[string]string{"a/b.yaml": "b"},
+			expectStatus: github.StatusUnchanged,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := githubtest.NewServer()
+			defer server.Close()
+			server.AddRepository("giantswarm", "test", tc.files)
+
+			repository := github.Repository{
+				Github:       server.Client(),
+				Name:         "test",
+				Organization: "giantswarm",
+				Branch:       githubtest.MainBranch,
+			}
+			result, err := repository.CreateDirectory(context.Background(), tc.content, "test commit")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Status != tc.expectStatus {
+				t.Fatalf("expected status %s, got %s", tc.expectStatus, result.Status)
+			}
+			if !reflect.DeepEqual(result.Files, tc.expectFiles) {
+				t.Fatalf("expected files %v, got %v", tc.expectFiles, result.Files)
+			}
+
+			commits := server.Commits("giantswarm", "test", githubtest.MainBranch)
+			if tc.expectStatus == github.StatusUnchanged {
+				if len(commits) != 1 || result.CommitSHA != "" {
+					t.Fatalf("expected no commit, got %v", commits)
+				}
+				return
+			}
+			if len(commits) != 2 || commits[0] != "test commit" || result.CommitSHA == "" {
+				t.Fatalf("expected one new commit, got %v", commits)
+			}
+			files := server.Files("giantswarm", "test", githubtest.MainBranch)
+			for path, content := range tc.content {
+				if files[path] != content {
+					t.Fatalf("expected %s to be %q, got %q", path, content, files[path])
+				}
+			}
+			directory, err := repository.GetDirectory(context.Background(), "a")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(directory, tc.content) {
+				t.Fatalf("expected directory %v, got %v", tc.content, directory)
+			}
+		})
+	}
+}
+
+func TestCreateFile(t *testing.T) {
+	server := githubtest.NewServer()
+	defer server.Close()
+	server.AddRepository("giantswarm", "test", map[string]string{"a.yaml": "a"})
+
+	repository := github.Repository{
+		Github:       server.Client(),
+		Name:         "test",
+		Organization: "giantswarm",
+		Branch:       "test-branch",
+	}
+	ctx := context.Background()
+	if err := repository.CreateBranch(ctx, githubtest.MainBranch); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, step := range []struct {
+		path    string
+		content string
+
+		expectStatus string
+	}{
+		{path: "a.yaml", content: "a", expectStatus: github.StatusUnchanged},
+		{path: "a.yaml", content: "changed", expectStatus: github.StatusUpdated},
+		{path: "b.yaml", content: "b", expectStatus: github.StatusCreated},
+	} {
+		result, err := repository.CreateFile(ctx, []byte(step.content), step.path)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Status != step.expectStatus {
+			t.Fatalf("expected status %s for %s, got %s", step.expectStatus, step.path, result.Status)
+		}
+		content, err := repository.GetFile(ctx, step.path)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if content != step.content {
+			t.Fatalf("expected %q, got %q", step.content, content)
+		}
+	}
+	if files := server.Files("giantswarm", "test", githubtest.MainBranch); files["a.yaml"] != "a" {
+		t.Fatalf("expected main branch to be unchanged, got %v", files)
+	}
+}
+
+func TestDeleteFiles(t *testing.T) {
+	server := githubtest.NewServer()
+	defer server.Close()
+	server.AddRepository("giantswarm", "test", map[string]string{"a/b.yaml": "b", "a/c.yaml": "c", "d.yaml": "d"})
+
+	repository := github.Repository{
+		Github:       server.Client(),
+		Name:         "test",
+		Organization: "giantswarm",
+		Branch:       githubtest.MainBranch,
+	}
+	ctx := context.Background()
+	names, err := repository.GetFileNames(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := []string{"a/b.yaml", "a/c.yaml", "d.yaml"}; !reflect.DeepEqual(names, expected) {
+		t.Fatalf("expected file names %v, got %v", expected, names)
+	}
+
+	result, err := repository.DeleteFiles(ctx, []string{"a/b.yaml", "a/c.yaml", "missing.yaml"}, "delete a")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Status != github.StatusUpdated || !reflect.DeepEqual(result.Files, []string{"a/b.yaml", "a/c.yaml"}) {
+		t.Fatalf("unexpected result %v", result)
+	}
+	if files := server.Files("giantswarm", "test", githubtest.MainBranch); !reflect.DeepEqual(files, map[string]string{"d.yaml": "d"}) {
+		t.Fatalf("expected only d.yaml to be left, got %v", files)
+	}
+
+	result, err = repository.DeleteFiles(ctx, []string{"a/b.yaml"}, "delete a again")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Status != github.StatusUnchanged || len(server.Commits("giantswarm", "test", githubtest.MainBranch)) != 2 {
+		t.Fatalf("expected no new commit, got %v", result)
+	}
+}
+
+func TestListCommits(t *testing.T) {
+	server := githubtest.NewServer()
+	defer server.Close()
+	server.AddRepository("giantswarm", "test", map[string]string{"a/b.yaml": "b", "d.yaml": "d"})
+
+	repository := github.Repository{
+		Github:       server.Client(),
+		Name:         "test",
+		Organization: "giantswarm",
+		Branch:       githubtest.MainBranch,
+	}
+	ctx := context.Background()
+	for _, content := range []map[string]string{{"a/b.yaml": "b2"}, {"d.yaml": "d2"}, {"a/c.yaml": "c"}} {
+		if _, err := repository.CreateDirectory(ctx, content, "update\n\nbody"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	commits, err := repository.ListCommits(ctx, "a", 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(commits) != 3 {
+		t.Fatalf("expected 3 commits changing a, got %v", commits)
+	}
+	if commits[0].GetSubject() != "update" || commits[0].Author != githubtest.User || !commits[0].Time.After(commits[1].Time) {
+		t.Fatalf("unexpected newest commit %v", commits[0])
+	}
+	if commits[2].Parent != "" {
+		t.Fatalf("expected initial commit without parent, got %s", commits[2].Parent)
+	}
+
+	commits, err = repository.ListCommits(ctx, "a", 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(commits) != 1 {
+		t.Fatalf("expected limit of 1 commit, got %v", commits)
+	}
+
+	old := repository
+	old.Ref = commits[0].Parent
+	if err := old.Check(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	content, err := old.GetFile(ctx, "d.yaml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if content != "d2" {
+		t.Fatalf("expected d.yaml at parent to be d2, got %s", content)
+	}
+	if _, err := old.GetFile(ctx, "a/c.yaml"); !github.IsNotFound(err) {
+		t.Fatalf("expected a/c.yaml not to exist at parent, got %v", err)
+	}
+
+	old.Ref = "unknown"
+	if err := old.Check(ctx); !github.IsNotFound(err) {
+		t.Fatalf("expected unknown ref not to be found, got %v", err)
+	}
+}
+
+func TestGetCommitBefore(t *testing.T) {
+	server := githubtest.NewServer()
+	defer server.Close()
+	server.AddRepository("giantswarm", "test", map[string]string{"a.yaml": "a"})
+
+	repository := github.Repository{
+		Github:       server.Client(),
+		Name:         "test",
+		Organization: "giantswarm",
+		Branch:       githubtest.MainBranch,
+	}
+	ctx := context.Background()
+	initial, err := repository.GetCommit(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := repository.CreateFile(ctx, []byte("b"), "b.yaml"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	commit, err := repository.GetCommitBefore(ctx, initial.Time)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if commit.SHA != initial.SHA {
+		t.Fatalf("expected initial commit %s, got %s", initial.SHA, commit.SHA)
+	}
+	if _, err := repository.GetCommitBefore(ctx, initial.Time.Add(-time.Minute)); !github.IsNotFound(err) {
+		t.Fatalf("expected no commit before the initial one, got %v", err)
+	}
+}
@@ -0,0 +1,920 @@
+// Package githubtest provides an in-memory stand-in for the GitHub API to test
+// commands end-to-end without access to api.github.com.
+package githubtest
+
+import (
+	"crypto/sha1" // #nosec G505 -- used to mimic git object ids
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/v90/github"
+
+	mcligithub "github.com/giantswarm/mcli/pkg/github"
+)
+
+const (
+	MainBranch = "main"
+	// User is the login of the authenticated user.
+	User = "mcli-test"
+)
+
+type Server struct {
+	*httptest.Server
+
+	mu            sync.Mutex
+	organizations map[string]bool
+	repositories  map[string]*repository
+	counter       int
+}
+
+type PullRequest struct {
+	Number int
+	Title  string
+	Head   string
+	Base   string
+}
+
+type repository struct {
+	organization string
+	name         string
+	branches     map[string]string
+	commits      map[string]*commit
+	trees        map[string]map[string]string
+	teams        map[string]string
+	pulls        []PullRequest
+	protections  []string
+}
+
+type commit struct {
+	message string
+	tree    string
+	parents []string
+	time    time.Time
+}
+
+// NewServer starts a fake GitHub API. It has to be closed after use.
+func NewServer() *Server {
+	s := &Server{
+		organizations: map[string]bool{},
+		repositories:  map[string]*repository{},
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+// Client returns a client that talks to the fake server.
+func (s *Server) Client() *mcligithub.Github {
+	return mcligithub.New(mcligithub.Config{
+		Token:   "token",
+		BaseURL: s.URL + "/",
+	})
+}
+
+// AddRepository creates a repository with the files on its main branch.
+// The organization is created if necessary.
+func (s *Server) AddRepository(organization string, name string, files map[string]string) {
+	s.AddBranchRepository(organization, name, MainBranch, files)
+}
+
+// AddBranchRepository creates a repository with the files on the given default branch.
+func (s *Server) AddBranchRepository(organization string, name string, branch string, files map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.organizations[organization] = true
+	r := &repository{
+		organization: organization,
+		name:         name,
+		branches:     map[string]string{},
+		commits:      map[string]*commit{},
+		trees:        map[string]map[string]string{},
+		teams:        map[string]string{},
+	}
+	s.repositories[fmt.Sprintf("%s/%s", organization, name)] = r
+	r.branches[branch] = s.commit(r, "initial commit", copyFiles(files), nil)
+}
+
+// AddBranch creates a branch from the head of another branch.
+func (s *Server) AddBranch(organization string, name string, branch string, from string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r, ok := s.repositories[fmt.Sprintf("%s/%s", organization, name)]
+	if !ok {
+		return fmt.Errorf("repository %s/%s does not exist", organization, name)
+	}
+	head, ok := r.branches[from]
+	if !ok {
+		return fmt.Errorf("branch %s of repository %s/%s does not exist", from, organization, name)
+	}
+	r.branches[branch] = head
+	return nil
+}
+
+// Files returns the files on a branch. It returns nil if the branch does not exist.
+func (s *Server) Files(organization string, name string, branch string) map[string]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r, ok := s.repositories[fmt.Sprintf("%s/%s", organization, name)]
+	if !ok {
+		return nil
+	}
+	head, ok := r.branches[branch]
+	if !ok {
+		return nil
+	}
+	return copyFiles(r.trees[r.commits[head].tree])
+}
+
+// Commits returns the commit messages on a branch, newest first.
+func (s *Server) Commits(organization string, name string, branch string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r, ok := s.repositories[fmt.Sprintf("%s/%s", organization, name)]
+	if !ok {
+		return nil
+	}
+	var messages []string
+	for sha := r.branches[branch]; sha != ""; {
+		c := r.commits[sha]
+		messages = append(messages, c.message)
+		if len(c.parents) == 0 {
+			break
+		}
+		sha = c.parents[0]
+	}
+	return messages
+}
+
+// PullRequests returns the pull requests of a repository.
+func (s *Server) PullRequests(organization string, name string) []PullRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r, ok := s.repositories[fmt.Sprintf("%s/%s", organization, name)]
+	if !ok {
+		return nil
+	}
+	return append([]PullRequest{}, r.pulls...)
+}
+
+// Teams returns the permission of each team on a repository.
+func (s *Server) Teams(organization string, name string) map[string]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r, ok := s.repositories[fmt.Sprintf("%s/%s", organization, name)]
+	if !ok {
+		return nil
+	}
+	teams := map[string]string{}
+	for k, v := range r.teams {
+		teams[k] = v
+	}
+	return teams
+}
+
+// BranchProtections returns the protected branch patterns of a repository.
+func (s *Server) BranchProtections(organization string, name string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r, ok := s.repositories[fmt.Sprintf("%s/%s", organization, name)]
+	if !ok {
+		return nil
+	}
+	return append([]string{}, r.protections...)
+}
+
+func (s *Server) handle(w http.ResponseWriter, req *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	segments, err := getSegments(req.URL.EscapedPath())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	// GitHub Enterprise clients prefix requests with api/v3
+	if len(segments) > 2 && segments[0] == "api" && segments[1] == "v3" {
+		segments = segments[2:]
+	}
+
+	switch {
+	case len(segments) == 1 && segments[0] == "graphql":
+		s.handleGraphQL(w, req)
+	case len(segments) == 1 && segments[0] == "user" && req.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, github.User{Login: github.Ptr(User)})
+	case len(segments) == 2 && segments[0] == "orgs" && req.Method == http.MethodGet:
+		if !s.organizations[segments[1]] {
+			writeError(w, http.StatusNotFound, "Not Found")
+			return
+		}
+		writeJSON(w, http.StatusOK, github.Organization{Login: github.Ptr(segments[1])})
+	case len(segments) == 3 && segments[0] == "orgs" && segments[2] == "repos" && req.Method == http.MethodGet:
+		s.handleListRepositories(w, segments[1])
+	case len(segments) == 7 && segments[0] == "orgs" && segments[2] == "teams" && segments[4] == "repos":
+		s.handleTeam(w, req, segments[3], segments[5], segments[6])
+	case len(segments) >= 3 && segments[0] == "repos":
+		s.handleRepository(w, req, segments[1], segments[2], segments[3:])
+	default:
+		writeError(w, http.StatusNotFound, "Not Found")
+	}
+}
+
+// handleListRepositories returns all repositories of the organization on a single page.
+func (s *Server) handleListRepositories(w http.ResponseWriter, organization string) {
+	if !s.organizations[organization] {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	var names []string
+	for _, r := range s.repositories {
+		if r.organization == organization {
+			names = append(names, r.name)
+		}
+	}
+	sort.Strings(names)
+	repositories := []github.Repository{}
+	for _, name := range names {
+		repositories = append(repositories, github.Repository{Name: github.Ptr(name)})
+	}
+	writeJSON(w, http.StatusOK, repositories)
+}
+
+func (s *Server) handleRepository(w http.ResponseWriter, req *http.Request, organization string, name string, segments []string) {
+	r, ok := s.repositories[fmt.Sprintf("%s/%s", organization, name)]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	switch {
+	case len(segments) == 0 && req.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, github.Repository{
+			Name:     github.Ptr(name),
+			FullName: github.Ptr(fmt.Sprintf("%s/%s", organization, name)),
+			Private:  github.Ptr(true),
+		})
+	case len(segments) == 1 && segments[0] == "generate" && req.Method == http.MethodPost:
+		s.handleGenerate(w, req, r)
+	case len(segments) == 2 && segments[0] == "branches" && req.Method == http.MethodGet:
+		head, ok := r.branches[segments[1]]
+		if !ok {
+			writeError(w, http.StatusNotFound, "Branch not found")
+			return
+		}
+		writeJSON(w, http.StatusOK, github.Branch{
+			Name:   github.Ptr(segments[1]),
+			Commit: &github.RepositoryCommit{SHA: github.Ptr(head)},
+		})
+	case len(segments) >= 1 && segments[0] == "contents":
+		s.handleContents(w, req, r, strings.Join(segments[1:], "/"))
+	case len(segments) == 3 && segments[0] == "git" && segments[1] == "trees" && req.Method == http.MethodGet:
+		s.handleGetTree(w, req, r, segments[2])
+	case len(segments) == 2 && segments[0] == "git" && segments[1] == "trees" && req.Method == http.MethodPost:
+		s.handleCreateTree(w, req, r)
+	case len(segments) == 2 && segments[0] == "git" && segments[1] == "commits" && req.Method == http.MethodPost:
+		s.handleCreateCommit(w, req, r)
+	case len(segments) == 2 && segments[0] == "git" && segments[1] == "refs" && req.Method == http.MethodPost:
+		s.handleCreateRef(w, req, r)
+	case len(segments) >= 4 && segments[0] == "git" && segments[1] == "refs" && segments[2] == "heads" && req.Method == http.MethodPatch:
+		s.handleUpdateRef(w, req, r, strings.Join(segments[3:], "/"))
+	case len(segments) == 1 && segments[0] == "commits" && req.Method == http.MethodGet:
+		s.handleListCommits(w, req, r)
+	case len(segments) == 2 && segments[0] == "commits" && req.Method == http.MethodGet:
+		sha := segments[1]
+		if head, ok := r.branches[sha]; ok {
+			sha = head
+		}
+		if _, ok := r.commits[sha]; !ok {
+			writeError(w, http.StatusUnprocessableEntity, "No commit found for SHA")
+			return
+		}
+		writeJSON(w, http.StatusOK, s.getRepositoryCommit(r, sha))
+	case len(segments) == 1 && segments[0] == "pulls":
+		s.handlePulls(w, req, r)
+	default:
+		writeError(w, http.StatusNotFound, "Not Found")
+	}
+}
+
+func (s *Server) handleContents(w http.ResponseWriter, req *http.Request, r *repository, path string) {
+	switch req.Method {
+	case http.MethodGet:
+		ref := req.URL.Query().Get("ref")
+		if ref == "" {
+			ref = MainBranch
+		}
+		files, ok := s.getFiles(r, ref)
+		if !ok {
+			writeError(w, http.StatusNotFound, "No commit found for the ref")
+			return
+		}
+		if content, ok := files[path]; ok {
+			writeJSON(w, http.StatusOK, getFileContent(path, content))
+			return
+		}
+		directory := getDirectoryContent(files, path)
+		if len(directory) == 0 {
+			writeError(w, http.StatusNotFound, "Not Found")
+			return
+		}
+		writeJSON(w, http.StatusOK, directory)
+	case http.MethodPut:
+		var body github.RepositoryContentFileOptions
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		branch := body.GetBranch()
+		if branch == "" {
+			branch = MainBranch
+		}
+		head, ok := r.branches[branch]
+		if !ok {
+			writeError(w, http.StatusNotFound, "Branch not found")
+			return
+		}
+		files := copyFiles(r.trees[r.commits[head].tree])
+		current, exists := files[path]
+		if exists && body.GetSHA() != getBlobSHA(current) {
+			writeError(w, http.StatusConflict, fmt.Sprintf("%s does not match", body.GetSHA()))
+			return
+		}
+		if !exists && body.GetSHA() != "" {
+			writeError(w, http.StatusUnprocessableEntity, "sha provided for a file that does not exist")
+			return
+		}
+		files[path] = string(body.Content)
+		sha := s.commit(r, body.GetMessage(), files, []string{head})
+		r.branches[branch] = sha
+		status := http.StatusOK
+		if !exists {
+			status = http.StatusCreated
+		}
+		writeJSON(w, status, github.RepositoryContentResponse{
+			Content: getFileContent(path, string(body.Content)),
+			Commit:  s.getCommit(r, sha),
+		})
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "Method Not Allowed")
+	}
+}
+
+// handleListCommits lists the first parent history of a ref, newest first, on a single page.
+// With a path only commits which changed files below the path are listed, with until only commits up to the time.
+func (s *Server) handleListCommits(w http.ResponseWriter, req *http.Request, r *repository) {
+	sha := req.URL.Query().Get("sha")
+	if sha == "" {
+		sha = MainBranch
+	}
+	if head, ok := r.branches[sha]; ok {
+		sha = head
+	}
+	if _, ok := r.commits[sha]; !ok {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	path := req.URL.Query().Get("path")
+	var until time.Time
+	if value := req.URL.Query().Get("until"); value != "" {
+		var err error
+		until, err = time.Parse(time.RFC3339, value)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	commits := []github.RepositoryCommit{}
+	for sha != "" {
+		c := r.commits[sha]
+		var parent string
+		if len(c.parents) > 0 {
+			parent = c.parents[0]
+		}
+		if !until.IsZero() && c.time.After(until) {
+			sha = parent
+			continue
+		}
+		if path == "" || !equalFiles(r.trees[c.tree], s.getParentFiles(r, parent), path) {
+			commits = append(commits, s.getRepositoryCommit(r, sha))
+		}
+		sha = parent
+	}
+	writeJSON(w, http.StatusOK, commits)
+}
+
+func (s *Server) getParentFiles(r *repository, parent string) map[string]string {
+	if parent == "" {
+		return nil
+	}
+	return r.trees[r.commits[parent].tree]
+}
+
+func (s *Server) getRepositoryCommit(r *repository, sha string) github.RepositoryCommit {
+	c := r.commits[sha]
+	commit := s.getCommit(r, sha)
+	commit.Author = &github.CommitAuthor{
+		Name: github.Ptr(User),
+		Date: &github.Timestamp{Time: c.time},
+	}
+	return github.RepositoryCommit{
+		SHA:     github.Ptr(sha),
+		Commit:  &commit,
+		Author:  &github.User{Login: github.Ptr(User)},
+		Parents: commit.Parents,
+		HTMLURL: commit.HTMLURL,
+	}
+}
+
+// equalFiles compares the files below the path.
+func equalFiles(a map[string]string, b map[string]string, path string) bool {
+	below := func(file string) bool {
+		return file == path || strings.HasPrefix(file, path+"/")
+	}
+	for file, content := range a {
+		if below(file) && b[file] != content {
+			return false
+		}
+	}
+	for file := range b {
+		if _, ok := a[file]; below(file) && !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// handleGetTree resolves branches to their head commit. mcli uses the returned SHA both as base tree and as parent commit.
+// The files are only listed for recursive requests.
+func (s *Server) handleGetTree(w http.ResponseWriter, req *http.Request, r *repository, ref string) {
+	sha := ref
+	if head, ok := r.branches[ref]; ok {
+		sha = head
+	}
+	files, ok := s.getTree(r, sha)
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	tree := github.Tree{SHA: github.Ptr(sha)}
+	if req.URL.Query().Get("recursive") != "" {
+		var paths []string
+		for path := range files {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+		for _, path := range paths {
+			tree.Entries = append(tree.Entries, &github.TreeEntry{
+				Path: github.Ptr(path),
+				Type: github.Ptr("blob"),
+				SHA:  github.Ptr(getBlobSHA(files[path])),
+			})
+		}
+	}
+	writeJSON(w, http.StatusOK, tree)
+}
+
+func (s *Server) handleCreateTree(w http.ResponseWriter, req *http.Request, r *repository) {
+	var body struct {
+		BaseTree string `json:"base_tree"`
+		Tree     []struct {
+			Path    string  `json:"path"`
+			SHA     *string `json:"sha"`
+			Content *string `json:"content"`
+		} `json:"tree"`
+	}
+	if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	files := map[string]string{}
+	if body.BaseTree != "" {
+		base, ok := s.getTree(r, body.BaseTree)
+		if !ok {
+			writeError(w, http.StatusUnprocessableEntity, "base_tree is not a valid tree")
+			return
+		}
+		files = copyFiles(base)
+	}
+	for _, entry := range body.Tree {
+		switch {
+		case entry.Content != nil:
+			files[entry.Path] = *entry.Content
+		case entry.SHA == nil:
+			delete(files, entry.Path)
+		default:
+			writeError(w, http.StatusUnprocessableEntity, "tree entries by sha are not supported")
+			return
+		}
+	}
+	sha := s.addTree(r, files)
+	writeJSON(w, http.StatusCreated, github.Tree{SHA: github.Ptr(sha)})
+}
+
+func (s *Server) handleCreateCommit(w http.ResponseWriter, req *http.Request, r *repository) {
+	var body struct {
+		Message string   `json:"message"`
+		Tree    string   `json:"tree"`
+		Parents []string `json:"parents"`
+	}
+	if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	files, ok := r.trees[body.Tree]
+	if !ok {
+		writeError(w, http.StatusUnprocessableEntity, "tree is not a valid tree")
+		return
+	}
+	for _, p := range body.Parents {
+		if _, ok := r.commits[p]; !ok {
+			writeError(w, http.StatusUnprocessableEntity, "parent is not a valid commit")
+			return
+		}
+	}
+	sha := s.commit(r, body.Message, files, body.Parents)
+	writeJSON(w, http.StatusCreated, s.getCommit(r, sha))
+}
+
+func (s *Server) handleCreateRef(w http.ResponseWriter, req *http.Request, r *repository) {
+	var body github.CreateRef
+	if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	branch := strings.TrimPrefix(body.Ref, "refs/heads/")
+	if _, ok := r.branches[branch]; ok {
+		writeError(w, http.StatusUnprocessableEntity, "Reference already exists")
+		return
+	}
+	if _, ok := r.commits[body.SHA]; !ok {
+		writeError(w, http.StatusUnprocessableEntity, "Object does not exist")
+		return
+	}
+	r.branches[branch] = body.SHA
+	writeJSON(w, http.StatusCreated, github.Reference{Ref: github.Ptr(body.Ref), Object: &github.GitObject{SHA: github.Ptr(body.SHA)}})
+}
+
+func (s *Server) handleUpdateRef(w http.ResponseWriter, req *http.Request, r *repository, branch string) {
+	var body github.UpdateRef
+	if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	head, ok := r.branches[branch]
+	if !ok {
+		writeError(w, http.StatusUnprocessableEntity, "Reference does not exist")
+		return
+	}
+	c, ok := r.commits[body.SHA]
+	if !ok {
+		writeError(w, http.StatusUnprocessableEntity, "Object does not exist")
+		return
+	}
+	if !body.GetForce() && (len(c.parents) == 0 || c.parents[0] != head) {
+		writeError(w, http.StatusUnprocessableEntity, "Update is not a fast forward")
+		return
+	}
+	r.branches[branch] = body.SHA
+	writeJSON(w, http.StatusOK, github.Reference{Ref: github.Ptr("refs/heads/" + branch), Object: &github.GitObject{SHA: github.Ptr(body.SHA)}})
+}
+
+func (s *Server) handlePulls(w http.ResponseWriter, req *http.Request, r *repository) {
+	switch req.Method {
+	case http.MethodGet:
+		var pulls []*github.PullRequest
+		for _, p := range r.pulls {
+			pulls = append(pulls, &github.PullRequest{
+				Number: github.Ptr(p.Number),
+				Title:  github.Ptr(p.Title),
+				State:  github.Ptr("open"),
+			})
+		}
+		writeJSON(w, http.StatusOK, pulls)
+	case http.MethodPost:
+		var body github.CreatePullRequest
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if _, ok := r.branches[body.Head]; !ok {
+			writeError(w, http.StatusUnprocessableEntity, "head branch does not exist")
+			return
+		}
+		p := PullRequest{
+			Number: len(r.pulls) + 1,
+			Title:  body.GetTitle(),
+			Head:   body.Head,
+			Base:   body.Base,
+		}
+		r.pulls = append(r.pulls, p)
+		writeJSON(w, http.StatusCreated, github.PullRequest{
+			Number:  github.Ptr(p.Number),
+			Title:   github.Ptr(p.Title),
+			HTMLURL: github.Ptr(fmt.Sprintf("%s/%s/%s/pull/%d", s.URL, r.organization, r.name, p.Number)),
+		})
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "Method Not Allowed")
+	}
+}
+
+func (s *Server) handleGenerate(w http.ResponseWriter, req *http.Request, template *repository) {
+	var body github.TemplateRepoRequest
+	if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	name := fmt.Sprintf("%s/%s", body.GetOwner(), body.GetName())
+	if _, ok := s.repositories[name]; ok {
+		writeError(w, http.StatusUnprocessableEntity, "Name already exists on this account")
+		return
+	}
+	files, _ := s.getFiles(template, MainBranch)
+	s.organizations[body.GetOwner()] = true
+	r := &repository{
+		organization: body.GetOwner(),
+		name:         body.GetName(),
+		branches:     map[string]string{},
+		commits:      map[string]*commit{},
+		trees:        map[string]map[string]string{},
+		teams:        map[string]string{},
+	}
+	s.repositories[name] = r
+	r.branches[MainBranch] = s.commit(r, "Initial commit", copyFiles(files), nil)
+	writeJSON(w, http.StatusCreated, github.Repository{
+		Name:     github.Ptr(body.GetName()),
+		FullName: github.Ptr(name),
+		Private:  github.Ptr(body.GetPrivate()),
+	})
+}
+
+func (s *Server) handleTeam(w http.ResponseWriter, req *http.Request, slug string, organization string, name string) {
+	r, ok := s.repositories[fmt.Sprintf("%s/%s", organization, name)]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	switch req.Method {
+	case http.MethodGet:
+		permission, ok := r.teams[slug]
+		if !ok {
+			writeError(w, http.StatusNotFound, "Not Found")
+			return
+		}
+		writeJSON(w, http.StatusOK, github.Repository{
+			Name:        github.Ptr(name),
+			Permissions: getPermissions(permission),
+		})
+	case http.MethodPut:
+		var body github.TeamAddTeamRepoOptions
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		r.teams[slug] = body.Permission
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "Method Not Allowed")
+	}
+}
+
+// handleGraphQL supports the queries and mutations used to set up branch protection.
+func (s *Server) handleGraphQL(w http.ResponseWriter, req *http.Request) {
+	var body struct {
+		Query     string         `json:"query"`
+		Variables map[string]any `json:"variables"`
+	}
+	if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if strings.Contains(body.Query, "createBranchProtectionRule") {
+		input, _ := body.Variables["input"].(map[string]any)
+		id, _ := input["repositoryId"].(string)
+		pattern, _ := input["pattern"].(string)
+		r, ok := s.repositories[id]
+		if !ok {
+			writeGraphQLError(w, fmt.Sprintf("Could not resolve to a node with the global id of '%s'", id))
+			return
+		}
+		r.protections = append(r.protections, pattern)
+		writeJSON(w, http.StatusOK, map[string]any{
+			"data": map[string]any{
+				"createBranchProtectionRule": map[string]any{
+					"branchProtectionRule": map[string]any{"id": fmt.Sprintf("%s/%s", id, pattern)},
+				},
+			},
+		})
+		return
+	}
+
+	owner, _ := body.Variables["owner"].(string)
+	name, _ := body.Variables["name"].(string)
+	id := fmt.Sprintf("%s/%s", owner, name)
+	r, ok := s.repositories[id]
+	if !ok {
+		writeGraphQLError(w, fmt.Sprintf("Could not resolve to a Repository with the name '%s'.", id))
+		return
+	}
+	// only answer the fields that were queried, the client rejects unknown fields
+	repository := map[string]any{}
+	if strings.Contains(body.Query, "branchProtectionRules") {
+		var nodes []map[string]any
+		for _, p := range r.protections {
+			nodes = append(nodes, map[string]any{"pattern": p})
+		}
+		repository["branchProtectionRules"] = map[string]any{"nodes": nodes}
+	} else {
+		repository["id"] = id
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"data": map[string]any{"repository": repository},
+	})
+}
+
+func (s *Server) commit(r *repository, message string, files map[string]string, parents []string) string {
+	tree := s.addTree(r, files)
+	s.counter++
+	sha := getSHA(fmt.Sprintf("commit %d %s %s %v", s.counter, message, tree, parents))
+	r.commits[sha] = &commit{
+		message: message,
+		tree:    tree,
+		parents: parents,
+		// commits are a minute apart to get a stable order
+		time: time.Date(2024, 1, 1, 0, s.counter, 0, 0, time.UTC),
+	}
+	return sha
+}
+
+func (s *Server) addTree(r *repository, files map[string]string) string {
+	var paths []string
+	for p := range files {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	var b strings.Builder
+	for _, p := range paths {
+		fmt.Fprintf(&b, "%s %s\n", p, getBlobSHA(files[p]))
+	}
+	sha := getSHA("tree " + b.String())
+	r.trees[sha] = copyFiles(files)
+	return sha
+}
+
+func (s *Server) getCommit(r *repository, sha string) github.Commit {
+	c := r.commits[sha]
+	var parents []*github.Commit
+	for _, p := range c.parents {
+		parents = append(parents, &github.Commit{SHA: github.Ptr(p)})
+	}
+	return github.Commit{
+		SHA:     github.Ptr(sha),
+		Message: github.Ptr(c.message),
+		Tree:    &github.Tree{SHA: github.Ptr(c.tree)},
+		Parents: parents,
+		HTMLURL: github.Ptr(fmt.Sprintf("%s/%s/%s/commit/%s", s.URL, r.organization, r.name, sha)),
+	}
+}
+
+// getTree returns the files of a tree or of the tree of a commit.
+func (s *Server) getTree(r *repository, sha string) (map[string]string, bool) {
+	if c, ok := r.commits[sha]; ok {
+		sha = c.tree
+	}
+	files, ok := r.trees[sha]
+	return files, ok
+}
+
+func (s *Server) getFiles(r *repository, ref string) (map[string]string, bool) {
+	if head, ok := r.branches[ref]; ok {
+		ref = head
+	}
+	return s.getTree(r, ref)
+}
+
+func getFileContent(path string, content string) *github.RepositoryContent {
+	return &github.RepositoryContent{
+		Type:     github.Ptr("file"),
+		Encoding: github.Ptr("base64"),
+		Size:     github.Ptr(len(content)),
+		Name:     github.Ptr(path[strings.LastIndex(path, "/")+1:]),
+		Path:     github.Ptr(path),
+		Content:  github.Ptr(base64.StdEncoding.EncodeToString([]byte(content))),
+		SHA:      github.Ptr(getBlobSHA(content)),
+	}
+}
+
+func getDirectoryContent(files map[string]string, path string) []*github.RepositoryContent {
+	prefix := strings.TrimSuffix(path, "/") + "/"
+	if path == "" {
+		prefix = ""
+	}
+	entries := map[string]*github.RepositoryContent{}
+	for p, content := range files {
+		if !strings.HasPrefix(p, prefix) {
+			continue
+		}
+		rest := strings.TrimPrefix(p, prefix)
+		name, _, isDir := strings.Cut(rest, "/")
+		if isDir {
+			entries[name] = &github.RepositoryContent{
+				Type: github.Ptr("dir"),
+				Name: github.Ptr(name),
+				Path: github.Ptr(prefix + name),
+			}
+		} else {
+			entries[name] = &github.RepositoryContent{
+				Type: github.Ptr("file"),
+				Name: github.Ptr(name),
+				Path: github.Ptr(p),
+				SHA:  github.Ptr(getBlobSHA(content)),
+				Size: github.Ptr(len(content)),
+			}
+		}
+	}
+	var names []string
+	for name := range entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var directory []*github.RepositoryContent
+	for _, name := range names {
+		directory = append(directory, entries[name])
+	}
+	return directory
+}
+
+func getPermissions(permission string) *github.RepositoryPermissions {
+	levels := []string{"pull", "triage", "push", "maintain", "admin"}
+	granted := map[string]bool{}
+	for _, level := range levels {
+		granted[level] = true
+		if level == permission {
+			break
+		}
+	}
+	return &github.RepositoryPermissions{
+		Pull:     github.Ptr(granted["pull"]),
+		Triage:   github.Ptr(granted["triage"]),
+		Push:     github.Ptr(granted["push"]),
+		Maintain: github.Ptr(granted["maintain"]),
+		Admin:    github.Ptr(granted["admin"]),
+	}
+}
+
+func getSegments(path string) ([]string, error) {
+	var segments []string
+	for _, segment := range strings.Split(strings.Trim(path, "/"), "/") {
+		s, err := url.PathUnescape(segment)
+		if err != nil {
+			return nil, err
+		}
+		segments = append(segments, s)
+	}
+	return segments, nil
+}
+
+func getBlobSHA(content string) string {
+	return getSHA(fmt.Sprintf("blob %d\x00%s", len(content), content))
+}
+
+func getSHA(data string) string {
+	return fmt.Sprintf("%x", sha1.Sum([]byte(data))) // #nosec G401 -- used to mimic git object ids
+}
+
+func copyFiles(files map[string]string) map[string]string {
+	result := map[string]string{}
+	for k, v := range files {
+		result[k] = v
+	}
+	return result
+}
+
+func writeJSON(w http.ResponseWriter, status int, data any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(data)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"message": message})
+}
+
+func writeGraphQLError(w http.ResponseWriter, message string) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"errors": []map[string]string{{"message": message}},
+	})
+}
@@ -0,0 +1,55 @@
+package github
+
+import (
+	"fmt"
+	"sort"
+)
+
+const (
+	StatusUnchanged = "unchanged"
+	StatusUpdated   = "updated"
+	StatusCreated   = "created"
+)
+
+// Result describes the outcome of writing files to a repository.
+type Result struct {
+	Repository string   `json:"repository"`
+	Branch     string   `json:"branch"`
+	Status     string   `json:"status"`
+	CommitSHA  string   `json:"commitSHA,omitempty"`
+	CommitURL  string   `json:"commitURL,omitempty"`
+	Files      []string `json:"files,omitempty"`
+}
+
+// NewResult returns a result without changes for the repository.
+func (r *Repository) NewResult() *Result {
+	return &Result{
+		Repository: fmt.Sprintf("%s/%s", r.Organization, r.Name),
+		Branch:     r.Branch,
+		Status:     StatusUnchanged,
+	}
+}
+
+// addFile records a changed file. The result is only considered created if all changed files are new.
+func (res *Result) addFile(path string, created bool) {
+	res.Files = append(res.Files, path)
+	sort.Strings(res.Files)
+	if !created {
+		res.Status = StatusUpdated
+	} else if res.Status == StatusUnchanged {
+		res.Status = StatusCreated
+	}
+}
+
+// Merge combines the results of multiple writes to the same repository.
+// The commit of the latest write that changed files is kept.
+func (res *Result) Merge(other *Result) {
+	if other == nil || other.Status == StatusUnchanged {
+		return
+	}
+	for _, f := range other.Files {
+		res.addFile(f, other.Status == StatusCreated)
+	}
+	res.CommitSHA = other.CommitSHA
+	res.CommitURL = other.CommitURL
+}
@@ -4,6 +4,7 @@ import (
 	"crypto/rand"
 	"fmt"
 	"math/big"
+	"strings"
 )
 
 const (
@@ -19,15 +20,25 @@ const (
 	Bots                    = "bots"
 	CMCTemplateRepository   = "template-management-clusters"
 	CMCEntryTemplatePath    = "scripts/setup-cmc-branch/management-cluster-template"
+	CMCClustersPath         = "management-clusters"
 	FluxNamespace           = "flux-giantswarm"
 	SchemaFile              = "schema.json"
 )
 
+const (
+	AuditPath = ".mcli/audit"
+)
+
 const (
 	ClusterValuesFile = "cluster-values.yaml"
 	CommonSecretsFile = "common.secrets"
 )
 
+const (
+	OutputYAML = "yaml"
+	OutputJSON = "json"
+)
+
 const (
 	ProviderAWS     = "capa"
 	ProviderAzure   = "capz"
@@ -68,6 +79,19 @@ func GetValidProviders() []string {
 	}
 }
 
+func GetValidOutputs() []string {
+	return []string{OutputYAML, OutputJSON}
+}
+
+func IsValidOutput(output string) bool {
+	for _, o := range GetValidOutputs() {
+		if o == output {
+			return true
+		}
+	}
+	return false
+}
+
 func GetValidRepositories() []string {
 	return []string{
 		RepositoryInstallations,
@@ -87,8 +111,17 @@ func GetInstallationsPath(cluster string) string {
 	return fmt.Sprintf("%s/cluster.yaml", cluster)
 }
 
+// GetClusterFromInstallationsPath returns the cluster if the file is the installations entry of a cluster.
+func GetClusterFromInstallationsPath(file string) (string, bool) {
+	cluster, _, ok := strings.Cut(file, "/")
+	if !ok || cluster == "" || file != GetInstallationsPath(cluster) {
+		return "", false
+	}
+	return cluster, true
+}
+
 func GetCMCPath(cluster string) string {
-	return fmt.Sprintf("management-clusters/%s", cluster)
+	return fmt.Sprintf("%s/%s", CMCClustersPath, cluster)
 }
 
 func GetCMCName(customer string) string {
@@ -107,6 +140,26 @@ func GetDefaultConfigBranch(cluster string) string {
 	return fmt.Sprintf("%s_auto_config", cluster)
 }
 
+func GetDeleteBranch(cluster string) string {
+	return fmt.Sprintf("%s_auto_delete", cluster)
+}
+
+// GetRevertBranch returns a branch per revision, so a revert never builds on the branch of another one.
+func GetRevertBranch(cluster string, sha string) string {
+	if len(sha) > 7 {
+		sha = sha[:7]
+	}
+	return fmt.Sprintf("%s_auto_revert_%s", cluster, sha)
+}
+
+func GetBulkBranch() string {
+	return fmt.Sprintf("bulk_auto_%s", GetRandom())
+}
+
+func GetAuditFile(cluster string) string {
+	return fmt.Sprintf("%s/%s.jsonl", AuditPath, cluster)
+}
+
 func GetOwnershipBranch(customer string) string {
 	return fmt.Sprintf("add-%s-mc-to-honeybadger-%s", customer, GetRandom())
 }
@@ -119,6 +172,18 @@ func GetContainerRegistriesFile(cluster string) string {
 	return fmt.Sprintf("%s-container-registries-configuration.yaml", cluster)
 }
 
+func GetPrivateCABundleFile(cluster string) string {
+	return fmt.Sprintf("%s-private-ca-bundle.pem", cluster)
+}
+
+func GetPrivateCACertificateFile(cluster string) string {
+	return fmt.Sprintf("%s-private-ca.crt", cluster)
+}
+
+func GetPrivateCAKeyFile(cluster string) string {
+	return fmt.Sprintf("%s-private-ca.key", cluster)
+}
+
 func GetCertManagerSecretName(cluster string) string {
 	return fmt.Sprintf("%s-cert-manager-user-secrets", cluster)
 }
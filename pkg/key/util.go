@@ -4,12 +4,16 @@ import (
 	"bytes"
 	"encoding/base64"
 	"fmt"
+	"reflect"
 	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/rs/zerolog/log"
 	"go.uber.org/config"
 	"gopkg.in/yaml.v3"
+
+	"github.com/giantswarm/mcli/pkg/project"
 )
 
 func GetSecretValue(key string, data string) (string, error) {
@@ -114,6 +118,255 @@ func MergeValues(valuesA string, valuesB string) (string, error) {
 	return string(data), nil
 }
 
+// KeepUnchangedValues returns the current values if they contain the same data as the desired values,
+// so comments and order of the current values are kept.
+func KeepUnchangedValues(current string, desired string) (string, error) {
+	var a, b any
+	if err := yaml.Unmarshal([]byte(current), &a); err != nil {
+		return "", fmt.Errorf("failed to unmarshal current values.\n%w", err)
+	}
+	if err := yaml.Unmarshal([]byte(desired), &b); err != nil {
+		return "", fmt.Errorf("failed to unmarshal desired values.\n%w", err)
+	}
+	if reflect.DeepEqual(a, b) {
+		return current, nil
+	}
+	return desired, nil
+}
+
+// RemoveValue removes the field at the path from the values as well as objects which are left empty.
+// The values are returned unchanged if the field does not exist. Otherwise comments and order are kept.
+func RemoveValue(values string, path ...string) (string, error) {
+	var document yaml.Node
+	if err := yaml.Unmarshal([]byte(values), &document); err != nil {
+		return "", fmt.Errorf("failed to unmarshal values.\n%w", err)
+	}
+	if len(document.Content) == 0 || !removeValue(document.Content[0], path) {
+		return values, nil
+	}
+	if len(document.Content[0].Content) == 0 {
+		return "", nil
+	}
+	data, err := GetData(&document)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal values.\n%w", err)
+	}
+	return string(data), nil
+}
+
+func removeValue(node *yaml.Node, path []string) bool {
+	if node.Kind != yaml.MappingNode {
+		return false
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value != path[0] {
+			continue
+		}
+		value := node.Content[i+1]
+		if len(path) > 1 {
+			if !removeValue(value, path[1:]) {
+				return false
+			}
+			if value.Kind != yaml.MappingNode || len(value.Content) > 0 {
+				return true
+			}
+		}
+		node.Content = append(node.Content[:i], node.Content[i+2:]...)
+		return true
+	}
+	return false
+}
+
+// GetChangedFields returns the YAML paths of all fields that differ between two objects.
+func GetChangedFields(current any, desired any) ([]string, error) {
+	a, err := getFields(current)
+	if err != nil {
+		return nil, err
+	}
+	b, err := getFields(desired)
+	if err != nil {
+		return nil, err
+	}
+	changed := getChangedFields("", a, b)
+	sort.Strings(changed)
+	return changed, nil
+}
+
+func getFields(object any) (map[string]any, error) {
+	data, err := GetData(object)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal object.\n%w", err)
+	}
+	var fields map[string]any
+	if err := yaml.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal object.\n%w", err)
+	}
+	return fields, nil
+}
+
+func getChangedFields(prefix string, a map[string]any, b map[string]any) []string {
+	var changed []string
+	keys := map[string]bool{}
+	for k := range a {
+		keys[k] = true
+	}
+	for k := range b {
+		keys[k] = true
+	}
+	for k := range keys {
+		path := k
+		if prefix != "" {
+			path = fmt.Sprintf("%s.%s", prefix, k)
+		}
+		nestedA, okA := a[k].(map[string]any)
+		nestedB, okB := b[k].(map[string]any)
+		if okA && okB {
+			changed = append(changed, getChangedFields(path, nestedA, nestedB)...)
+		} else if !reflect.DeepEqual(a[k], b[k]) {
+			changed = append(changed, path)
+		}
+	}
+	return changed
+}
+
+// GetUnsetFields returns the paths of the fields below the prefix which are explicitly set to null, false or "".
+// Empty lists and objects and zero numbers are not unset, since they are also left by pulled files with stripped secrets.
+// Lists are not descended into. An empty prefix returns the paths of all fields.
+func GetUnsetFields(data []byte, prefix string) ([]string, error) {
+	var fields map[string]any
+	if err := yaml.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal data.\n%w", err)
+	}
+	if prefix != "" {
+		value, ok := getField(fields, strings.Split(prefix, "."))
+		if !ok {
+			return nil, nil
+		}
+		nested, ok := value.(map[string]any)
+		if !ok {
+			return nil, nil
+		}
+		fields = nested
+	}
+	unset := getUnsetFields("", fields)
+	sort.Strings(unset)
+	return unset, nil
+}
+
+func getUnsetFields(prefix string, fields map[string]any) []string {
+	var unset []string
+	for k, v := range fields {
+		path := k
+		if prefix != "" {
+			path = fmt.Sprintf("%s.%s", prefix, k)
+		}
+		if nested, ok := v.(map[string]any); ok {
+			unset = append(unset, getUnsetFields(path, nested)...)
+		} else if v == nil || v == false || v == "" {
+			unset = append(unset, path)
+		}
+	}
+	return unset
+}
+
+// FilterUnsetFields returns the paths which are missing or empty in the object.
+func FilterUnsetFields(object any, paths []string) ([]string, error) {
+	fields, err := getFields(object)
+	if err != nil {
+		return nil, err
+	}
+	var unset []string
+	for _, path := range paths {
+		if value, ok := getField(fields, strings.Split(path, ".")); !ok || isEmpty(value) {
+			unset = append(unset, path)
+		}
+	}
+	return unset, nil
+}
+
+// RemoveEmptyFields returns the data of the object without fields which are null or "" and objects left empty by their removal.
+func RemoveEmptyFields(object any) ([]byte, error) {
+	fields, err := getFields(object)
+	if err != nil {
+		return nil, err
+	}
+	removeEmptyFields(fields)
+	return GetData(fields)
+}
+
+func removeEmptyFields(fields map[string]any) {
+	for k, v := range fields {
+		if nested, ok := v.(map[string]any); ok && len(nested) > 0 {
+			removeEmptyFields(nested)
+			if len(nested) == 0 {
+				delete(fields, k)
+			}
+		} else if v == nil || v == "" {
+			delete(fields, k)
+		}
+	}
+}
+
+// RemoveFields returns the data of the object without the fields at the paths.
+func RemoveFields(object any, paths []string) ([]byte, error) {
+	fields, err := getFields(object)
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range paths {
+		removeField(fields, strings.Split(path, "."))
+	}
+	return GetData(fields)
+}
+
+func getField(fields map[string]any, path []string) (any, bool) {
+	value, ok := fields[path[0]]
+	if !ok || len(path) == 1 {
+		return value, ok
+	}
+	nested, ok := value.(map[string]any)
+	if !ok {
+		return nil, false
+	}
+	return getField(nested, path[1:])
+}
+
+func removeField(fields map[string]any, path []string) {
+	if len(path) == 1 {
+		delete(fields, path[0])
+		return
+	}
+	if nested, ok := fields[path[0]].(map[string]any); ok {
+		removeField(nested, path[1:])
+	}
+}
+
+// isEmpty returns true for null, false, zero and empty values as well as objects of only empty values.
+func isEmpty(value any) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case bool:
+		return !v
+	case string:
+		return v == ""
+	case int:
+		return v == 0
+	case float64:
+		return v == 0
+	case []any:
+		return len(v) == 0
+	case map[string]any:
+		for _, nested := range v {
+			if !isEmpty(nested) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
 func GetSchemaHeader(schemaPath string) string {
 	return fmt.Sprintf("# yaml-language-server: $schema=%s\n", schemaPath)
 }
@@ -122,3 +375,19 @@ func PrependSchemaHeader(data []byte, schemaPath string) []byte {
 	header := GetSchemaHeader(schemaPath)
 	return append([]byte(header), data...)
 }
+
+// GetCommitMessage lists the changed fields in the body and adds the mcli version as trailer.
+func GetCommitMessage(subject string, changedFields []string) string {
+	var b strings.Builder
+	b.WriteString(subject)
+	b.WriteString("\n\n")
+	if len(changedFields) > 0 {
+		b.WriteString("Changed fields:\n")
+		for _, field := range changedFields {
+			fmt.Fprintf(&b, "- %s\n", field)
+		}
+		b.WriteString("\n")
+	}
+	fmt.Fprintf(&b, "Mcli-Version: %s\n", project.Version())
+	return b.String()
+}
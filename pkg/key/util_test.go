@@ -1,8 +1,11 @@
 package key
 
 import (
+	"reflect"
 	"strings"
 	"testing"
+
+	"gopkg.in/yaml.v3"
 )
 
 func TestGetSchemaHeader(t *testing.T) {
@@ -131,3 +134,255 @@ func TestGetSecretValue(t *testing.T) {
 		})
 	}
 }
+
+func TestGetChangedFields(t *testing.T) {
+	type nested struct {
+		A string `yaml:"a,omitempty"`
+		B string `yaml:"b,omitempty"`
+	}
+	type object struct {
+		Name   string   `yaml:"name,omitempty"`
+		List   []string `yaml:"list,omitempty"`
+		Nested nested   `yaml:"nested,omitempty"`
+	}
+	testCases := []struct {
+		name    string
+		current object
+		desired object
+
+		expected []string
+	}{
+		{
+			name:    "unchanged",
+			current: object{Name: "test", Nested: nested{A: "a"}},
+			desired: object{Name: "test", Nested: nested{A: "a"}},
+		},
+		{
+			name:     "changed nested field",
+			current:  object{Name: "test", Nested: nested{A: "a"}},
+			desired:  object{Name: "test", Nested: nested{A: "b", B: "b"}},
+			expected: []string{"nested.a", "nested.b"},
+		},
+		{
+			name:     "changed list and removed field",
+			current:  object{Name: "test", List: []string{"a"}},
+			desired:  object{List: []string{"a", "b"}},
+			expected: []string{"list", "name"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := GetChangedFields(tc.current, tc.desired)
+			if err != nil {
+				t.Fatalf("expected no error but got %v", err)
+			}
+			if strings.Join(actual, ",") != strings.Join(tc.expected, ",") {
+				t.Fatalf("expected %v but got %v", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestGetUnsetFields(t *testing.T) {
+	data := `cmc:
+  privateMC: false
+  clusterApp:
+    version: ""
+    name: cluster
+  mcProxy:
+    enabled: true
+    noProxy: []
+  customCoreDNS: {}
+  credentialExpiry: null
+  clusterAppReplicas: 0
+installations:
+  additionalProviders: []
+  slack: null
+  pipeline: stable
+`
+	testCases := []struct {
+		name   string
+		prefix string
+
+		expected []string
+	}{
+		{
+			name:     "all fields",
+			expected: []string{"cmc.clusterApp.version", "cmc.credentialExpiry", "cmc.privateMC", "installations.slack"},
+		},
+		{
+			name:     "fields below prefix",
+			prefix:   "installations",
+			expected: []string{"slack"},
+		},
+		{
+			name:   "missing prefix",
+			prefix: "secretFolder",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := GetUnsetFields([]byte(data), tc.prefix)
+			if err != nil {
+				t.Fatalf("expected no error but got %v", err)
+			}
+			if strings.Join(actual, ",") != strings.Join(tc.expected, ",") {
+				t.Fatalf("expected %v but got %v", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestRemoveFields(t *testing.T) {
+	type nested struct {
+		A string `yaml:"a"`
+		B bool   `yaml:"b"`
+	}
+	type object struct {
+		Name   string   `yaml:"name,omitempty"`
+		List   []string `yaml:"list,omitempty"`
+		Nested nested   `yaml:"nested"`
+	}
+	o := object{Name: "test", List: []string{"a"}, Nested: nested{A: "a", B: true}}
+
+	unset, err := FilterUnsetFields(object{Nested: nested{A: "a"}}, []string{"name", "list", "nested.a", "nested.b", "nested"})
+	if err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+	if strings.Join(unset, ",") != "name,list,nested.b" {
+		t.Fatalf("expected unset fields name,list,nested.b but got %v", unset)
+	}
+
+	data, err := RemoveFields(o, unset)
+	if err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+	var actual object
+	if err := yaml.Unmarshal(data, &actual); err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+	expected := object{Nested: nested{A: "a"}}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("expected %v but got %v", expected, actual)
+	}
+}
+
+func TestRemoveEmptyFields(t *testing.T) {
+	o := map[string]any{
+		"name":    "test",
+		"empty":   "",
+		"null":    nil,
+		"enabled": false,
+		"list":    []string{},
+		"secret":  map[string]any{"identity": "", "key": ""},
+		"nested":  map[string]any{"a": "a", "b": ""},
+	}
+	data, err := RemoveEmptyFields(o)
+	if err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+	expected := "enabled: false\nlist: []\nname: test\nnested:\n  a: a\n"
+	if string(data) != expected {
+		t.Fatalf("expected %q but got %q", expected, string(data))
+	}
+}
+
+func TestKeepUnchangedValues(t *testing.T) {
+	current := "# values\nb: 1\na:\n  - x\n"
+	values, err := KeepUnchangedValues(current, "a:\n- x\nb: 1\n")
+	if err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+	if values != current {
+		t.Fatalf("expected %q but got %q", current, values)
+	}
+	values, err = KeepUnchangedValues(current, "a:\n- y\nb: 1\n")
+	if err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+	if values != "a:\n- y\nb: 1\n" {
+		t.Fatalf("expected desired values but got %q", values)
+	}
+}
+
+func TestRemoveValue(t *testing.T) {
+	var testCases = []struct {
+		name   string
+		values string
+		path   []string
+
+		expected string
+	}{
+		{
+			name:     "case 0: remove field and empty parents",
+			values:   "# values\nglobal:\n  proxy:\n    enabled: true\n  name: test # name\n",
+			path:     []string{"global", "proxy", "enabled"},
+			expected: "# values\nglobal:\n  name: test # name\n",
+		},
+		{
+			name:     "case 1: parents with other fields are kept",
+			values:   "global:\n  proxy:\n    enabled: true\n    other: true\n",
+			path:     []string{"global", "proxy", "enabled"},
+			expected: "global:\n  proxy:\n    other: true\n",
+		},
+		{
+			name:     "case 2: missing field",
+			values:   "global:\n    name: test\n",
+			path:     []string{"global", "proxy", "enabled"},
+			expected: "global:\n    name: test\n",
+		},
+		{
+			name:     "case 3: parent is no object",
+			values:   "global: test\n",
+			path:     []string{"global", "proxy"},
+			expected: "global: test\n",
+		},
+		{
+			name:     "case 4: only field",
+			values:   "global:\n  proxy: true\n",
+			path:     []string{"global", "proxy"},
+			expected: "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			values, err := RemoveValue(tc.values, tc.path...)
+			if err != nil {
+				t.Fatalf("expected no error but got %v", err)
+			}
+			if values != tc.expected {
+				t.Fatalf("expected %q but got %q", tc.expected, values)
+			}
+		})
+	}
+}
+
+func TestGetCommitMessage(t *testing.T) {
+	testCases := []struct {
+		name          string
+		subject       string
+		changedFields []string
+		expected      string
+	}{
+		{
+			name:     "no changed fields",
+			subject:  "Create configuration of management cluster gigmac",
+			expected: "Create configuration of management cluster gigmac\n\nMcli-Version: dev\n",
+		},
+		{
+			name:          "changed fields",
+			subject:       "Update configuration of management cluster gigmac",
+			changedFields: []string{"baseDomain", "clusterApp.version"},
+			expected:      "Update configuration of management cluster gigmac\n\nChanged fields:\n- baseDomain\n- clusterApp.version\n\nMcli-Version: dev\n",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := GetCommitMessage(tc.subject, tc.changedFields)
+			if actual != tc.expected {
+				t.Fatalf("expected %q but got %q", tc.expected, actual)
+			}
+		})
+	}
+}
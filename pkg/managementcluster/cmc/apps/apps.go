@@ -190,11 +190,20 @@ func GetAppsConfig(file string) (Config, error) {
 		Namespace:                    namespace,
 		Values:                       userConfigConfigMap.Data[ValuesKey],
 		MCAppsPreventDeletion:        userConfigConfigMap.Labels[label.PreventDeletion] == TrueString,
-		ConfigureContainerRegistries: len(app.Spec.ExtraConfigs) > 0,
+		ConfigureContainerRegistries: hasContainerRegistryConfig(app.Spec.ExtraConfigs),
 		Provider:                     getProvider(app.Spec.Name),
 	}, nil
 }
 
+func hasContainerRegistryConfig(extraConfigs []applicationv1alpha1.AppExtraConfig) bool {
+	for _, e := range extraConfigs {
+		if e.Kind == "secret" && e.Name == ContainerRegistrySecretName {
+			return true
+		}
+	}
+	return false
+}
+
 func getProvider(appName string) string {
 	if strings.Contains(appName, "aws") {
 		return key.ProviderAWS
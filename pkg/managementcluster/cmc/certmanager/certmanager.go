@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/rs/zerolog/log"
+	"gopkg.in/yaml.v3"
 
 	"github.com/giantswarm/mcli/pkg/key"
 	"github.com/giantswarm/mcli/pkg/template"
@@ -18,6 +19,13 @@ const (
 	SecretAccessKeyKey = "secretAccessKey"
 )
 
+const (
+	SolverRoute53    = "route53"
+	SolverAzureDNS   = "azureDNS"
+	SolverCloudflare = "cloudflare"
+	SolverRFC2136    = "rfc2136"
+)
+
 const CertManagerTemplate = `apiVersion: v1
 kind: Secret
 metadata:
@@ -30,13 +38,43 @@ data:
 type Config struct {
 	Cluster          string
 	ClusterNamespace string
+	Solver           string
 	Region           string
 	Role             string
 	AccessKeyID      string
 	SecretAccessKey  string
+	AzureDNS         AzureDNS
+	Cloudflare       Cloudflare
+	RFC2136          RFC2136
 	MCProxyEnabled   bool
 }
 
+// AzureDNS authenticates either with a managed identity or with a service principal.
+type AzureDNS struct {
+	SubscriptionID    string          `yaml:"subscriptionID,omitempty"`
+	ResourceGroupName string          `yaml:"resourceGroupName,omitempty"`
+	HostedZoneName    string          `yaml:"hostedZoneName,omitempty"`
+	ManagedIdentity   ManagedIdentity `yaml:"managedIdentity,omitempty"`
+	TenantID          string          `yaml:"tenantID,omitempty"`
+	ClientID          string          `yaml:"clientID,omitempty"`
+	ClientSecret      string          `yaml:"clientSecret,omitempty"`
+}
+
+type ManagedIdentity struct {
+	ClientID string `yaml:"clientID,omitempty"`
+}
+
+type Cloudflare struct {
+	APIToken string `yaml:"apiToken,omitempty"`
+}
+
+type RFC2136 struct {
+	Nameserver    string `yaml:"nameserver,omitempty"`
+	TSIGKeyName   string `yaml:"tsigKeyName,omitempty"`
+	TSIGAlgorithm string `yaml:"tsigAlgorithm,omitempty"`
+	TSIGSecret    string `yaml:"tsigSecret,omitempty"`
+}
+
 type CMSecret struct {
 	Global                        map[string]string       `yaml:"global"`
 	GiantSwarmClusterIssuer       GiantSwarmClusterIssuer `yaml:"giantSwarmClusterIssuer"`
@@ -50,59 +88,90 @@ type Acme struct {
 	Dns01 Dns01 `yaml:"dns01"`
 }
 type Dns01 struct {
-	Route53 map[string]string `yaml:"route53"`
+	Route53    map[string]string `yaml:"route53,omitempty"`
+	AzureDNS   *AzureDNS         `yaml:"azureDNS,omitempty"`
+	Cloudflare *Cloudflare       `yaml:"cloudflare,omitempty"`
+	RFC2136    *RFC2136          `yaml:"rfc2136,omitempty"`
 }
 
-func GetCertManagerConfig(file string) (Config, error) {
-	log.Debug().Msg("Getting Route53 configuration")
+func GetSolvers() []string {
+	return []string{SolverRoute53, SolverAzureDNS, SolverCloudflare, SolverRFC2136}
+}
 
-	values, err := key.GetSecretValue(ValuesKey, file)
-	if err != nil {
-		return Config{}, fmt.Errorf("failed to get values.\n%w", err)
+func IsValidSolver(solver string) bool {
+	for _, s := range GetSolvers() {
+		if s == solver {
+			return true
+		}
 	}
+	return false
+}
 
-	region, err := key.GetValue(RegionKey, values)
-	if err != nil {
-		return Config{}, fmt.Errorf("failed to get region.\n%w", err)
+// GetSolver returns the solver, Route53 is used if none is set.
+func GetSolver(solver string) string {
+	if solver == "" {
+		return SolverRoute53
 	}
+	return solver
+}
 
-	role, err := key.GetValue(RoleKey, values)
-	if err != nil {
-		return Config{}, fmt.Errorf("failed to get role.\n%w", err)
-	}
+func GetCertManagerConfig(file string) (Config, error) {
+	log.Debug().Msg("Getting DNS01 solver configuration")
 
-	accessKeyID, err := key.GetValue(AccessKeyIDKey, values)
+	values, err := key.GetSecretValue(ValuesKey, file)
 	if err != nil {
-		return Config{}, fmt.Errorf("failed to get accessKeyID.\n%w", err)
+		return Config{}, fmt.Errorf("failed to get values.\n%w", err)
 	}
 
-	secretAccessKey, err := key.GetValue(SecretAccessKeyKey, values)
-	if err != nil {
-		return Config{}, fmt.Errorf("failed to get secretAccessKey.\n%w", err)
+	var cmSecret CMSecret
+	if err := yaml.Unmarshal([]byte(values), &cmSecret); err != nil {
+		return Config{}, fmt.Errorf("failed to unmarshal values.\n%w", err)
 	}
-
-	return Config{
-		Region:          region,
-		Role:            role,
-		AccessKeyID:     accessKeyID,
-		SecretAccessKey: secretAccessKey,
-	}, nil
+	dns01 := cmSecret.GiantSwarmClusterIssuer.Acme.Dns01
+	switch {
+	case dns01.AzureDNS != nil:
+		return Config{Solver: SolverAzureDNS, AzureDNS: *dns01.AzureDNS}, nil
+	case dns01.Cloudflare != nil:
+		return Config{Solver: SolverCloudflare, Cloudflare: *dns01.Cloudflare}, nil
+	case dns01.RFC2136 != nil:
+		return Config{Solver: SolverRFC2136, RFC2136: *dns01.RFC2136}, nil
+	case dns01.Route53 != nil:
+		// route53 is the default solver and is not set explicitly
+		return Config{
+			Region:          dns01.Route53[RegionKey],
+			Role:            dns01.Route53[RoleKey],
+			AccessKeyID:     dns01.Route53[AccessKeyIDKey],
+			SecretAccessKey: dns01.Route53[SecretAccessKeyKey],
+		}, nil
+	}
+	return Config{}, fmt.Errorf("no DNS01 solver configured\n%w", ErrInvalidSolver)
 }
 
 func GetCertManagerFile(c Config) (string, error) {
-	log.Debug().Msg("Creating Route53 configuration file for cert-manager")
+	log.Debug().Msgf("Creating %s configuration file for cert-manager", GetSolver(c.Solver))
 
+	var dns01 Dns01
+	switch GetSolver(c.Solver) {
+	case SolverRoute53:
+		dns01.Route53 = map[string]string{
+			RegionKey:          c.Region,
+			RoleKey:            c.Role,
+			AccessKeyIDKey:     c.AccessKeyID,
+			SecretAccessKeyKey: c.SecretAccessKey,
+		}
+	case SolverAzureDNS:
+		dns01.AzureDNS = &c.AzureDNS
+	case SolverCloudflare:
+		dns01.Cloudflare = &c.Cloudflare
+	case SolverRFC2136:
+		dns01.RFC2136 = &c.RFC2136
+	default:
+		return "", fmt.Errorf("unknown solver %s\n%w", c.Solver, ErrInvalidSolver)
+	}
 	cmSecret := CMSecret{
 		GiantSwarmClusterIssuer: GiantSwarmClusterIssuer{
 			Acme: Acme{
-				Dns01: Dns01{
-					Route53: map[string]string{
-						RegionKey:          c.Region,
-						RoleKey:            c.Role,
-						AccessKeyIDKey:     c.AccessKeyID,
-						SecretAccessKeyKey: c.SecretAccessKey,
-					},
-				},
+				Dns01: dns01,
 			},
 		},
 	}
@@ -128,6 +197,61 @@ func GetCertManagerFile(c Config) (string, error) {
 	})
 }
 
+// Validate checks that the credentials of the solver are set.
+func Validate(c Config) error {
+	switch GetSolver(c.Solver) {
+	case SolverRoute53:
+		if c.Region == "" {
+			return fmt.Errorf("route53 region is empty\n%w", ErrInvalidSolver)
+		}
+		if c.Role == "" {
+			return fmt.Errorf("route53 role is empty\n%w", ErrInvalidSolver)
+		}
+		if c.AccessKeyID == "" {
+			return fmt.Errorf("route53 access key id is empty\n%w", ErrInvalidSolver)
+		}
+		if c.SecretAccessKey == "" {
+			return fmt.Errorf("route53 secret access key is empty\n%w", ErrInvalidSolver)
+		}
+	case SolverAzureDNS:
+		if c.AzureDNS.SubscriptionID == "" || c.AzureDNS.ResourceGroupName == "" || c.AzureDNS.HostedZoneName == "" {
+			return fmt.Errorf("azureDNS requires subscription ID, resource group name and hosted zone name\n%w", ErrInvalidSolver)
+		}
+		servicePrincipal := c.AzureDNS.TenantID != "" || c.AzureDNS.ClientID != "" || c.AzureDNS.ClientSecret != ""
+		if c.AzureDNS.ManagedIdentity.ClientID != "" && servicePrincipal {
+			return fmt.Errorf("azureDNS can use either a managed identity or a service principal\n%w", ErrInvalidSolver)
+		}
+		if servicePrincipal && (c.AzureDNS.TenantID == "" || c.AzureDNS.ClientID == "" || c.AzureDNS.ClientSecret == "") {
+			return fmt.Errorf("azureDNS service principal requires tenant ID, client ID and client secret\n%w", ErrInvalidSolver)
+		}
+		if !servicePrincipal && c.AzureDNS.ManagedIdentity.ClientID == "" {
+			return fmt.Errorf("azureDNS requires a managed identity client ID or a service principal\n%w", ErrInvalidSolver)
+		}
+	case SolverCloudflare:
+		if c.Cloudflare.APIToken == "" {
+			return fmt.Errorf("cloudflare requires an API token\n%w", ErrInvalidSolver)
+		}
+	case SolverRFC2136:
+		if c.RFC2136.Nameserver == "" || c.RFC2136.TSIGKeyName == "" || c.RFC2136.TSIGSecret == "" {
+			return fmt.Errorf("rfc2136 requires nameserver, TSIG key name and TSIG secret\n%w", ErrInvalidSolver)
+		}
+		if c.RFC2136.TSIGAlgorithm != "" && !isValidTSIGAlgorithm(c.RFC2136.TSIGAlgorithm) {
+			return fmt.Errorf("unknown TSIG algorithm %s\n%w", c.RFC2136.TSIGAlgorithm, ErrInvalidSolver)
+		}
+	default:
+		return fmt.Errorf("unknown solver %s. Valid values: %v\n%w", c.Solver, GetSolvers(), ErrInvalidSolver)
+	}
+	return nil
+}
+
+func isValidTSIGAlgorithm(algorithm string) bool {
+	switch algorithm {
+	case "HMACMD5", "HMACSHA1", "HMACSHA256", "HMACSHA512":
+		return true
+	}
+	return false
+}
+
 func GetCertManagerDefaultAppConfigMap() string {
 	log.Debug().Msg("Creating cert-manager user values config map")
 	return `kind: ConfigMap
@@ -147,3 +271,54 @@ data:
       defaultIssuer:
         name: private-giantswarm`
 }
+
+// Merge returns the configuration with all fields set in override replaced.
+func (a AzureDNS) Merge(override AzureDNS) AzureDNS {
+	if override.SubscriptionID != "" {
+		a.SubscriptionID = override.SubscriptionID
+	}
+	if override.ResourceGroupName != "" {
+		a.ResourceGroupName = override.ResourceGroupName
+	}
+	if override.HostedZoneName != "" {
+		a.HostedZoneName = override.HostedZoneName
+	}
+	if override.ManagedIdentity.ClientID != "" {
+		a.ManagedIdentity.ClientID = override.ManagedIdentity.ClientID
+	}
+	if override.TenantID != "" {
+		a.TenantID = override.TenantID
+	}
+	if override.ClientID != "" {
+		a.ClientID = override.ClientID
+	}
+	if override.ClientSecret != "" {
+		a.ClientSecret = override.ClientSecret
+	}
+	return a
+}
+
+// Merge returns the configuration with all fields set in override replaced.
+func (c Cloudflare) Merge(override Cloudflare) Cloudflare {
+	if override.APIToken != "" {
+		c.APIToken = override.APIToken
+	}
+	return c
+}
+
+// Merge returns the configuration with all fields set in override replaced.
+func (r RFC2136) Merge(override RFC2136) RFC2136 {
+	if override.Nameserver != "" {
+		r.Nameserver = override.Nameserver
+	}
+	if override.TSIGKeyName != "" {
+		r.TSIGKeyName = override.TSIGKeyName
+	}
+	if override.TSIGAlgorithm != "" {
+		r.TSIGAlgorithm = override.TSIGAlgorithm
+	}
+	if override.TSIGSecret != "" {
+		r.TSIGSecret = override.TSIGSecret
+	}
+	return r
+}
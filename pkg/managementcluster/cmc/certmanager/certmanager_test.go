@@ -0,0 +1,157 @@
+package certmanager
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestGetCertManagerConfig(t *testing.T) {
+	testCases := []struct {
+		name   string
+		config Config
+	}{
+		{
+			name: "route53",
+			config: Config{
+				Region:          "eu-west-1",
+				Role:            "role",
+				AccessKeyID:     "id",
+				SecretAccessKey: "secret",
+			},
+		},
+		{
+			name: "azure dns with managed identity",
+			config: Config{
+				Solver: SolverAzureDNS,
+				AzureDNS: AzureDNS{
+					SubscriptionID:    "subscription",
+					ResourceGroupName: "group",
+					HostedZoneName:    "example.com",
+					ManagedIdentity:   ManagedIdentity{ClientID: "identity"},
+				},
+			},
+		},
+		{
+			name: "azure dns with service principal",
+			config: Config{
+				Solver: SolverAzureDNS,
+				AzureDNS: AzureDNS{
+					SubscriptionID:    "subscription",
+					ResourceGroupName: "group",
+					HostedZoneName:    "example.com",
+					TenantID:          "tenant",
+					ClientID:          "client",
+					ClientSecret:      "secret",
+				},
+			},
+		},
+		{
+			name: "cloudflare",
+			config: Config{
+				Solver:     SolverCloudflare,
+				Cloudflare: Cloudflare{APIToken: "token"},
+			},
+		},
+		{
+			name: "rfc2136",
+			config: Config{
+				Solver: SolverRFC2136,
+				RFC2136: RFC2136{
+					Nameserver:    "10.0.0.1:53",
+					TSIGKeyName:   "key",
+					TSIGAlgorithm: "HMACSHA512",
+					TSIGSecret:    "secret",
+				},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			file, err := GetCertManagerFile(tc.config)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			output, err := GetCertManagerConfig(file)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(output, tc.config) {
+				t.Fatalf("expected %v but got %v", tc.config, output)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	testCases := []struct {
+		name        string
+		config      Config
+		expectError bool
+	}{
+		{
+			name:   "route53",
+			config: Config{Region: "eu-west-1", Role: "role", AccessKeyID: "id", SecretAccessKey: "secret"},
+		},
+		{
+			name:        "route53 without secret",
+			config:      Config{Region: "eu-west-1", Role: "role", AccessKeyID: "id"},
+			expectError: true,
+		},
+		{
+			name:        "unknown solver",
+			config:      Config{Solver: "digitalocean"},
+			expectError: true,
+		},
+		{
+			name: "azure dns with managed identity",
+			config: Config{Solver: SolverAzureDNS, AzureDNS: AzureDNS{
+				SubscriptionID: "s", ResourceGroupName: "g", HostedZoneName: "z", ManagedIdentity: ManagedIdentity{ClientID: "i"},
+			}},
+		},
+		{
+			name: "azure dns with managed identity and service principal",
+			config: Config{Solver: SolverAzureDNS, AzureDNS: AzureDNS{
+				SubscriptionID: "s", ResourceGroupName: "g", HostedZoneName: "z", ManagedIdentity: ManagedIdentity{ClientID: "i"}, ClientID: "c",
+			}},
+			expectError: true,
+		},
+		{
+			name: "azure dns with incomplete service principal",
+			config: Config{Solver: SolverAzureDNS, AzureDNS: AzureDNS{
+				SubscriptionID: "s", ResourceGroupName: "g", HostedZoneName: "z", TenantID: "t", ClientID: "c",
+			}},
+			expectError: true,
+		},
+		{
+			name:        "azure dns without credentials",
+			config:      Config{Solver: SolverAzureDNS, AzureDNS: AzureDNS{SubscriptionID: "s", ResourceGroupName: "g", HostedZoneName: "z"}},
+			expectError: true,
+		},
+		{
+			name:        "cloudflare without token",
+			config:      Config{Solver: SolverCloudflare},
+			expectError: true,
+		},
+		{
+			name:   "rfc2136 without algorithm",
+			config: Config{Solver: SolverRFC2136, RFC2136: RFC2136{Nameserver: "n", TSIGKeyName: "k", TSIGSecret: "s"}},
+		},
+		{
+			name:        "rfc2136 with unknown algorithm",
+			config:      Config{Solver: SolverRFC2136, RFC2136: RFC2136{Nameserver: "n", TSIGKeyName: "k", TSIGSecret: "s", TSIGAlgorithm: "HMACSHA3"}},
+			expectError: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := Validate(tc.config)
+			if tc.expectError && !errors.Is(err, ErrInvalidSolver) {
+				t.Fatalf("expected ErrInvalidSolver but got %v", err)
+			}
+			if !tc.expectError && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
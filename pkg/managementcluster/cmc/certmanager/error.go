@@ -0,0 +1,5 @@
+package certmanager
+
+import "errors"
+
+var ErrInvalidSolver = errors.New("invalid cert-manager DNS01 solver")
@@ -9,7 +9,13 @@ import (
 	"gopkg.in/yaml.v3"
 
 	"github.com/giantswarm/mcli/pkg/key"
+	"github.com/giantswarm/mcli/pkg/managementcluster/cmc/certmanager"
+	"github.com/giantswarm/mcli/pkg/managementcluster/cmc/coredns"
+	"github.com/giantswarm/mcli/pkg/managementcluster/cmc/credentialexpiry"
 	"github.com/giantswarm/mcli/pkg/managementcluster/cmc/defaultappsvalues"
+	"github.com/giantswarm/mcli/pkg/managementcluster/cmc/mcproxy"
+	"github.com/giantswarm/mcli/pkg/managementcluster/cmc/privateca"
+	"github.com/giantswarm/mcli/pkg/managementcluster/cmc/registry"
 )
 
 type CMC struct {
@@ -19,7 +25,7 @@ type CMC struct {
 	DefaultApps                  App                          `yaml:"defaultApps,omitempty"`
 	ClusterIntegratesDefaultApps bool                         `yaml:"clusterIntegratesDefaultApps"`
 	MCAppsPreventDeletion        bool                         `yaml:"mcAppsPreventDeletion"`
-	PrivateCA                    bool                         `yaml:"privateCA"`
+	PrivateCA                    PrivateCA                    `yaml:"privateCA"`
 	PrivateMC                    bool                         `yaml:"privateMC"`
 	ClusterNamespace             string                       `yaml:"clusterNamespace"`
 	Provider                     Provider                     `yaml:"provider"`
@@ -35,6 +41,7 @@ type CMC struct {
 	BaseDomain                   string                       `yaml:"baseDomain,omitempty"`
 	RegistryDomain               string                       `yaml:"registryDomain,omitempty"`
 	GitOps                       GitOps                       `yaml:"gitOps,omitempty"`
+	CredentialExpiry             map[string]string            `yaml:"credentialExpiry,omitempty"`
 }
 
 type App struct {
@@ -79,21 +86,59 @@ type CAPVCD struct {
 }
 
 type CertManagerDNSChallenge struct {
-	Enabled         bool   `yaml:"enabled"`
-	Region          string `yaml:"region,omitempty"`
-	Role            string `yaml:"role,omitempty"`
-	AccessKeyID     string `yaml:"accessKeyID,omitempty"`
-	SecretAccessKey string `yaml:"secretAccessKey,omitempty"`
+	Enabled bool `yaml:"enabled"`
+	// Solver is one of route53, azureDNS, cloudflare or rfc2136. Defaults to route53.
+	Solver          string                 `yaml:"solver,omitempty"`
+	Region          string                 `yaml:"region,omitempty"`
+	Role            string                 `yaml:"role,omitempty"`
+	AccessKeyID     string                 `yaml:"accessKeyID,omitempty"`
+	SecretAccessKey string                 `yaml:"secretAccessKey,omitempty"`
+	AzureDNS        certmanager.AzureDNS   `yaml:"azureDNS,omitempty"`
+	Cloudflare      certmanager.Cloudflare `yaml:"cloudflare,omitempty"`
+	RFC2136         certmanager.RFC2136    `yaml:"rfc2136,omitempty"`
+}
+
+func (c CertManagerDNSChallenge) GetConfig() certmanager.Config {
+	return certmanager.Config{
+		Solver:          certmanager.GetSolver(c.Solver),
+		Region:          c.Region,
+		Role:            c.Role,
+		AccessKeyID:     c.AccessKeyID,
+		SecretAccessKey: c.SecretAccessKey,
+		AzureDNS:        c.AzureDNS,
+		Cloudflare:      c.Cloudflare,
+		RFC2136:         c.RFC2136,
+	}
 }
 
 type ConfigureContainerRegistries struct {
-	Enabled bool   `yaml:"enabled"`
-	Values  string `yaml:"values,omitempty"`
+	Enabled    bool                `yaml:"enabled"`
+	Registries []registry.Registry `yaml:"registries,omitempty"`
+	// Values are raw containerd values which are merged underneath the registries.
+	Values string `yaml:"values,omitempty"`
+}
+
+func (c ConfigureContainerRegistries) GetConfig() registry.Config {
+	return registry.Config{
+		Registries: c.Registries,
+		Raw:        c.Values,
+	}
 }
 
 type CustomCoreDNS struct {
-	Enabled bool   `yaml:"enabled"`
-	Values  string `yaml:"values,omitempty"`
+	Enabled     bool                `yaml:"enabled"`
+	Zones       []coredns.Zone      `yaml:"zones,omitempty"`
+	StubDomains map[string][]string `yaml:"stubDomains,omitempty"`
+	// Values is a raw Corefile which is appended to the zones and stub domains.
+	Values string `yaml:"values,omitempty"`
+}
+
+func (c CustomCoreDNS) GetConfig() coredns.Config {
+	return coredns.Config{
+		Zones:       c.Zones,
+		StubDomains: c.StubDomains,
+		Raw:         c.Values,
+	}
 }
 
 type DeployKey struct {
@@ -102,10 +147,39 @@ type DeployKey struct {
 	KnownHosts string `yaml:"knownHosts"`
 }
 
+type PrivateCA struct {
+	Enabled     bool   `yaml:"enabled"`
+	Bundle      string `yaml:"bundle,omitempty"`
+	Certificate string `yaml:"certificate,omitempty"`
+	Key         string `yaml:"key,omitempty"`
+}
+
+// UnmarshalYAML keeps input files working that still set privateCA to a boolean.
+func (p *PrivateCA) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		return value.Decode(&p.Enabled)
+	}
+	type privateCA PrivateCA
+	return value.Decode((*privateCA)(p))
+}
+
+func (p PrivateCA) GetConfig() privateca.Config {
+	return privateca.Config{
+		Bundle:      p.Bundle,
+		Certificate: p.Certificate,
+		Key:         p.Key,
+	}
+}
+
 type MCProxy struct {
-	Enabled  bool   `yaml:"enabled"`
-	Hostname string `yaml:"hostname,omitempty"`
-	Port     string `yaml:"port,omitempty"`
+	Enabled    bool     `yaml:"enabled"`
+	Hostname   string   `yaml:"hostname,omitempty"`
+	Port       string   `yaml:"port,omitempty"`
+	HTTPProxy  string   `yaml:"httpProxy,omitempty"`
+	HTTPSProxy string   `yaml:"httpsProxy,omitempty"`
+	NoProxy    []string `yaml:"noProxy,omitempty"`
+	CIDR       string   `yaml:"cidr,omitempty"`
+	GithubPort string   `yaml:"githubPort,omitempty"`
 }
 
 func GetCMC(data []byte) (*CMC, error) {
@@ -184,8 +258,17 @@ func (c *CMC) Override(override *CMC) *CMC {
 	if override.MCAppsPreventDeletion {
 		cmc.MCAppsPreventDeletion = override.MCAppsPreventDeletion
 	}
-	if override.PrivateCA {
-		cmc.PrivateCA = override.PrivateCA
+	if override.PrivateCA.Enabled {
+		cmc.PrivateCA.Enabled = override.PrivateCA.Enabled
+		if override.PrivateCA.Bundle != "" {
+			cmc.PrivateCA.Bundle = override.PrivateCA.Bundle
+		}
+		if override.PrivateCA.Certificate != "" {
+			cmc.PrivateCA.Certificate = override.PrivateCA.Certificate
+		}
+		if override.PrivateCA.Key != "" {
+			cmc.PrivateCA.Key = override.PrivateCA.Key
+		}
 	}
 	if override.PrivateMC {
 		cmc.PrivateMC = override.PrivateMC
@@ -277,6 +360,11 @@ func (c *CMC) Override(override *CMC) *CMC {
 		cmc.SharedDeployKey.KnownHosts = override.SharedDeployKey.KnownHosts
 	}
 	if override.CertManagerDNSChallenge.Enabled {
+		if override.CertManagerDNSChallenge.Solver != "" &&
+			certmanager.GetSolver(override.CertManagerDNSChallenge.Solver) != certmanager.GetSolver(cmc.CertManagerDNSChallenge.Solver) {
+			// credentials of the previous solver do not apply anymore
+			cmc.CertManagerDNSChallenge = CertManagerDNSChallenge{Solver: override.CertManagerDNSChallenge.Solver}
+		}
 		cmc.CertManagerDNSChallenge.Enabled = override.CertManagerDNSChallenge.Enabled
 		if override.CertManagerDNSChallenge.Region != "" {
 			cmc.CertManagerDNSChallenge.Region = override.CertManagerDNSChallenge.Region
@@ -290,19 +378,33 @@ func (c *CMC) Override(override *CMC) *CMC {
 		if override.CertManagerDNSChallenge.SecretAccessKey != "" {
 			cmc.CertManagerDNSChallenge.SecretAccessKey = override.CertManagerDNSChallenge.SecretAccessKey
 		}
+		cmc.CertManagerDNSChallenge.AzureDNS = cmc.CertManagerDNSChallenge.AzureDNS.Merge(override.CertManagerDNSChallenge.AzureDNS)
+		cmc.CertManagerDNSChallenge.Cloudflare = cmc.CertManagerDNSChallenge.Cloudflare.Merge(override.CertManagerDNSChallenge.Cloudflare)
+		cmc.CertManagerDNSChallenge.RFC2136 = cmc.CertManagerDNSChallenge.RFC2136.Merge(override.CertManagerDNSChallenge.RFC2136)
 	}
 	if override.ConfigureContainerRegistries.Enabled {
 		cmc.ConfigureContainerRegistries.Enabled = override.ConfigureContainerRegistries.Enabled
-		if override.ConfigureContainerRegistries.Values != "" {
+		if !override.ConfigureContainerRegistries.GetConfig().IsEmpty() {
+			cmc.ConfigureContainerRegistries.Registries = override.ConfigureContainerRegistries.Registries
 			cmc.ConfigureContainerRegistries.Values = override.ConfigureContainerRegistries.Values
 		}
 	}
 	if override.CustomCoreDNS.Enabled {
-		cmc.CustomCoreDNS.Values = override.CustomCoreDNS.Values
+		cmc.CustomCoreDNS = override.CustomCoreDNS
 	}
 	if override.DisableDenyAllNetPol {
 		cmc.DisableDenyAllNetPol = override.DisableDenyAllNetPol
 	}
+	if len(override.CredentialExpiry) > 0 {
+		credentialExpiry := map[string]string{}
+		for k, v := range cmc.CredentialExpiry {
+			credentialExpiry[k] = v
+		}
+		for k, v := range override.CredentialExpiry {
+			credentialExpiry[k] = v
+		}
+		cmc.CredentialExpiry = credentialExpiry
+	}
 	if override.MCProxy.Enabled {
 		cmc.MCProxy.Enabled = override.MCProxy.Enabled
 		if override.MCProxy.Hostname != "" {
@@ -311,6 +413,21 @@ func (c *CMC) Override(override *CMC) *CMC {
 		if override.MCProxy.Port != "" {
 			cmc.MCProxy.Port = override.MCProxy.Port
 		}
+		if override.MCProxy.HTTPProxy != "" {
+			cmc.MCProxy.HTTPProxy = override.MCProxy.HTTPProxy
+		}
+		if override.MCProxy.HTTPSProxy != "" {
+			cmc.MCProxy.HTTPSProxy = override.MCProxy.HTTPSProxy
+		}
+		if len(override.MCProxy.NoProxy) > 0 {
+			cmc.MCProxy.NoProxy = override.MCProxy.NoProxy
+		}
+		if override.MCProxy.CIDR != "" {
+			cmc.MCProxy.CIDR = override.MCProxy.CIDR
+		}
+		if override.MCProxy.GithubPort != "" {
+			cmc.MCProxy.GithubPort = override.MCProxy.GithubPort
+		}
 	}
 	return &cmc
 }
@@ -440,28 +557,53 @@ func (c *CMC) Validate() error {
 		return fmt.Errorf("shared deploy key known hosts is empty")
 	}
 	if c.CertManagerDNSChallenge.Enabled {
-		if c.CertManagerDNSChallenge.Region == "" {
-			return fmt.Errorf("cert manager dns challenge region is empty")
-		}
-		if c.CertManagerDNSChallenge.Role == "" {
-			return fmt.Errorf("cert manager dns challenge role is empty")
-		}
-		if c.CertManagerDNSChallenge.AccessKeyID == "" {
-			return fmt.Errorf("cert manager dns challenge access key id is empty")
-		}
-		if c.CertManagerDNSChallenge.SecretAccessKey == "" {
-			return fmt.Errorf("cert manager dns challenge secret access key is empty")
+		if err := certmanager.Validate(c.CertManagerDNSChallenge.GetConfig()); err != nil {
+			return fmt.Errorf("cert manager dns challenge is invalid.\n%w", err)
 		}
 	}
 	if c.ConfigureContainerRegistries.Enabled {
-		if c.ConfigureContainerRegistries.Values == "" {
+		if c.ConfigureContainerRegistries.GetConfig().IsEmpty() {
 			return fmt.Errorf("configure container registries values is empty")
 		}
+		if err := registry.Validate(c.ConfigureContainerRegistries.GetConfig()); err != nil {
+			return fmt.Errorf("configure container registries values are invalid.\n%w", err)
+		}
 	}
 	if c.CustomCoreDNS.Enabled {
-		if c.CustomCoreDNS.Values == "" {
+		if c.CustomCoreDNS.GetConfig().IsEmpty() {
 			return fmt.Errorf("custom core dns values is empty")
 		}
+		if err := coredns.Validate(c.CustomCoreDNS.GetConfig()); err != nil {
+			return fmt.Errorf("custom core dns config is invalid.\n%w", err)
+		}
+	}
+	if c.PrivateCA.Enabled {
+		if c.PrivateCA.Bundle != "" {
+			if err := privateca.ValidateBundle(c.PrivateCA.Bundle); err != nil {
+				return fmt.Errorf("private ca bundle is invalid.\n%w", err)
+			}
+		}
+		if c.PrivateCA.Certificate != "" {
+			if c.PrivateCA.Key == "" {
+				return fmt.Errorf("private ca key is empty")
+			}
+			if err := privateca.ValidateBundle(c.PrivateCA.Certificate); err != nil {
+				return fmt.Errorf("private ca certificate is invalid.\n%w", err)
+			}
+			if err := privateca.ValidateKeyPair(c.PrivateCA.Certificate, c.PrivateCA.Key); err != nil {
+				return fmt.Errorf("private ca key is invalid.\n%w", err)
+			}
+		} else if c.PrivateCA.Key != "" {
+			return fmt.Errorf("private ca certificate is empty")
+		}
+	}
+	for k, v := range c.CredentialExpiry {
+		if !IsValidCredential(k) {
+			return fmt.Errorf("credential %s is unknown. Valid credentials: %v", k, GetCredentials())
+		}
+		if _, err := credentialexpiry.ParseDate(v); err != nil {
+			return fmt.Errorf("credential expiry of %s is invalid.\n%w", k, err)
+		}
 	}
 	if c.MCProxy.Enabled {
 		if c.MCProxy.Hostname == "" {
@@ -470,10 +612,26 @@ func (c *CMC) Validate() error {
 		if c.MCProxy.Port == "" {
 			return fmt.Errorf("mc proxy port is empty")
 		}
+		if err := mcproxy.Validate(c.MCProxy.GetConfig()); err != nil {
+			return fmt.Errorf("mc proxy is invalid.\n%w", err)
+		}
 	}
 	return nil
 }
 
+// Unset returns a copy of the CMC with the fields at the paths set to their empty value.
+// Override keeps current values for empty fields, so fields explicitly emptied in an input file are unset afterwards.
+func (c *CMC) Unset(paths []string) (*CMC, error) {
+	if len(paths) == 0 {
+		return c, nil
+	}
+	data, err := key.RemoveFields(c, paths)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unset fields.\n%w", err)
+	}
+	return GetCMC(data)
+}
+
 func (c *CMC) Equals(desired *CMC) bool {
 	return reflect.DeepEqual(c, desired)
 }
@@ -481,7 +639,7 @@ func (c *CMC) Equals(desired *CMC) bool {
 func (c *CMC) SetDefaultAppValues() error {
 	config := defaultappsvalues.Config{
 		Cluster:                 c.Cluster,
-		PrivateCA:               c.PrivateCA,
+		PrivateCA:               c.PrivateCA.Enabled,
 		PrivateMC:               c.PrivateMC,
 		Provider:                c.Provider.Name,
 		CertManagerDNSChallenge: c.CertManagerDNSChallenge.Enabled,
@@ -507,6 +665,65 @@ func (c *CMC) SetDefaultAppValues() error {
 	return nil
 }
 
+// SetClusterValues propagates settings that are also needed by the cluster to the cluster values.
+// current is the CMC before the change and nil if the CMC is new.
+func (c *CMC) SetClusterValues(current *CMC) error {
+	if err := c.SetProxyValues(current); err != nil {
+		return err
+	}
+	return c.SetPrivateCAValues(current)
+}
+
+// SetPrivateCAValues propagates the private CA bundle to the cluster values or removes it if the private CA is disabled by this change.
+// A trust bundle which is maintained in the cluster values while the private CA is disabled is kept.
+func (c *CMC) SetPrivateCAValues(current *CMC) error {
+	var values string
+	var err error
+	if c.PrivateCA.Enabled {
+		values, err = privateca.GetClusterValues(c.ClusterApp.Values, c.PrivateCA.GetConfig())
+	} else if current != nil && current.PrivateCA.Enabled {
+		values, err = privateca.RemoveClusterValues(c.ClusterApp.Values)
+	} else {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to set private ca values in cluster values.\n%w", err)
+	}
+	c.ClusterApp.Values = values
+	return nil
+}
+
+// SetProxyValues propagates the proxy configuration to the cluster values or removes it if the proxy is disabled by this change.
+// A proxy which is configured in the cluster values while the proxy is disabled is kept.
+func (c *CMC) SetProxyValues(current *CMC) error {
+	var values string
+	var err error
+	if c.MCProxy.Enabled {
+		values, err = mcproxy.GetClusterValues(c.ClusterApp.Values, c.MCProxy.GetConfig())
+	} else if current != nil && current.MCProxy.Enabled {
+		values, err = mcproxy.RemoveClusterValues(c.ClusterApp.Values)
+	} else {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to set proxy values in cluster values.\n%w", err)
+	}
+	c.ClusterApp.Values = values
+	return nil
+}
+
+func (p MCProxy) GetConfig() mcproxy.Config {
+	return mcproxy.Config{
+		Hostname:   p.Hostname,
+		Port:       p.Port,
+		HTTPProxy:  p.HTTPProxy,
+		HTTPSProxy: p.HTTPSProxy,
+		NoProxy:    p.NoProxy,
+		CIDR:       p.CIDR,
+		GithubPort: p.GithubPort,
+	}
+}
+
 func GetCMCFromFile(file string) (*CMC, error) {
 	log.Debug().Msg(fmt.Sprintf("getting CMC object from file %s", file))
 	data, err := os.ReadFile(file)
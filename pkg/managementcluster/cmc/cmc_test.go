@@ -0,0 +1,166 @@
+package cmc
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/giantswarm/mcli/pkg/key"
+)
+
+func TestGetCMCPrivateCA(t *testing.T) {
+	var testCases = []struct {
+		name  string
+		input string
+
+		expected PrivateCA
+	}{
+		{
+			name:     "case 0: boolean",
+			input:    "privateCA: true\n",
+			expected: PrivateCA{Enabled: true},
+		},
+		{
+			name:     "case 1: disabled boolean",
+			input:    "privateCA: false\n",
+			expected: PrivateCA{},
+		},
+		{
+			name:  "case 2: block",
+			input: "privateCA:\n  enabled: true\n  bundle: test-bundle\n",
+			expected: PrivateCA{
+				Enabled: true,
+				Bundle:  "test-bundle",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, err := GetCMC([]byte(tc.input))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(c.PrivateCA, tc.expected) {
+				t.Fatalf("expected %v, got %v", tc.expected, c.PrivateCA)
+			}
+		})
+	}
+}
+
+func TestOverrideUnset(t *testing.T) {
+	var testCases = []struct {
+		name  string
+		input string
+
+		check func(c *CMC) bool
+	}{
+		{
+			name:  "case 0: missing fields are kept",
+			input: "clusterApp:\n  version: 1.2.3\n",
+			check: func(c *CMC) bool {
+				return c.ClusterApp.Version == "1.2.3" && c.PrivateCA.Enabled && c.MCProxy.Enabled && c.ClusterApp.Values == "values"
+			},
+		},
+		{
+			name:  "case 1: disable private CA with boolean",
+			input: "privateCA: false\n",
+			check: func(c *CMC) bool {
+				return reflect.DeepEqual(c.PrivateCA, PrivateCA{}) && c.MCProxy.Enabled
+			},
+		},
+		{
+			name:  "case 2: disable mc proxy and clear values",
+			input: "mcProxy:\n  enabled: false\nclusterApp:\n  values: \"\"\n",
+			check: func(c *CMC) bool {
+				return !c.MCProxy.Enabled && c.MCProxy.Hostname == "proxy.example.com" && c.ClusterApp.Values == "" && c.ClusterApp.Version == "1.0.0"
+			},
+		},
+		{
+			name:  "case 3: remove list",
+			input: "mcProxy:\n  noProxy: null\n",
+			check: func(c *CMC) bool {
+				return c.MCProxy.Enabled && len(c.MCProxy.NoProxy) == 0
+			},
+		},
+		{
+			name:  "case 4: empty list and object are kept",
+			input: "mcProxy:\n  noProxy: []\nclusterApp: {}\nprivateCA:\n  key: \"\"\n",
+			check: func(c *CMC) bool {
+				return reflect.DeepEqual(c.MCProxy.NoProxy, []string{"a.example.com"}) && c.ClusterApp.Version == "1.0.0" && c.ClusterApp.Values == "values" && c.PrivateCA.Enabled && c.PrivateCA.Bundle == "bundle"
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			current := &CMC{
+				ClusterApp: App{Version: "1.0.0", Values: "values"},
+				PrivateCA:  PrivateCA{Enabled: true, Bundle: "bundle"},
+				MCProxy:    MCProxy{Enabled: true, Hostname: "proxy.example.com", NoProxy: []string{"a.example.com"}},
+			}
+			override, err := GetCMC([]byte(tc.input))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			unset, err := key.GetUnsetFields([]byte(tc.input), "")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			result, err := current.Override(override).Unset(unset)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !tc.check(result) {
+				t.Fatalf("unexpected result %+v", result)
+			}
+		})
+	}
+}
+
+func TestSetClusterValues(t *testing.T) {
+	values := "# cluster values\nglobal:\n  metadata:\n    name: test\n"
+	c := &CMC{
+		ClusterApp: App{Values: values},
+		PrivateCA:  PrivateCA{Enabled: true, Bundle: "bundle"},
+		MCProxy:    MCProxy{Enabled: true, Hostname: "proxy.example.com", Port: "3128"},
+	}
+	if err := c.SetClusterValues(nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	enabled := c.ClusterApp.Values
+	if enabled == values {
+		t.Fatalf("expected proxy and private CA values to be set, got %s", enabled)
+	}
+
+	// values are not rewritten on every push
+	current := *c
+	c.ClusterApp.Values = "# enabled\n" + enabled
+	if err := c.SetClusterValues(&current); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if c.ClusterApp.Values != "# enabled\n"+enabled {
+		t.Fatalf("expected unchanged values, got %s", c.ClusterApp.Values)
+	}
+
+	// values are removed when the proxy and private CA are disabled
+	current = *c
+	c.PrivateCA = PrivateCA{}
+	c.MCProxy = MCProxy{}
+	if err := c.SetClusterValues(&current); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := "# enabled\nglobal:\n  metadata:\n    name: test\n"; c.ClusterApp.Values != expected {
+		t.Fatalf("expected %q, got %q", expected, c.ClusterApp.Values)
+	}
+
+	// values configured while the proxy and private CA are disabled are kept
+	manual := "global:\n  connectivity:\n    proxy:\n      enabled: true\n      httpProxy: http://manual.example.com:3128\n  components:\n    containerd:\n      trustedCertificateAuthorities: bundle\n"
+	current = *c
+	c.ClusterApp.Values = manual
+	if err := c.SetClusterValues(&current); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if c.ClusterApp.Values != manual {
+		t.Fatalf("expected %q, got %q", manual, c.ClusterApp.Values)
+	}
+}
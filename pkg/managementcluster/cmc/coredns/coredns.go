@@ -9,7 +9,7 @@ import (
 	"sigs.k8s.io/yaml"
 )
 
-func GetCoreDNSFile(config string) (string, error) {
+func GetCoreDNSFile(config Config) (string, error) {
 	log.Debug().Msg("Creating CoreDNS configmap")
 	configmap := v1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
@@ -25,7 +25,7 @@ func GetCoreDNSFile(config string) (string, error) {
 			},
 		},
 		Data: map[string]string{
-			"Corefile": config,
+			"Corefile": GetCorefile(config),
 		},
 	}
 	data, err := yaml.Marshal(configmap)
@@ -35,14 +35,14 @@ func GetCoreDNSFile(config string) (string, error) {
 	return string(data), nil
 }
 
-func GetCoreDNSValues(file string) (string, error) {
+func GetCoreDNSValues(file string) (Config, error) {
 	log.Debug().Msg("Creating CoreDNS values")
 
 	var configmap v1.ConfigMap
 	err := yaml.Unmarshal([]byte(file), &configmap)
 	if err != nil {
-		return "", fmt.Errorf("failed to unmarshal CoreDNS configmap.\n%w", err)
+		return Config{}, fmt.Errorf("failed to unmarshal CoreDNS configmap.\n%w", err)
 	}
 
-	return configmap.Data["Corefile"], nil
+	return GetConfig(configmap.Data["Corefile"]), nil
 }
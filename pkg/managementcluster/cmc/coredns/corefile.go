@@ -0,0 +1,435 @@
+package coredns
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Config is the custom CoreDNS configuration of a management cluster.
+// Zones and stub domains are rendered as server blocks, Raw is appended as is.
+type Config struct {
+	Zones       []Zone
+	StubDomains map[string][]string
+	Raw         string
+}
+
+// Zone is a server block forwarding a zone to its upstreams with caching and optional hosts entries.
+type Zone struct {
+	Name      string   `yaml:"name"`
+	Port      int      `yaml:"port,omitempty"`
+	Upstreams []string `yaml:"upstreams,omitempty"`
+	Hosts     []Host   `yaml:"hosts,omitempty"`
+	Cache     Cache    `yaml:"cache,omitempty"`
+}
+
+type Host struct {
+	IP        string   `yaml:"ip"`
+	Hostnames []string `yaml:"hostnames"`
+}
+
+type Cache struct {
+	TTL      int `yaml:"ttl,omitempty"`
+	Success  int `yaml:"success,omitempty"`
+	Denial   int `yaml:"denial,omitempty"`
+	Prefetch int `yaml:"prefetch,omitempty"`
+}
+
+const indent = "    "
+
+var zoneName = regexp.MustCompile(`^(\.|([a-zA-Z0-9_]([a-zA-Z0-9_-]*[a-zA-Z0-9_])?\.)*[a-zA-Z0-9_]([a-zA-Z0-9_-]*[a-zA-Z0-9_])?\.?)$`)
+
+func (c Config) IsEmpty() bool {
+	return len(c.Zones) == 0 && len(c.StubDomains) == 0 && c.Raw == ""
+}
+
+// GetCorefile renders the Corefile of the configuration.
+func GetCorefile(c Config) string {
+	if len(c.Zones) == 0 && len(c.StubDomains) == 0 {
+		return c.Raw
+	}
+
+	var blocks []string
+	for _, zone := range c.Zones {
+		blocks = append(blocks, getZoneBlock(zone))
+	}
+	for _, domain := range getSortedKeys(c.StubDomains) {
+		blocks = append(blocks, getStubDomainBlock(domain, c.StubDomains[domain]))
+	}
+	if c.Raw != "" {
+		blocks = append(blocks, strings.TrimSuffix(c.Raw, "\n")+"\n")
+	}
+	return strings.Join(blocks, "\n")
+}
+
+func getZoneBlock(zone Zone) string {
+	var b strings.Builder
+	b.WriteString(getServerKey(zone.Name, zone.Port) + " {\n")
+	b.WriteString(indent + "errors\n")
+	if len(zone.Hosts) > 0 {
+		b.WriteString(indent + "hosts {\n")
+		for _, host := range zone.Hosts {
+			b.WriteString(fmt.Sprintf("%s%s%s %s\n", indent, indent, host.IP, strings.Join(host.Hostnames, " ")))
+		}
+		b.WriteString(indent + indent + "fallthrough\n")
+		b.WriteString(indent + "}\n")
+	}
+	b.WriteString(indent + "cache")
+	if zone.Cache.TTL > 0 {
+		b.WriteString(fmt.Sprintf(" %d", zone.Cache.TTL))
+	}
+	if zone.Cache.Success > 0 || zone.Cache.Denial > 0 || zone.Cache.Prefetch > 0 {
+		b.WriteString(" {\n")
+		if zone.Cache.Success > 0 {
+			b.WriteString(fmt.Sprintf("%s%ssuccess %d\n", indent, indent, zone.Cache.Success))
+		}
+		if zone.Cache.Denial > 0 {
+			b.WriteString(fmt.Sprintf("%s%sdenial %d\n", indent, indent, zone.Cache.Denial))
+		}
+		if zone.Cache.Prefetch > 0 {
+			b.WriteString(fmt.Sprintf("%s%sprefetch %d\n", indent, indent, zone.Cache.Prefetch))
+		}
+		b.WriteString(indent + "}")
+	}
+	b.WriteString("\n")
+	if len(zone.Upstreams) > 0 {
+		b.WriteString(fmt.Sprintf("%sforward . %s\n", indent, strings.Join(zone.Upstreams, " ")))
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+func getStubDomainBlock(domain string, upstreams []string) string {
+	return fmt.Sprintf("%s {\n%serrors\n%sforward . %s\n}\n", domain, indent, indent, strings.Join(upstreams, " "))
+}
+
+func getServerKey(name string, port int) string {
+	if port == 0 {
+		return name
+	}
+	return fmt.Sprintf("%s:%d", name, port)
+}
+
+// GetConfig parses a Corefile into the configuration.
+// Corefiles which can not be represented by zones and stub domains are kept as raw configuration.
+func GetConfig(corefile string) Config {
+	config, err := ParseCorefile(corefile)
+	if err != nil {
+		log.Debug().Msgf("keeping raw CoreDNS configuration. %v", err)
+		return Config{Raw: corefile}
+	}
+	return config
+}
+
+// ParseCorefile parses a Corefile into zones and stub domains.
+func ParseCorefile(corefile string) (Config, error) {
+	blocks, err := parse(corefile)
+	if err != nil {
+		return Config{}, err
+	}
+	if len(blocks) == 0 {
+		return Config{}, fmt.Errorf("no server blocks found\n%w", ErrUnsupportedCorefile)
+	}
+
+	var config Config
+	for _, block := range blocks {
+		if len(block.keys) != 1 {
+			return Config{}, fmt.Errorf("server block with multiple keys %v\n%w", block.keys, ErrUnsupportedCorefile)
+		}
+		name, port, err := parseServerKey(block.keys[0])
+		if err != nil {
+			return Config{}, err
+		}
+		if isStubDomain(block) && port == 0 {
+			if config.StubDomains == nil {
+				config.StubDomains = map[string][]string{}
+			}
+			config.StubDomains[name] = block.directives[1].args[1:]
+			continue
+		}
+		zone, err := parseZone(name, port, block)
+		if err != nil {
+			return Config{}, err
+		}
+		config.Zones = append(config.Zones, zone)
+	}
+
+	// only accept the configuration if it renders the same Corefile, so comments and formatting are not lost
+	if trimTrailingWhitespace(GetCorefile(config)) != trimTrailingWhitespace(corefile) {
+		return Config{}, fmt.Errorf("corefile can not be rendered from zones and stub domains\n%w", ErrUnsupportedCorefile)
+	}
+	return config, nil
+}
+
+func isStubDomain(block serverBlock) bool {
+	return len(block.directives) == 2 &&
+		block.directives[0].name == "errors" && len(block.directives[0].args) == 0 &&
+		isForward(block.directives[1])
+}
+
+func isForward(d directive) bool {
+	return d.name == "forward" && len(d.args) > 1 && d.args[0] == "." && d.block == nil
+}
+
+func parseServerKey(key string) (string, int, error) {
+	i := strings.LastIndex(key, ":")
+	if i < 0 {
+		return key, 0, nil
+	}
+	port, err := strconv.Atoi(key[i+1:])
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid server block key %s\n%w", key, ErrUnsupportedCorefile)
+	}
+	return key[:i], port, nil
+}
+
+func parseZone(name string, port int, block serverBlock) (Zone, error) {
+	zone := Zone{Name: name, Port: port}
+	for _, d := range block.directives {
+		switch {
+		case d.name == "errors" && len(d.args) == 0 && d.block == nil:
+		case isForward(d):
+			zone.Upstreams = d.args[1:]
+		case d.name == "hosts" && len(d.args) == 0:
+			for _, entry := range d.block {
+				if entry.name == "fallthrough" && len(entry.args) == 0 {
+					continue
+				}
+				if net.ParseIP(entry.name) == nil || entry.block != nil {
+					return Zone{}, fmt.Errorf("unsupported hosts option %s\n%w", entry.name, ErrUnsupportedCorefile)
+				}
+				zone.Hosts = append(zone.Hosts, Host{IP: entry.name, Hostnames: entry.args})
+			}
+		case d.name == "cache" && len(d.args) <= 1:
+			if len(d.args) == 1 {
+				ttl, err := strconv.Atoi(d.args[0])
+				if err != nil {
+					return Zone{}, fmt.Errorf("invalid cache TTL %s\n%w", d.args[0], ErrUnsupportedCorefile)
+				}
+				zone.Cache.TTL = ttl
+			}
+			for _, option := range d.block {
+				if len(option.args) != 1 {
+					return Zone{}, fmt.Errorf("unsupported cache option %s\n%w", option.name, ErrUnsupportedCorefile)
+				}
+				value, err := strconv.Atoi(option.args[0])
+				if err != nil {
+					return Zone{}, fmt.Errorf("invalid cache option %s\n%w", option.name, ErrUnsupportedCorefile)
+				}
+				switch option.name {
+				case "success":
+					zone.Cache.Success = value
+				case "denial":
+					zone.Cache.Denial = value
+				case "prefetch":
+					zone.Cache.Prefetch = value
+				default:
+					return Zone{}, fmt.Errorf("unsupported cache option %s\n%w", option.name, ErrUnsupportedCorefile)
+				}
+			}
+		default:
+			return Zone{}, fmt.Errorf("unsupported directive %s in server block %s\n%w", d.name, name, ErrUnsupportedCorefile)
+		}
+	}
+	return zone, nil
+}
+
+// Validate checks zones and stub domains. Raw configuration is not validated.
+func Validate(c Config) error {
+	names := map[string]bool{}
+	for _, zone := range c.Zones {
+		if err := validateZoneName(zone.Name); err != nil {
+			return err
+		}
+		if zone.Port < 0 || zone.Port > 65535 {
+			return fmt.Errorf("invalid port %d of zone %s\n%w", zone.Port, zone.Name, ErrInvalidConfig)
+		}
+		key := getServerKey(zone.Name, zone.Port)
+		if names[key] {
+			return fmt.Errorf("zone %s is configured more than once\n%w", key, ErrInvalidConfig)
+		}
+		names[key] = true
+		if len(zone.Upstreams) == 0 && len(zone.Hosts) == 0 {
+			return fmt.Errorf("zone %s has neither upstreams nor hosts\n%w", zone.Name, ErrInvalidConfig)
+		}
+		if err := validateUpstreams(zone.Name, zone.Upstreams); err != nil {
+			return err
+		}
+		for _, host := range zone.Hosts {
+			if net.ParseIP(host.IP) == nil {
+				return fmt.Errorf("invalid IP %s of hosts entry in zone %s\n%w", host.IP, zone.Name, ErrInvalidConfig)
+			}
+			if len(host.Hostnames) == 0 {
+				return fmt.Errorf("hosts entry %s in zone %s has no hostnames\n%w", host.IP, zone.Name, ErrInvalidConfig)
+			}
+		}
+		if zone.Cache.TTL < 0 || zone.Cache.Success < 0 || zone.Cache.Denial < 0 || zone.Cache.Prefetch < 0 {
+			return fmt.Errorf("invalid cache settings of zone %s\n%w", zone.Name, ErrInvalidConfig)
+		}
+	}
+	for domain, upstreams := range c.StubDomains {
+		if err := validateZoneName(domain); err != nil {
+			return err
+		}
+		if names[domain] {
+			return fmt.Errorf("stub domain %s is also configured as zone\n%w", domain, ErrInvalidConfig)
+		}
+		if len(upstreams) == 0 {
+			return fmt.Errorf("stub domain %s has no upstreams\n%w", domain, ErrInvalidConfig)
+		}
+		if err := validateUpstreams(domain, upstreams); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateZoneName(name string) error {
+	if !zoneName.MatchString(name) {
+		return fmt.Errorf("invalid zone name %q\n%w", name, ErrInvalidConfig)
+	}
+	return nil
+}
+
+// validateUpstreams checks that upstreams are IP addresses with an optional port.
+func validateUpstreams(zone string, upstreams []string) error {
+	for _, upstream := range upstreams {
+		ip := upstream
+		if host, port, err := net.SplitHostPort(upstream); err == nil {
+			if p, err := strconv.Atoi(port); err != nil || p < 1 || p > 65535 {
+				return fmt.Errorf("invalid port of upstream %s in zone %s\n%w", upstream, zone, ErrInvalidConfig)
+			}
+			ip = host
+		}
+		if net.ParseIP(ip) == nil {
+			return fmt.Errorf("upstream %s in zone %s is not an IP address\n%w", upstream, zone, ErrInvalidConfig)
+		}
+	}
+	return nil
+}
+
+type serverBlock struct {
+	keys       []string
+	directives []directive
+}
+
+type directive struct {
+	name  string
+	args  []string
+	block []directive
+}
+
+// parse reads the server blocks of a Corefile. Imports, snippets and environment variables are not supported.
+func parse(corefile string) ([]serverBlock, error) {
+	lines := tokenize(corefile)
+
+	var blocks []serverBlock
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		if line[len(line)-1] != "{" || len(line) < 2 {
+			return nil, fmt.Errorf("expected server block, got %q\n%w", strings.Join(line, " "), ErrUnsupportedCorefile)
+		}
+		directives, next, err := parseDirectives(lines, i+1)
+		if err != nil {
+			return nil, err
+		}
+		blocks = append(blocks, serverBlock{keys: line[:len(line)-1], directives: directives})
+		i = next
+	}
+	return blocks, nil
+}
+
+// parseDirectives reads directives until the closing brace and returns the index of the line containing it.
+func parseDirectives(lines [][]string, start int) ([]directive, int, error) {
+	var directives []directive
+	for i := start; i < len(lines); i++ {
+		line := lines[i]
+		if len(line) == 1 && line[0] == "}" {
+			return directives, i, nil
+		}
+		for _, token := range line {
+			if strings.HasPrefix(token, "import") || strings.HasPrefix(token, "{$") || strings.HasPrefix(token, "(") {
+				return nil, 0, fmt.Errorf("unsupported token %s\n%w", token, ErrUnsupportedCorefile)
+			}
+		}
+		d := directive{name: line[0], args: line[1:]}
+		if line[len(line)-1] == "{" {
+			if len(line) < 2 {
+				return nil, 0, fmt.Errorf("unexpected opening brace\n%w", ErrUnsupportedCorefile)
+			}
+			block, next, err := parseDirectives(lines, i+1)
+			if err != nil {
+				return nil, 0, err
+			}
+			d.args = line[1 : len(line)-1]
+			d.block = block
+			if d.block == nil {
+				d.block = []directive{}
+			}
+			i = next
+		}
+		directives = append(directives, d)
+	}
+	return nil, 0, fmt.Errorf("missing closing brace\n%w", ErrUnsupportedCorefile)
+}
+
+// tokenize splits a Corefile into lines of tokens. Comments and empty lines are dropped
+// and braces are split from adjacent tokens.
+func tokenize(corefile string) [][]string {
+	var lines [][]string
+	for _, line := range strings.Split(corefile, "\n") {
+		line = stripComment(line)
+		line = strings.ReplaceAll(line, "{", " { ")
+		line = strings.ReplaceAll(line, "}", " } ")
+
+		var tokens []string
+		for _, token := range strings.Fields(line) {
+			// a closing brace always ends a line
+			if token == "}" && len(tokens) > 0 {
+				lines = append(lines, tokens)
+				tokens = nil
+			}
+			tokens = append(tokens, token)
+			if token == "}" {
+				lines = append(lines, tokens)
+				tokens = nil
+			}
+		}
+		if len(tokens) > 0 {
+			lines = append(lines, tokens)
+		}
+	}
+	return lines
+}
+
+// stripComment removes a comment from a line. Comments start with a # at the beginning of a token.
+func stripComment(line string) string {
+	for i := 0; i < len(line); i++ {
+		if line[i] == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t') {
+			return line[:i]
+		}
+	}
+	return line
+}
+
+func trimTrailingWhitespace(corefile string) string {
+	lines := strings.Split(corefile, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t\r")
+	}
+	return strings.TrimRight(strings.Join(lines, "\n"), "\n")
+}
+
+func getSortedKeys(m map[string][]string) []string {
+	var keys []string
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,244 @@
+package coredns
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestCorefile(t *testing.T) {
+	testCases := []struct {
+		name     string
+		config   Config
+		corefile string
+	}{
+		{
+			name: "case 0: zone with hosts and cache",
+			config: Config{
+				Zones: []Zone{
+					{
+						Name:      "example.com",
+						Port:      1053,
+						Upstreams: []string{"10.0.0.1", "10.0.0.2:5353"},
+						Hosts: []Host{
+							{IP: "10.0.0.10", Hostnames: []string{"a.example.com", "b.example.com"}},
+						},
+						Cache: Cache{TTL: 30, Success: 9984, Prefetch: 10},
+					},
+				},
+			},
+			corefile: `example.com:1053 {
+    errors
+    hosts {
+        10.0.0.10 a.example.com b.example.com
+        fallthrough
+    }
+    cache 30 {
+        success 9984
+        prefetch 10
+    }
+    forward . 10.0.0.1 10.0.0.2:5353
+}
+`,
+		},
+		{
+			name: "case 1: zone and stub domains",
+			config: Config{
+				Zones: []Zone{
+					{Name: "example.com", Upstreams: []string{"10.0.0.1"}},
+				},
+				StubDomains: map[string][]string{
+					"corp.internal": {"10.1.0.1"},
+					"acme.internal": {"10.2.0.1", "10.2.0.2"},
+				},
+			},
+			corefile: `example.com {
+    errors
+    cache
+    forward . 10.0.0.1
+}
+
+acme.internal {
+    errors
+    forward . 10.2.0.1 10.2.0.2
+}
+
+corp.internal {
+    errors
+    forward . 10.1.0.1
+}
+`,
+		},
+		{
+			name: "case 2: raw configuration",
+			config: Config{
+				Raw: ".:1053 {\n    errors\n    log\n    forward . /etc/resolv.conf\n}\n",
+			},
+			corefile: ".:1053 {\n    errors\n    log\n    forward . /etc/resolv.conf\n}\n",
+		},
+		{
+			name: "case 3: commented configuration is kept as raw configuration",
+			config: Config{
+				Raw: "# forward internal zone to the corporate resolvers\ninternal.example.com {\n    errors\n    cache\n    forward . 10.0.0.10 10.0.0.11 # primary, secondary\n}\n",
+			},
+			corefile: "# forward internal zone to the corporate resolvers\ninternal.example.com {\n    errors\n    cache\n    forward . 10.0.0.10 10.0.0.11 # primary, secondary\n}\n",
+		},
+		{
+			name: "case 4: raw configuration is not parsed",
+			config: Config{
+				Raw: "customcorednsvalues",
+			},
+			corefile: "customcorednsvalues",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			corefile := GetCorefile(tc.config)
+			if corefile != tc.corefile {
+				t.Fatalf("expected\n%s\ngot\n%s", tc.corefile, corefile)
+			}
+			config := GetConfig(corefile)
+			if !reflect.DeepEqual(config, tc.config) {
+				t.Fatalf("expected %v but got %v", tc.config, config)
+			}
+		})
+	}
+}
+
+func TestParseCorefile(t *testing.T) {
+	testCases := []struct {
+		name        string
+		corefile    string
+		expectError bool
+	}{
+		{
+			name:     "case 0: trailing whitespace is ignored",
+			corefile: "example.com {  \n    errors\n    cache 30\t\n    forward . 10.0.0.1\n}\n\n",
+		},
+		{
+			name:        "case 1: comments are not rendered",
+			corefile:    "# forward internal zone to the corporate resolvers\nexample.com {\n    errors\n    cache\n    forward . 10.0.0.10 10.0.0.11 # primary, secondary\n}\n",
+			expectError: true,
+		},
+		{
+			name:        "case 2: formatting is not rendered",
+			corefile:    "example.com {\n  errors\n  cache 30\n  forward . 10.0.0.1\n}\n",
+			expectError: true,
+		},
+		{
+			name:        "case 3: unsupported plugin",
+			corefile:    "example.com {\n    errors\n    log\n    cache\n    forward . 10.0.0.1\n}\n",
+			expectError: true,
+		},
+		{
+			name:        "case 4: forward options",
+			corefile:    "example.com {\n    errors\n    forward . 10.0.0.1 {\n        max_fails 3\n    }\n}\n",
+			expectError: true,
+		},
+		{
+			name:        "case 5: hosts without fallthrough",
+			corefile:    "example.com {\n    errors\n    hosts {\n        10.0.0.10 a.example.com\n    }\n    cache\n}\n",
+			expectError: true,
+		},
+		{
+			name:        "case 6: import",
+			corefile:    "example.com {\n    import common\n}\n",
+			expectError: true,
+		},
+		{
+			name:        "case 7: missing closing brace",
+			corefile:    "example.com {\n    errors\n",
+			expectError: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParseCorefile(tc.corefile)
+			if tc.expectError && !errors.Is(err, ErrUnsupportedCorefile) {
+				t.Fatalf("expected unsupported Corefile error, got %v", err)
+			}
+			if !tc.expectError && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestStripComment(t *testing.T) {
+	testCases := []struct {
+		line     string
+		expected string
+	}{
+		{line: "# comment", expected: ""},
+		{line: "    cache 30 # seconds", expected: "    cache 30 "},
+		{line: "    cache 30\t# seconds", expected: "    cache 30\t"},
+		{line: "    forward . 10.0.0.1#53", expected: "    forward . 10.0.0.1#53"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.line, func(t *testing.T) {
+			if result := stripComment(tc.line); result != tc.expected {
+				t.Fatalf("expected %q but got %q", tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	testCases := []struct {
+		name        string
+		config      Config
+		expectError bool
+	}{
+		{
+			name: "case 0: valid",
+			config: Config{
+				Zones:       []Zone{{Name: "example.com.", Upstreams: []string{"10.0.0.1", "[fd00::1]:53"}}},
+				StubDomains: map[string][]string{"corp.internal": {"fd00::2"}},
+			},
+		},
+		{
+			name:        "case 1: upstream is no IP",
+			config:      Config{Zones: []Zone{{Name: "example.com", Upstreams: []string{"dns.example.com"}}}},
+			expectError: true,
+		},
+		{
+			name:        "case 2: invalid upstream port",
+			config:      Config{StubDomains: map[string][]string{"corp.internal": {"10.0.0.1:0"}}},
+			expectError: true,
+		},
+		{
+			name:        "case 3: invalid hosts IP",
+			config:      Config{Zones: []Zone{{Name: "example.com", Hosts: []Host{{IP: "a", Hostnames: []string{"a.example.com"}}}}}},
+			expectError: true,
+		},
+		{
+			name:        "case 4: zone without upstreams and hosts",
+			config:      Config{Zones: []Zone{{Name: "example.com"}}},
+			expectError: true,
+		},
+		{
+			name:        "case 5: invalid zone name",
+			config:      Config{Zones: []Zone{{Name: "example..com", Upstreams: []string{"10.0.0.1"}}}},
+			expectError: true,
+		},
+		{
+			name: "case 6: duplicate zone",
+			config: Config{
+				Zones:       []Zone{{Name: "example.com", Upstreams: []string{"10.0.0.1"}}},
+				StubDomains: map[string][]string{"example.com": {"10.0.0.1"}},
+			},
+			expectError: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := Validate(tc.config)
+			if tc.expectError && !errors.Is(err, ErrInvalidConfig) {
+				t.Fatalf("expected invalid config error, got %v", err)
+			}
+			if !tc.expectError && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
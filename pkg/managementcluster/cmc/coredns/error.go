@@ -0,0 +1,7 @@
+package coredns
+
+import "errors"
+
+var ErrInvalidConfig = errors.New("invalid CoreDNS configuration")
+
+var ErrUnsupportedCorefile = errors.New("unsupported Corefile")
@@ -0,0 +1,53 @@
+package credentialexpiry
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"gopkg.in/yaml.v3"
+
+	"github.com/giantswarm/mcli/pkg/key"
+)
+
+const (
+	DateFormat = "2006-01-02"
+)
+
+// File holds the expiry dates of credentials that can not be inspected directly.
+// It is not a kubernetes resource and therefore not part of the kustomization.
+type File struct {
+	Credentials map[string]string `yaml:"credentials"`
+}
+
+func GetCredentialExpiry(file string) (map[string]string, error) {
+	log.Debug().Msg("Getting credential expiry")
+
+	f := File{}
+	if err := yaml.Unmarshal([]byte(file), &f); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal credential expiry.\n%w", err)
+	}
+	return f.Credentials, nil
+}
+
+func GetCredentialExpiryFile(credentials map[string]string) (string, error) {
+	log.Debug().Msg("Creating credential expiry file")
+
+	data, err := key.GetData(File{Credentials: credentials})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal credential expiry.\n%w", err)
+	}
+	return string(data), nil
+}
+
+func ParseDate(date string) (time.Time, error) {
+	t, err := time.Parse(DateFormat, date)
+	if err == nil {
+		return t, nil
+	}
+	t, err = time.Parse(time.RFC3339, date)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to parse date %s. Expected format: %s.\n%w", date, DateFormat, err)
+	}
+	return t, nil
+}
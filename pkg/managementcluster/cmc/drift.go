@@ -0,0 +1,139 @@
+package cmc
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/giantswarm/mcli/pkg/key"
+	"github.com/giantswarm/mcli/pkg/managementcluster/cmc/kustomization"
+	"github.com/giantswarm/mcli/pkg/sops"
+)
+
+type Drift struct {
+	Cluster           string   `yaml:"cluster"`
+	Changed           []string `yaml:"changed,omitempty"`
+	Missing           []string `yaml:"missing,omitempty"`
+	Removed           []string `yaml:"removed,omitempty"`
+	Unknown           []string `yaml:"unknown,omitempty"`
+	MissingReferences []string `yaml:"missingReferences,omitempty"`
+	// Error is set if the entry could not be checked.
+	Error string `yaml:"error,omitempty"`
+}
+
+// GetDriftFromMap parses the current cmc entry and renders it from the cmc entry template like push does.
+// The result is compared with the current entry, so files mcli does not know or would no longer render are reported.
+// The template has to contain the files of the cmc entry template with the path of the cluster.
+func GetDriftFromMap(current map[string]string, template map[string]string, cluster string, cmcRepository string) (*Drift, error) {
+	log.Debug().Msg(fmt.Sprintf("checking %s entry for %s for drift", cmcRepository, cluster))
+
+	c, err := GetCMCFromMap(current, cluster, cmcRepository)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cmc from map.\n%w", err)
+	}
+
+	knownFiles := make([]string, 0, len(template))
+	desired := make(map[string]string, len(template)+1)
+	for k, v := range template {
+		knownFiles = append(knownFiles, k)
+		desired[k] = v
+	}
+	desired[SopsFile] = current[SopsFile]
+	rendered, err := c.GetMap(desired)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cmc map.\n%w", err)
+	}
+
+	// encrypted files never match, so we compare the decrypted contents
+	currentDecrypted, err := sops.DecryptDir(current)
+	if err != nil {
+		return nil, err
+	}
+	renderedDecrypted, err := sops.DecryptDir(rendered)
+	if err != nil {
+		return nil, err
+	}
+
+	return GetDrift(currentDecrypted, renderedDecrypted, knownFiles, cluster)
+}
+
+func GetDrift(current map[string]string, rendered map[string]string, knownFiles []string, cluster string) (*Drift, error) {
+	path := key.GetCMCPath(cluster)
+	drift := &Drift{
+		Cluster: cluster,
+	}
+
+	known := map[string]bool{}
+	for _, f := range knownFiles {
+		known[f] = true
+	}
+	for _, f := range kustomization.GetFiles() {
+		known[fmt.Sprintf("%s/%s", path, f)] = true
+	}
+
+	for k, v := range current {
+		if !strings.HasPrefix(k, path+"/") {
+			continue
+		}
+		r, ok := rendered[k]
+		if !ok {
+			if known[k] {
+				drift.Removed = append(drift.Removed, k)
+			} else {
+				drift.Unknown = append(drift.Unknown, k)
+			}
+			continue
+		}
+		if r != v {
+			drift.Changed = append(drift.Changed, k)
+		}
+	}
+	for k := range rendered {
+		if !strings.HasPrefix(k, path+"/") {
+			continue
+		}
+		if _, ok := current[k]; !ok {
+			drift.Missing = append(drift.Missing, k)
+		}
+	}
+
+	references, err := kustomization.GetLocalReferences(current[fmt.Sprintf("%s/%s", path, kustomization.KustomizationFile)])
+	if err != nil {
+		return nil, fmt.Errorf("failed to get kustomization references.\n%w", err)
+	}
+	for _, r := range references {
+		if !containsPath(current, fmt.Sprintf("%s/%s", path, strings.TrimSuffix(strings.TrimPrefix(r, "./"), "/"))) {
+			drift.MissingReferences = append(drift.MissingReferences, r)
+		}
+	}
+
+	sort.Strings(drift.Changed)
+	sort.Strings(drift.Missing)
+	sort.Strings(drift.Removed)
+	sort.Strings(drift.Unknown)
+	sort.Strings(drift.MissingReferences)
+	return drift, nil
+}
+
+func (d *Drift) HasDrift() bool {
+	return len(d.Changed) > 0 ||
+		len(d.Missing) > 0 ||
+		len(d.Removed) > 0 ||
+		len(d.Unknown) > 0 ||
+		len(d.MissingReferences) > 0
+}
+
+// a reference can either be a file or a directory
+func containsPath(files map[string]string, path string) bool {
+	if _, ok := files[path]; ok {
+		return true
+	}
+	for k := range files {
+		if strings.HasPrefix(k, path+"/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,285 @@
+package cmc
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/giantswarm/mcli/pkg/key"
+	"github.com/giantswarm/mcli/pkg/sops"
+)
+
+func TestGetDrift(t *testing.T) {
+	var testCases = []struct {
+		name       string
+		current    map[string]string
+		rendered   map[string]string
+		knownFiles []string
+
+		expected    *Drift
+		expectError bool
+	}{
+		{
+			name: "case 0: no drift",
+			current: map[string]string{
+				"management-clusters/cluster/kustomization.yaml":         "resources:\n  - cluster-app-manifests.yaml\n",
+				"management-clusters/cluster/cluster-app-manifests.yaml": "app",
+				".sops.yaml": "sops",
+			},
+			rendered: map[string]string{
+				"management-clusters/cluster/kustomization.yaml":         "resources:\n  - cluster-app-manifests.yaml\n",
+				"management-clusters/cluster/cluster-app-manifests.yaml": "app",
+				".sops.yaml": "other sops",
+			},
+			expected: &Drift{
+				Cluster: "cluster",
+			},
+		},
+		{
+			name: "case 1: changed, missing, removed and unknown files",
+			current: map[string]string{
+				"management-clusters/cluster/kustomization.yaml":          "resources:\n  - cluster-app-manifests.yaml\n  - coredns-configmap.yaml\n",
+				"management-clusters/cluster/cluster-app-manifests.yaml":  "app",
+				"management-clusters/cluster/coredns-configmap.yaml":      "coredns",
+				"management-clusters/cluster/custom-branch-config.yaml":   "branch",
+				"management-clusters/cluster/something-handwritten.yaml":  "custom",
+				"management-clusters/othercluster/kustomization.yaml":     "other",
+				"management-clusters/othercluster/handwritten-file.yaml":  "other",
+				"management-clusters/cluster/catalogs/kustomization.yaml": "catalogs",
+			},
+			rendered: map[string]string{
+				"management-clusters/cluster/kustomization.yaml":          "resources:\n  - cluster-app-manifests.yaml\n",
+				"management-clusters/cluster/cluster-app-manifests.yaml":  "changed app",
+				"management-clusters/cluster/custom-branch-config.yaml":   "branch",
+				"management-clusters/cluster/something-handwritten.yaml":  "custom",
+				"management-clusters/cluster/deny-all-policies.yaml":      "deny",
+				"management-clusters/cluster/catalogs/kustomization.yaml": "catalogs",
+			},
+			knownFiles: []string{
+				"management-clusters/cluster/custom-branch-config.yaml",
+			},
+			expected: &Drift{
+				Cluster: "cluster",
+				Changed: []string{
+					"management-clusters/cluster/cluster-app-manifests.yaml",
+					"management-clusters/cluster/kustomization.yaml",
+				},
+				Missing: []string{
+					"management-clusters/cluster/deny-all-policies.yaml",
+				},
+				Removed: []string{
+					"management-clusters/cluster/coredns-configmap.yaml",
+				},
+			},
+		},
+		{
+			name: "case 2: unknown files and missing references",
+			current: map[string]string{
+				"management-clusters/cluster/kustomization.yaml": `resources:
+  - https://github.com/giantswarm/management-cluster-bases//bases/provider/capa/flux-v2?ref=main
+  - cluster-app-manifests.yaml
+  - catalogs
+  - missing-file.yaml
+patches:
+  - path: ../../bases/patches/kustomization-post-build.yaml
+  - path: ./missing-patch.yaml
+`,
+				"management-clusters/cluster/cluster-app-manifests.yaml":  "app",
+				"management-clusters/cluster/catalogs/kustomization.yaml": "catalogs",
+				"management-clusters/cluster/handwritten.yaml":            "custom",
+			},
+			rendered: map[string]string{
+				"management-clusters/cluster/kustomization.yaml": `resources:
+  - https://github.com/giantswarm/management-cluster-bases//bases/provider/capa/flux-v2?ref=main
+  - cluster-app-manifests.yaml
+  - catalogs
+  - missing-file.yaml
+patches:
+  - path: ../../bases/patches/kustomization-post-build.yaml
+  - path: ./missing-patch.yaml
+`,
+				"management-clusters/cluster/cluster-app-manifests.yaml":  "app",
+				"management-clusters/cluster/catalogs/kustomization.yaml": "catalogs",
+			},
+			expected: &Drift{
+				Cluster: "cluster",
+				Unknown: []string{
+					"management-clusters/cluster/handwritten.yaml",
+				},
+				MissingReferences: []string{
+					"./missing-patch.yaml",
+					"missing-file.yaml",
+				},
+			},
+		},
+		{
+			name: "case 3: invalid kustomization",
+			current: map[string]string{
+				"management-clusters/cluster/kustomization.yaml": "resources: invalid",
+			},
+			rendered: map[string]string{
+				"management-clusters/cluster/kustomization.yaml": "resources: invalid",
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			drift, err := GetDrift(tc.current, tc.rendered, tc.knownFiles, "cluster")
+			if tc.expectError {
+				if err == nil {
+					t.Fatalf("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(drift, tc.expected) {
+				t.Fatalf("expected %v, got %v", tc.expected, drift)
+			}
+			if drift.HasDrift() != !reflect.DeepEqual(tc.expected, &Drift{Cluster: "cluster"}) {
+				t.Fatalf("unexpected drift result %v", drift.HasDrift())
+			}
+		})
+	}
+}
+
+func TestGetDriftFromMap(t *testing.T) {
+	var testCases = []struct {
+		name   string
+		add    map[string]string
+		remove []string
+
+		expected *Drift
+	}{
+		{
+			name: "case 0: no drift",
+
+			expected: &Drift{
+				Cluster: "cluster",
+			},
+		},
+		{
+			name: "case 1: stray and stale files",
+			add: map[string]string{
+				"management-clusters/cluster/handwritten.yaml":       "custom",
+				"management-clusters/cluster/coredns-configmap.yaml": "coredns",
+			},
+
+			expected: &Drift{
+				Cluster: "cluster",
+				Removed: []string{
+					"management-clusters/cluster/coredns-configmap.yaml",
+				},
+				Unknown: []string{
+					"management-clusters/cluster/handwritten.yaml",
+				},
+			},
+		},
+		{
+			name:   "case 2: template file missing",
+			remove: []string{"management-clusters/cluster/custom-branch-config.yaml"},
+
+			expected: &Drift{
+				Cluster: "cluster",
+				Missing: []string{
+					"management-clusters/cluster/custom-branch-config.yaml",
+				},
+				MissingReferences: []string{
+					"custom-branch-config.yaml",
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, ok := os.LookupEnv("CI"); ok { // we skip this test in CI since it needs sops binary to be present right now
+				t.Skip()
+			}
+
+			agekey, agepubkey, err := GetTestKeys()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			t.Setenv(sops.EnvAgeKey, agekey)
+			c := &CMC{
+				Cluster:    "cluster",
+				BaseDomain: "basedomain.io",
+				AgePubKey:  agepubkey,
+				GitOps: GitOps{
+					CMCRepository:         "test-management-clusters",
+					CMCBranch:             "cmc-branch",
+					MCBBranchSource:       "mcb-branch",
+					ConfigBranch:          "config-branch",
+					MCAppCollectionBranch: "mc-app-collection-branch",
+				},
+				ClusterApp: App{
+					Name:    "clusterapp-aws",
+					Values:  "global:\n  clusterapp: values",
+					Version: "clusterappversion",
+					Catalog: "clustercatalog",
+					AppName: "clusterappname-aws",
+				},
+				DefaultApps: App{
+					Name:    "defaultapp-aws",
+					Values:  "defaultappvalues",
+					Version: "defaultappversion",
+					Catalog: "defaultcatalog",
+					AppName: "defaultappname-aws",
+				},
+				ClusterNamespace: "clusternamespace",
+				Provider: Provider{
+					Name: key.ProviderAWS,
+				},
+				TaylorBotToken: "taylorbottoken",
+				SSHdeployKey: DeployKey{
+					Identity:   "identity",
+					Passphrase: "passphrase",
+					KnownHosts: "knownhosts",
+				},
+				CustomerDeployKey: DeployKey{
+					Identity:   "customeridentity",
+					Passphrase: "customerpassphrase",
+					KnownHosts: "customerknownhosts",
+				},
+				SharedDeployKey: DeployKey{
+					Identity:   "sharedidentity",
+					Passphrase: "sharedpassphrase",
+					KnownHosts: "sharedknownhosts",
+				},
+			}
+			if err := c.SetDefaultAppValues(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			current, err := c.GetMap(getTestDriftTemplate())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			for k, v := range tc.add {
+				current[k] = v
+			}
+			for _, k := range tc.remove {
+				delete(current, k)
+			}
+
+			drift, err := GetDriftFromMap(current, getTestDriftTemplate(), "cluster", "test-management-clusters")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(drift, tc.expected) {
+				t.Fatalf("expected %v, got %v", tc.expected, drift)
+			}
+		})
+	}
+}
+
+// getTestDriftTemplate adds the files referenced by the test kustomization which mcli does not render.
+func getTestDriftTemplate() map[string]string {
+	template := GetTestTemplate()
+	template["management-clusters/cluster/configmap-management-cluster-metadata.yaml"] = "metadata"
+	template["management-clusters/cluster/sops-secret.yaml"] = "sops-secret"
+	return template
+}
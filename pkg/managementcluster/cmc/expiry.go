@@ -0,0 +1,138 @@
+package cmc
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/giantswarm/mcli/pkg/key"
+	"github.com/giantswarm/mcli/pkg/managementcluster/cmc/credentialexpiry"
+	"github.com/giantswarm/mcli/pkg/managementcluster/cmc/privateca"
+)
+
+const (
+	CredentialAzureClientSecret         = "azureClientSecret"
+	CredentialCloudDirectorRefreshToken = "cloudDirectorRefreshToken"
+	CredentialVsphereCredentials        = "vsphereCredentials"
+	CredentialCertManagerAccessKey      = "certManagerAccessKey"
+	CredentialContainerRegistries       = "containerRegistries"
+	CredentialTaylorBotToken            = "taylorBotToken"
+	CredentialPrivateCAIssuer           = "privateCAIssuer"
+	CredentialPrivateCABundle           = "privateCABundle"
+)
+
+const (
+	ExpirySourceCertificate = "certificate"
+	ExpirySourceMetadata    = "metadata"
+	ExpirySourceUnknown     = "unknown"
+)
+
+type Expiry struct {
+	Cluster      string `yaml:"cluster"`
+	Credential   string `yaml:"credential"`
+	Subject      string `yaml:"subject,omitempty"`
+	Source       string `yaml:"source,omitempty"`
+	ExpiresAt    string `yaml:"expiresAt,omitempty"`
+	DaysToExpiry *int   `yaml:"daysToExpiry,omitempty"`
+	Error        string `yaml:"error,omitempty"`
+}
+
+// GetCredentials returns the credentials for which an expiry date can be recorded.
+func GetCredentials() []string {
+	return []string{
+		CredentialAzureClientSecret,
+		CredentialCloudDirectorRefreshToken,
+		CredentialVsphereCredentials,
+		CredentialCertManagerAccessKey,
+		CredentialContainerRegistries,
+		CredentialTaylorBotToken,
+	}
+}
+
+func IsValidCredential(credential string) bool {
+	for _, c := range GetCredentials() {
+		if c == credential {
+			return true
+		}
+	}
+	return false
+}
+
+// GetExpiry returns the expiry of all credentials configured for the management cluster.
+// Certificates are inspected directly, other credentials rely on the expiry recorded at push time.
+func (c *CMC) GetExpiry() ([]Expiry, error) {
+	var result []Expiry
+
+	for _, credential := range c.getConfiguredCredentials() {
+		e := Expiry{
+			Cluster:    c.Cluster,
+			Credential: credential,
+			Source:     ExpirySourceUnknown,
+		}
+		if date, ok := c.CredentialExpiry[credential]; ok {
+			expiresAt, err := credentialexpiry.ParseDate(date)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get expiry of %s.\n%w", credential, err)
+			}
+			e.Source = ExpirySourceMetadata
+			e.ExpiresAt = expiresAt.UTC().Format(time.RFC3339)
+			e.DaysToExpiry = getDaysToExpiry(expiresAt)
+		}
+		result = append(result, e)
+	}
+
+	if c.PrivateCA.Enabled {
+		certificates := map[string]string{
+			CredentialPrivateCAIssuer: c.PrivateCA.Certificate,
+			CredentialPrivateCABundle: c.PrivateCA.Bundle,
+		}
+		for _, credential := range []string{CredentialPrivateCAIssuer, CredentialPrivateCABundle} {
+			if certificates[credential] == "" {
+				continue
+			}
+			infos, err := privateca.GetCertificates(certificates[credential], credential)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get expiry of %s.\n%w", credential, err)
+			}
+			for _, info := range infos {
+				result = append(result, Expiry{
+					Cluster:      c.Cluster,
+					Credential:   credential,
+					Subject:      info.Subject,
+					Source:       ExpirySourceCertificate,
+					ExpiresAt:    info.NotAfter.Format(time.RFC3339),
+					DaysToExpiry: getDaysToExpiry(info.NotAfter),
+				})
+			}
+		}
+	}
+	return result, nil
+}
+
+func (c *CMC) getConfiguredCredentials() []string {
+	var credentials []string
+	if key.IsProviderAzure(c.Provider.Name) && c.Provider.CAPZ.ClientSecret != "" {
+		credentials = append(credentials, CredentialAzureClientSecret)
+	}
+	if key.IsProviderVCD(c.Provider.Name) && c.Provider.CAPVCD.RefreshToken != "" {
+		credentials = append(credentials, CredentialCloudDirectorRefreshToken)
+	}
+	if key.IsProviderVsphere(c.Provider.Name) && c.Provider.CAPV.CloudConfig != "" {
+		credentials = append(credentials, CredentialVsphereCredentials)
+	}
+	if c.CertManagerDNSChallenge.Enabled {
+		credentials = append(credentials, CredentialCertManagerAccessKey)
+	}
+	if c.ConfigureContainerRegistries.Enabled {
+		credentials = append(credentials, CredentialContainerRegistries)
+	}
+	if c.TaylorBotToken != "" {
+		credentials = append(credentials, CredentialTaylorBotToken)
+	}
+	return credentials
+}
+
+func getDaysToExpiry(t time.Time) *int {
+	days := int(math.Floor(time.Until(t).Hours() / 24))
+	return &days
+}
@@ -0,0 +1,96 @@
+package cmc
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGetExpiry(t *testing.T) {
+	inTenDays := time.Now().Add(10*24*time.Hour + time.Hour).UTC().Format(time.RFC3339)
+
+	var testCases = []struct {
+		name string
+		cmc  CMC
+
+		expectErr  bool
+		expectDays map[string]*int
+	}{
+		{
+			name: "case 0: no credentials",
+			cmc: CMC{
+				Cluster: "test",
+			},
+			expectDays: map[string]*int{},
+		},
+		{
+			name: "case 1: recorded expiry",
+			cmc: CMC{
+				Cluster:        "test",
+				TaylorBotToken: "token",
+				CredentialExpiry: map[string]string{
+					CredentialTaylorBotToken: inTenDays,
+				},
+			},
+			expectDays: map[string]*int{
+				CredentialTaylorBotToken: intPtr(10),
+			},
+		},
+		{
+			name: "case 2: unknown expiry",
+			cmc: CMC{
+				Cluster: "test",
+				ConfigureContainerRegistries: ConfigureContainerRegistries{
+					Enabled: true,
+				},
+			},
+			expectDays: map[string]*int{
+				CredentialContainerRegistries: nil,
+			},
+		},
+		{
+			name: "case 3: invalid date",
+			cmc: CMC{
+				Cluster:        "test",
+				TaylorBotToken: "token",
+				CredentialExpiry: map[string]string{
+					CredentialTaylorBotToken: "next year",
+				},
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			expiry, err := tc.cmc.GetExpiry()
+			if tc.expectErr {
+				if err == nil {
+					t.Fatalf("expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(expiry) != len(tc.expectDays) {
+				t.Fatalf("expected %d credentials, got %v", len(tc.expectDays), expiry)
+			}
+			for _, e := range expiry {
+				expected, ok := tc.expectDays[e.Credential]
+				if !ok {
+					t.Fatalf("unexpected credential %s", e.Credential)
+				}
+				if expected == nil && e.DaysToExpiry != nil {
+					t.Fatalf("expected unknown expiry for %s, got %d", e.Credential, *e.DaysToExpiry)
+				}
+				if expected != nil && (e.DaysToExpiry == nil || *e.DaysToExpiry != *expected) {
+					t.Fatalf("expected %d days to expiry for %s, got %v", *expected, e.Credential, e.DaysToExpiry)
+				}
+			}
+		})
+	}
+}
+
+func intPtr(i int) *int {
+	return &i
+}
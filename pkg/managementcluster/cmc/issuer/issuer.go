@@ -2,18 +2,20 @@ package issuer
 
 import "github.com/rs/zerolog/log"
 
-// TODO: this does not seem ideal but it's how its in mc-bootstrap and we go with it for now
-func GetIssuerFile() string {
-	log.Debug().Msg("Creating issuer file")
-	return `apiVersion: cert-manager.io/v1
+const clusterIssuer = `apiVersion: cert-manager.io/v1
 kind: ClusterIssuer
 metadata:
   name: private-giantswarm
   labels:
-	giantswarm.io/service-type: "managed"
+    giantswarm.io/service-type: "managed"
 spec:
   ca:
-	secretName: private-giantswarm-secret
+    secretName: private-giantswarm-secret`
+
+// TODO: this does not seem ideal but it's how its in mc-bootstrap and we go with it for now
+func GetIssuerFile() string {
+	log.Debug().Msg("Creating issuer file")
+	return clusterIssuer + `
 ---
 apiVersion: cert-manager.io/v1
 kind: Certificate
@@ -25,10 +27,17 @@ spec:
   commonName: gigantic.internal
   secretName: private-giantswarm-secret
   privateKey:
-	algorithm: ECDSA
-	size: 256
+    algorithm: ECDSA
+    size: 256
   issuerRef:
-	name: selfsigned-giantswarm
-	kind: ClusterIssuer
-	group: cert-manager.io`
+    name: selfsigned-giantswarm
+    kind: ClusterIssuer
+    group: cert-manager.io`
+}
+
+// GetClusterIssuerFile returns the issuer without the self-signed CA certificate.
+// It is used when the CA is provided by the customer.
+func GetClusterIssuerFile() string {
+	log.Debug().Msg("Creating issuer file for provided CA")
+	return clusterIssuer
 }
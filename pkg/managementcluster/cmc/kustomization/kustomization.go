@@ -2,6 +2,7 @@ package kustomization
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/rs/zerolog/log"
 	"gopkg.in/yaml.v3"
@@ -31,12 +32,15 @@ const (
 	CertManagerFile                    = "cert-manager-dns01-secret.yaml"
 	CertManagerConfigMapFile           = "cert-manager-configmap.yaml"
 	IssuerFile                         = "private-cluster-issuer.yaml"
+	PrivateCABundleFile                = "private-ca-bundle.yaml"
+	PrivateCASecretFile                = "private-ca-secret.yaml"
 	VsphereCredentialsFile             = "vsphere-cloud-config-secret.yaml" // #nosec G101
 	CloudDirectorCredentialsFile       = "cloud-director-cloud-config-secret.yaml"
 	AzureClusterIdentitySPFile         = "azureclusteridentity-sp.yaml"
 	AzureClusterIdentityUAFile         = "azureclusteridentity-ua.yaml"
 	AzureSecretClusterIdentityStaticSP = "secret-clusteridentity-static-sp.yaml"
 	ExternalDNSFile                    = "external-dns-configmap.yaml"
+	CredentialExpiryFile               = "credential-expiry.yaml"
 )
 
 func GetSourceControllerPatchPath(branch string) string {
@@ -50,6 +54,8 @@ type Config struct {
 	CertManagerDNSChallenge      bool
 	Provider                     string
 	PrivateCA                    bool
+	PrivateCABundle              bool
+	PrivateCASecret              bool
 	PrivateMC                    bool
 	ConfigureContainerRegistries bool
 	CustomCoreDNS                bool
@@ -69,6 +75,8 @@ func GetKustomizationConfig(file string) (Config, error) {
 		CertManagerDNSChallenge:      containsResource(kustomization.Resources, CertManagerFile),
 		Provider:                     getProvider(kustomization.Resources),
 		PrivateCA:                    containsResource(kustomization.Resources, IssuerFile),
+		PrivateCABundle:              containsResource(kustomization.Resources, PrivateCABundleFile),
+		PrivateCASecret:              containsResource(kustomization.Resources, PrivateCASecretFile),
 		ConfigureContainerRegistries: containsResource(kustomization.Resources, RegistryFile),
 		CustomCoreDNS:                containsResource(kustomization.Resources, CoreDNSFile),
 		DisableDenyAllNetPol:         !containsResource(kustomization.Resources, DenyNetPolFile),
@@ -114,6 +122,16 @@ func GetKustomizationFile(c Config, file string) (string, error) {
 			k.Resources = appendResource(k.Resources, CertManagerConfigMapFile)
 		}
 	}
+	if c.PrivateCABundle {
+		k.Resources = appendResource(k.Resources, PrivateCABundleFile)
+	} else {
+		k.Resources = removeResource(k.Resources, PrivateCABundleFile)
+	}
+	if c.PrivateCASecret {
+		k.Resources = appendResource(k.Resources, PrivateCASecretFile)
+	} else {
+		k.Resources = removeResource(k.Resources, PrivateCASecretFile)
+	}
 	if c.ConfigureContainerRegistries {
 		k.Resources = appendResource(k.Resources, RegistryFile)
 	}
@@ -148,6 +166,86 @@ func GetKustomizationFile(c Config, file string) (string, error) {
 	return string(data), nil
 }
 
+// GetFiles returns all files inside a management cluster directory that are rendered by mcli.
+func GetFiles() []string {
+	return []string{
+		AgeKeyFile,
+		ClusterAppsFile,
+		DefaultAppsFile,
+		KustomizationFile,
+		TaylorBotFile,
+		SSHdeployKeyFile,
+		CustomerDeployKeyFile,
+		SharedDeployKeyFile,
+		AllowNetPolFile,
+		SourceControllerFile,
+		RegistryFile,
+		CoreDNSFile,
+		DenyNetPolFile,
+		CertManagerFile,
+		CertManagerConfigMapFile,
+		IssuerFile,
+		PrivateCABundleFile,
+		PrivateCASecretFile,
+		VsphereCredentialsFile,
+		CloudDirectorCredentialsFile,
+		AzureClusterIdentitySPFile,
+		AzureClusterIdentityUAFile,
+		AzureSecretClusterIdentityStaticSP,
+		ExternalDNSFile,
+		CredentialExpiryFile,
+	}
+}
+
+// GetResources returns the resources and the paths of the patches of the kustomization file.
+func GetResources(file string) ([]string, error) {
+	k, err := getKustomization(file)
+	if err != nil {
+		return nil, err
+	}
+	resources := append([]string{}, k.Resources...)
+	for _, p := range k.Patches {
+		if p.Path != "" {
+			resources = append(resources, p.Path)
+		}
+	}
+	return resources, nil
+}
+
+// GetLocalReferences returns the resources and patches of the kustomization file
+// that point to files inside the management cluster directory.
+// Remote references and references to parent directories are ignored.
+func GetLocalReferences(file string) ([]string, error) {
+	k, err := getKustomization(file)
+	if err != nil {
+		return nil, err
+	}
+	var references []string
+	for _, r := range k.Resources {
+		if isLocalReference(r) {
+			references = append(references, r)
+		}
+	}
+	for _, p := range k.Patches {
+		if isLocalReference(p.Path) {
+			references = append(references, p.Path)
+		}
+	}
+	for _, p := range k.PatchesStrategicMerge {
+		if isLocalReference(string(p)) {
+			references = append(references, string(p))
+		}
+	}
+	return references, nil
+}
+
+func isLocalReference(reference string) bool {
+	return reference != "" &&
+		!strings.Contains(reference, "://") &&
+		!strings.HasPrefix(reference, "../") &&
+		!strings.HasPrefix(reference, "/")
+}
+
 func getKustomization(file string) (kustomize.Kustomization, error) {
 	k := kustomize.Kustomization{}
 	if err := yaml.Unmarshal([]byte(file), &k); err != nil {
@@ -9,12 +9,14 @@ import (
 	"github.com/giantswarm/mcli/pkg/managementcluster/cmc/base"
 	"github.com/giantswarm/mcli/pkg/managementcluster/cmc/certmanager"
 	"github.com/giantswarm/mcli/pkg/managementcluster/cmc/coredns"
+	"github.com/giantswarm/mcli/pkg/managementcluster/cmc/credentialexpiry"
 	"github.com/giantswarm/mcli/pkg/managementcluster/cmc/defaultappsvalues"
 	"github.com/giantswarm/mcli/pkg/managementcluster/cmc/deploykey"
 	"github.com/giantswarm/mcli/pkg/managementcluster/cmc/externaldns"
 	"github.com/giantswarm/mcli/pkg/managementcluster/cmc/issuer"
 	"github.com/giantswarm/mcli/pkg/managementcluster/cmc/kustomization"
 	"github.com/giantswarm/mcli/pkg/managementcluster/cmc/mcproxy"
+	"github.com/giantswarm/mcli/pkg/managementcluster/cmc/privateca"
 	"github.com/giantswarm/mcli/pkg/managementcluster/cmc/provider/capv"
 	"github.com/giantswarm/mcli/pkg/managementcluster/cmc/provider/capvcd"
 	"github.com/giantswarm/mcli/pkg/managementcluster/cmc/provider/capz"
@@ -88,7 +90,9 @@ func GetCMCFromMap(input map[string]string, cluster string, cmcRepository string
 		Provider: Provider{
 			Name: clusterAppsConfig.Provider,
 		},
-		PrivateCA:             kustomizationConfig.PrivateCA,
+		PrivateCA: PrivateCA{
+			Enabled: kustomizationConfig.PrivateCA,
+		},
 		PrivateMC:             kustomizationConfig.PrivateMC,
 		DisableDenyAllNetPol:  kustomizationConfig.DisableDenyAllNetPol,
 		MCAppsPreventDeletion: clusterAppsConfig.MCAppsPreventDeletion,
@@ -132,14 +136,31 @@ func GetCMCFromMap(input map[string]string, cluster string, cmcRepository string
 		cmc.PrivateMC = cmc.PrivateMC || defaultappsvalues.IsPrivateMC(defaultAppsConfig.Values)
 	}
 
+	if kustomizationConfig.PrivateCASecret {
+		certificate, privateKey, err := privateca.GetSecret(data[fmt.Sprintf("%s/%s", path, kustomization.PrivateCASecretFile)])
+		if err != nil {
+			return nil, fmt.Errorf("failed to get private CA secret.\n%w", err)
+		}
+		cmc.PrivateCA.Certificate = certificate
+		cmc.PrivateCA.Key = privateKey
+	}
+	if kustomizationConfig.PrivateCABundle {
+		bundle, err := privateca.GetBundle(data[fmt.Sprintf("%s/%s", path, kustomization.PrivateCABundleFile)], cmc.PrivateCA.Certificate)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get private CA bundle.\n%w", err)
+		}
+		cmc.PrivateCA.Bundle = bundle
+	}
+
 	if kustomizationConfig.ConfigureContainerRegistries {
 		registryConfig, err := registry.GetRegistryConfig(data[fmt.Sprintf("%s/%s", path, kustomization.RegistryFile)])
 		if err != nil {
 			return nil, fmt.Errorf("failed to get registry config.\n%w", err)
 		}
 		cmc.ConfigureContainerRegistries = ConfigureContainerRegistries{
-			Enabled: true,
-			Values:  registryConfig,
+			Enabled:    true,
+			Registries: registryConfig.Registries,
+			Values:     registryConfig.Raw,
 		}
 	}
 
@@ -149,8 +170,10 @@ func GetCMCFromMap(input map[string]string, cluster string, cmcRepository string
 			return nil, fmt.Errorf("failed to get coreDNS config.\n%w", err)
 		}
 		cmc.CustomCoreDNS = CustomCoreDNS{
-			Enabled: true,
-			Values:  coreDNSConfig,
+			Enabled:     true,
+			Zones:       coreDNSConfig.Zones,
+			StubDomains: coreDNSConfig.StubDomains,
+			Values:      coreDNSConfig.Raw,
 		}
 	}
 
@@ -161,10 +184,14 @@ func GetCMCFromMap(input map[string]string, cluster string, cmcRepository string
 		}
 		cmc.CertManagerDNSChallenge = CertManagerDNSChallenge{
 			Enabled:         true,
+			Solver:          certManagerConfig.Solver,
 			Region:          certManagerConfig.Region,
 			Role:            certManagerConfig.Role,
 			AccessKeyID:     certManagerConfig.AccessKeyID,
 			SecretAccessKey: certManagerConfig.SecretAccessKey,
+			AzureDNS:        certManagerConfig.AzureDNS,
+			Cloudflare:      certManagerConfig.Cloudflare,
+			RFC2136:         certManagerConfig.RFC2136,
 		}
 	}
 
@@ -174,12 +201,25 @@ func GetCMCFromMap(input map[string]string, cluster string, cmcRepository string
 			return nil, fmt.Errorf("failed to get https proxy.\n%w", err)
 		}
 		cmc.MCProxy = MCProxy{
-			Enabled:  true,
-			Hostname: httpsProxy.Hostname,
-			Port:     httpsProxy.Port,
+			Enabled:    true,
+			Hostname:   httpsProxy.Hostname,
+			Port:       httpsProxy.Port,
+			HTTPProxy:  httpsProxy.HTTPProxy,
+			HTTPSProxy: httpsProxy.HTTPSProxy,
+			NoProxy:    httpsProxy.NoProxy,
+			CIDR:       httpsProxy.CIDR,
+			GithubPort: httpsProxy.GithubPort,
 		}
 	}
 
+	if file, ok := data[fmt.Sprintf("%s/%s", path, kustomization.CredentialExpiryFile)]; ok {
+		credentialExpiry, err := credentialexpiry.GetCredentialExpiry(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get credential expiry.\n%w", err)
+		}
+		cmc.CredentialExpiry = credentialExpiry
+	}
+
 	if key.IsProviderVsphere(clusterAppsConfig.Provider) {
 		capvConfig, err := capv.GetCAPVConfig(data[fmt.Sprintf("%s/%s", path, kustomization.VsphereCredentialsFile)])
 		if err != nil {
@@ -246,6 +286,10 @@ func (c *CMC) GetMap(cmcTemplate map[string]string) (map[string]string, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to add mcproxy files.\n%w", err)
 	}
+	cmcTemplate, err = c.GetCredentialExpiry(cmcTemplate, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add credential expiry file.\n%w", err)
+	}
 	if !c.ClusterIntegratesDefaultApps {
 		cmcTemplate, err = c.GetDefaultApps(cmcTemplate, path)
 		if err != nil {
@@ -373,14 +417,10 @@ func (c *CMC) GetSecrets(cmcTemplate map[string]string, path string) (map[string
 
 	// CertManager
 	if c.CertManagerDNSChallenge.Enabled {
-		certManagerFile, err := certmanager.GetCertManagerFile(certmanager.Config{
-			Cluster:          c.Cluster,
-			ClusterNamespace: c.ClusterNamespace,
-			Region:           c.CertManagerDNSChallenge.Region,
-			Role:             c.CertManagerDNSChallenge.Role,
-			AccessKeyID:      c.CertManagerDNSChallenge.AccessKeyID,
-			SecretAccessKey:  c.CertManagerDNSChallenge.SecretAccessKey,
-		})
+		certManagerConfig := c.CertManagerDNSChallenge.GetConfig()
+		certManagerConfig.Cluster = c.Cluster
+		certManagerConfig.ClusterNamespace = c.ClusterNamespace
+		certManagerFile, err := certmanager.GetCertManagerFile(certManagerConfig)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get cert-manager file.\n%w", err)
 		}
@@ -389,9 +429,20 @@ func (c *CMC) GetSecrets(cmcTemplate map[string]string, path string) (map[string
 		delete(cmcTemplate, fmt.Sprintf("%s/%s", path, kustomization.CertManagerFile))
 	}
 
+	// PrivateCA
+	if c.hasPrivateCASecret() {
+		secretFile, err := privateca.GetSecretFile(c.PrivateCA.GetConfig())
+		if err != nil {
+			return nil, fmt.Errorf("failed to get private CA secret file.\n%w", err)
+		}
+		secretMap[fmt.Sprintf("%s/%s", path, kustomization.PrivateCASecretFile)] = secretFile
+	} else {
+		delete(cmcTemplate, fmt.Sprintf("%s/%s", path, kustomization.PrivateCASecretFile))
+	}
+
 	// ConfigureContainerRegistries
 	if c.ConfigureContainerRegistries.Enabled {
-		registryFile, err := registry.GetRegistryFile(c.ConfigureContainerRegistries.Values)
+		registryFile, err := registry.GetRegistryFile(c.ConfigureContainerRegistries.GetConfig())
 		if err != nil {
 			return nil, fmt.Errorf("failed to get registry file.\n%w", err)
 		}
@@ -489,8 +540,11 @@ func (c *CMC) GetProviders(cmcTemplate map[string]string, path string) (map[stri
 
 // PrivateCA
 func (c *CMC) GetPrivateCA(cmcTemplate map[string]string, path string) (map[string]string, error) {
-	if c.PrivateCA {
+	if c.PrivateCA.Enabled {
 		issuerfile := issuer.GetIssuerFile()
+		if c.PrivateCA.Certificate != "" {
+			issuerfile = issuer.GetClusterIssuerFile()
+		}
 		cmcTemplate[fmt.Sprintf("%s/%s", path, kustomization.IssuerFile)] = issuerfile
 		if c.ClusterIntegratesDefaultApps {
 			certmanagerfile := certmanager.GetCertManagerDefaultAppConfigMap()
@@ -500,13 +554,22 @@ func (c *CMC) GetPrivateCA(cmcTemplate map[string]string, path string) (map[stri
 		delete(cmcTemplate, fmt.Sprintf("%s/%s", path, kustomization.IssuerFile))
 		delete(cmcTemplate, fmt.Sprintf("%s/%s", path, kustomization.CertManagerConfigMapFile))
 	}
+	if c.hasPrivateCABundle() {
+		bundleFile, err := privateca.GetBundleFile(c.PrivateCA.GetConfig())
+		if err != nil {
+			return nil, fmt.Errorf("failed to get private CA bundle file.\n%w", err)
+		}
+		cmcTemplate[fmt.Sprintf("%s/%s", path, kustomization.PrivateCABundleFile)] = bundleFile
+	} else {
+		delete(cmcTemplate, fmt.Sprintf("%s/%s", path, kustomization.PrivateCABundleFile))
+	}
 	return cmcTemplate, nil
 }
 
 // CustomCoreDNS
 func (c *CMC) GetCustomCoreDNS(cmcTemplate map[string]string, path string) (map[string]string, error) {
 	if c.CustomCoreDNS.Enabled {
-		coreDNSFile, err := coredns.GetCoreDNSFile(c.CustomCoreDNS.Values)
+		coreDNSFile, err := coredns.GetCoreDNSFile(c.CustomCoreDNS.GetConfig())
 		if err != nil {
 			return nil, fmt.Errorf("failed to get coreDNS file.\n%w", err)
 		}
@@ -586,16 +649,16 @@ func (c *CMC) GetDefaultApps(cmcTemplate map[string]string, path string) (map[st
 // MCProxy
 func (c *CMC) GetMCProxy(cmcTemplate map[string]string, path string) (map[string]string, error) {
 	if c.MCProxy.Enabled {
-		allowNetPolFile := mcproxy.GetAllowNetPolFile(mcproxy.Config{
-			Hostname: c.MCProxy.Hostname,
-			Port:     c.MCProxy.Port,
-		})
+		allowNetPolFile, err := mcproxy.GetAllowNetPolFile(c.MCProxy.GetConfig())
+		if err != nil {
+			return nil, fmt.Errorf("failed to get proxy network policy file.\n%w", err)
+		}
 		cmcTemplate[fmt.Sprintf("%s/%s", path, kustomization.AllowNetPolFile)] = allowNetPolFile
 
-		proxykustomization := mcproxy.GetKustomization(mcproxy.Config{
-			Hostname: c.MCProxy.Hostname,
-			Port:     c.MCProxy.Port,
-		})
+		proxykustomization, err := mcproxy.GetKustomization(c.MCProxy.GetConfig())
+		if err != nil {
+			return nil, fmt.Errorf("failed to get proxy kustomization file.\n%w", err)
+		}
 		cmcTemplate[fmt.Sprintf("%s/%s", path, kustomization.SourceControllerFile)] = proxykustomization
 	} else {
 		delete(cmcTemplate, fmt.Sprintf("%s/%s", path, kustomization.AllowNetPolFile))
@@ -604,12 +667,36 @@ func (c *CMC) GetMCProxy(cmcTemplate map[string]string, path string) (map[string
 	return cmcTemplate, nil
 }
 
+func (c *CMC) hasPrivateCABundle() bool {
+	return c.PrivateCA.Enabled && (c.PrivateCA.Bundle != "" || c.PrivateCA.Certificate != "")
+}
+
+func (c *CMC) hasPrivateCASecret() bool {
+	return c.PrivateCA.Enabled && c.PrivateCA.Certificate != ""
+}
+
+// CredentialExpiry
+func (c *CMC) GetCredentialExpiry(cmcTemplate map[string]string, path string) (map[string]string, error) {
+	if len(c.CredentialExpiry) > 0 {
+		credentialExpiryFile, err := credentialexpiry.GetCredentialExpiryFile(c.CredentialExpiry)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get credential expiry file.\n%w", err)
+		}
+		cmcTemplate[fmt.Sprintf("%s/%s", path, kustomization.CredentialExpiryFile)] = credentialExpiryFile
+	} else {
+		delete(cmcTemplate, fmt.Sprintf("%s/%s", path, kustomization.CredentialExpiryFile))
+	}
+	return cmcTemplate, nil
+}
+
 // Kustomization
 func (c *CMC) GetKustomization(cmcTemplate map[string]string, path string) (map[string]string, error) {
 	kustomizationFile, err := kustomization.GetKustomizationFile(kustomization.Config{
 		CertManagerDNSChallenge:      c.CertManagerDNSChallenge.Enabled,
 		Provider:                     c.Provider.Name,
-		PrivateCA:                    c.PrivateCA,
+		PrivateCA:                    c.PrivateCA.Enabled,
+		PrivateCABundle:              c.hasPrivateCABundle(),
+		PrivateCASecret:              c.hasPrivateCASecret(),
 		PrivateMC:                    c.PrivateMC,
 		IntegratedDefaultAppsValues:  c.ClusterIntegratesDefaultApps,
 		ConfigureContainerRegistries: c.ConfigureContainerRegistries.Enabled,
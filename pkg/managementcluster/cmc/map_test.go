@@ -46,7 +46,7 @@ func TestGetMapFromCMC(t *testing.T) {
 					AppName: "defaultappname-aws",
 				},
 				MCAppsPreventDeletion: true,
-				PrivateCA:             true,
+				PrivateCA:             PrivateCA{Enabled: true},
 				ClusterNamespace:      "clusternamespace",
 				Provider: Provider{
 					Name: key.ProviderAWS,
@@ -119,7 +119,7 @@ func TestGetMapFromCMC(t *testing.T) {
 					AppName: "defaultappname-azure",
 				},
 				MCAppsPreventDeletion: true,
-				PrivateCA:             true,
+				PrivateCA:             PrivateCA{Enabled: true},
 				ClusterNamespace:      "clusternamespace",
 				Provider: Provider{
 					Name: key.ProviderAzure,
@@ -195,7 +195,7 @@ func TestGetMapFromCMC(t *testing.T) {
 				},
 				ClusterIntegratesDefaultApps: true,
 				MCAppsPreventDeletion:        true,
-				PrivateCA:                    true,
+				PrivateCA:                    PrivateCA{Enabled: true},
 				ClusterNamespace:             "clusternamespace",
 				Provider: Provider{
 					Name: key.ProviderAWS,
@@ -261,7 +261,7 @@ func TestGetMapFromCMC(t *testing.T) {
 					AppName: "clusterappname-azure",
 				},
 				MCAppsPreventDeletion:        true,
-				PrivateCA:                    true,
+				PrivateCA:                    PrivateCA{Enabled: true},
 				PrivateMC:                    true,
 				ClusterIntegratesDefaultApps: true,
 				ClusterNamespace:             "clusternamespace",
@@ -345,7 +345,7 @@ func TestGetMapFromCMC(t *testing.T) {
 					AppName: "defaultappname-azure",
 				},
 				MCAppsPreventDeletion: true,
-				PrivateCA:             true,
+				PrivateCA:             PrivateCA{Enabled: true},
 				PrivateMC:             true,
 				ClusterNamespace:      "clusternamespace",
 				Provider: Provider{
@@ -423,7 +423,7 @@ func TestGetMapFromCMC(t *testing.T) {
 				},
 				ClusterIntegratesDefaultApps: true,
 				MCAppsPreventDeletion:        true,
-				PrivateCA:                    true,
+				PrivateCA:                    PrivateCA{Enabled: true},
 				ClusterNamespace:             "clusternamespace",
 				Provider: Provider{
 					Name: key.ProviderAWS,
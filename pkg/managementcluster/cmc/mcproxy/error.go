@@ -0,0 +1,5 @@
+package mcproxy
+
+import "errors"
+
+var ErrInvalidProxy = errors.New("invalid proxy configuration")
@@ -2,25 +2,171 @@ package mcproxy
 
 import (
 	"fmt"
+	"net"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/rs/zerolog/log"
+	"gopkg.in/yaml.v3"
 
 	"github.com/giantswarm/mcli/pkg/key"
 )
 
 type Config struct {
-	Hostname string
-	Port     string
+	Hostname   string
+	Port       string
+	HTTPProxy  string
+	HTTPSProxy string
+	NoProxy    []string
+	CIDR       string
+	GithubPort string
 }
 
 const (
 	ProxyHostnameKey = "proxy_hostname"
 	ProxyPortKey     = "proxy_port"
+	HTTPProxyKey     = "http_proxy"
+	HTTPSProxyKey    = "https_proxy"
+	NoProxyKey       = "no_proxy"
+	ProxyCIDRKey     = "proxy_cidr"
+	GithubPortKey    = "github_port"
 )
 
+// DefaultGithubPort is the port flux uses to reach github through the proxy if no port is configured.
+const (
+	DefaultGithubPort = "8081"
+)
+
+type Kustomization struct {
+	APIVersion string   `yaml:"apiVersion"`
+	Kind       string   `yaml:"kind"`
+	Metadata   Metadata `yaml:"metadata"`
+	Spec       Spec     `yaml:"spec"`
+}
+
+type Metadata struct {
+	Name      string `yaml:"name"`
+	Namespace string `yaml:"namespace"`
+}
+
+type Spec struct {
+	PostBuild PostBuild `yaml:"postBuild"`
+}
+
+type PostBuild struct {
+	Substitute map[string]string `yaml:"substitute"`
+}
+
+type CiliumNetworkPolicy struct {
+	APIVersion string                  `yaml:"apiVersion"`
+	Kind       string                  `yaml:"kind"`
+	Metadata   Metadata                `yaml:"metadata"`
+	Spec       CiliumNetworkPolicySpec `yaml:"spec"`
+}
+
+type CiliumNetworkPolicySpec struct {
+	EndpointSelector map[string]string `yaml:"endpointSelector"`
+	Egress           []Egress          `yaml:"egress"`
+}
+
+type Egress struct {
+	ToEndpoints []Endpoint `yaml:"toEndpoints,omitempty"`
+	ToCIDRSet   []CIDR     `yaml:"toCIDRSet,omitempty"`
+	ToFQDNs     []FQDN     `yaml:"toFQDNs,omitempty"`
+	ToPorts     []ToPorts  `yaml:"toPorts"`
+}
+
+type Endpoint struct {
+	MatchLabels map[string]string `yaml:"matchLabels"`
+}
+
+type CIDR struct {
+	CIDR string `yaml:"cidr"`
+}
+
+type FQDN struct {
+	MatchName string `yaml:"matchName"`
+}
+
+type ToPorts struct {
+	Ports []Port `yaml:"ports"`
+	Rules *Rules `yaml:"rules,omitempty"`
+}
+
+type Port struct {
+	Port     string `yaml:"port"`
+	Protocol string `yaml:"protocol,omitempty"`
+}
+
+type Rules struct {
+	DNS []DNS `yaml:"dns"`
+}
+
+type DNS struct {
+	MatchPattern string `yaml:"matchPattern"`
+}
+
+type ClusterValues struct {
+	Global ClusterValuesGlobal `yaml:"global"`
+}
+
+type ClusterValuesGlobal struct {
+	Connectivity Connectivity `yaml:"connectivity"`
+}
+
+type Connectivity struct {
+	Proxy Proxy `yaml:"proxy"`
+}
+
+type Proxy struct {
+	Enabled    bool     `yaml:"enabled"`
+	HTTPProxy  string   `yaml:"httpProxy,omitempty"`
+	HTTPSProxy string   `yaml:"httpsProxy,omitempty"`
+	NoProxy    *NoProxy `yaml:"noProxy,omitempty"`
+}
+
+type NoProxy struct {
+	Addresses []string `yaml:"addresses"`
+}
+
 func GetHTTPSProxy(kustomizationFile string) (Config, error) {
 	log.Debug().Msg("Getting HTTPS proxy configuration")
 
+	k := Kustomization{}
+	if err := yaml.Unmarshal([]byte(kustomizationFile), &k); err != nil {
+		// older versions of the file are not valid yaml, so we fall back to reading the values directly
+		log.Debug().Msgf("failed to unmarshal proxy kustomization, reading values directly.\n%s", err)
+		return getLegacyHTTPSProxy(kustomizationFile)
+	}
+	substitute := k.Spec.PostBuild.Substitute
+
+	c := Config{
+		Hostname:   substitute[ProxyHostnameKey],
+		Port:       substitute[ProxyPortKey],
+		HTTPProxy:  substitute[HTTPProxyKey],
+		HTTPSProxy: substitute[HTTPSProxyKey],
+		CIDR:       substitute[ProxyCIDRKey],
+		GithubPort: substitute[GithubPortKey],
+	}
+	// the default port is left empty, so configurations without a github port are unchanged
+	if c.GithubPort == DefaultGithubPort {
+		c.GithubPort = ""
+	}
+	if substitute[NoProxyKey] != "" {
+		c.NoProxy = strings.Split(substitute[NoProxyKey], ",")
+	}
+	if c.Hostname == "" {
+		return Config{}, fmt.Errorf("failed to get proxy hostname.\n%w", ErrInvalidProxy)
+	}
+	if c.Port == "" {
+		return Config{}, fmt.Errorf("failed to get proxy port.\n%w", ErrInvalidProxy)
+	}
+	return c, nil
+}
+
+func getLegacyHTTPSProxy(kustomizationFile string) (Config, error) {
 	hostname, err := key.GetValue(ProxyHostnameKey, kustomizationFile)
 	if err != nil {
 		return Config{}, fmt.Errorf("failed to get proxy hostname.\n%w", err)
@@ -32,48 +178,315 @@ func GetHTTPSProxy(kustomizationFile string) (Config, error) {
 	}
 
 	return Config{
-		Hostname: hostname,
-		Port:     port,
+		Hostname: strings.Trim(hostname, `"`),
+		Port:     strings.Trim(port, `"`),
 	}, nil
 }
 
-func GetAllowNetPolFile(c Config) string {
+// GetProxyFromURL returns hostname and port of a proxy URL like http://<hostname>:<port>.
+func GetProxyFromURL(proxyURL string) (string, string, error) {
+	u, err := url.Parse(proxyURL)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to parse proxy url %s.\n%w", proxyURL, err)
+	}
+	if u.Scheme == "" || u.Hostname() == "" {
+		return "", "", fmt.Errorf("proxy url %s has no scheme or hostname. Expected format: http://<hostname>:<port>.\n%w", proxyURL, ErrInvalidProxy)
+	}
+	port := u.Port()
+	if port == "" {
+		switch u.Scheme {
+		case "http":
+			port = "80"
+		case "https":
+			port = "443"
+		default:
+			return "", "", fmt.Errorf("proxy url %s has no port. Expected format: http://<hostname>:<port>.\n%w", proxyURL, ErrInvalidProxy)
+		}
+	}
+	return u.Hostname(), port, nil
+}
+
+func Validate(c Config) error {
+	if c.Hostname == "" {
+		return fmt.Errorf("proxy hostname is empty.\n%w", ErrInvalidProxy)
+	}
+	if c.Port == "" {
+		return fmt.Errorf("proxy port is empty.\n%w", ErrInvalidProxy)
+	}
+	if c.GithubPort != "" {
+		if port, err := strconv.Atoi(c.GithubPort); err != nil || port < 1 || port > 65535 {
+			return fmt.Errorf("github port %s is invalid.\n%w", c.GithubPort, ErrInvalidProxy)
+		}
+	}
+	if c.CIDR != "" {
+		if _, _, err := net.ParseCIDR(c.CIDR); err != nil {
+			return fmt.Errorf("proxy cidr %s is invalid.\n%w", c.CIDR, ErrInvalidProxy)
+		}
+	}
+	for _, u := range []string{c.HTTPProxy, c.HTTPSProxy} {
+		if u == "" {
+			continue
+		}
+		if _, _, err := GetProxyFromURL(u); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func GetAllowNetPolFile(c Config) (string, error) {
 	log.Debug().Msg("Creating CiliumNetworkPolicy for proxy")
 
-	giantswarm := getCiliumNetworkPolicy("giantswarm", c.Hostname, c.Port)
-	kubeSystem := getCiliumNetworkPolicy("kube-system", c.Hostname, c.Port)
-	return fmt.Sprintf("%s\n---\n%s", giantswarm, kubeSystem)
-}
-
-func getCiliumNetworkPolicy(namespace string, hostname string, port string) string {
-	return fmt.Sprintf(`apiVersion: cilium.io/v2
-kind: CiliumNetworkPolicy
-metadata:
-  name: allow-egress-to-proxy
-  namespace: %s
-spec:
-  endpointSelector: {}
-  egress:
-    - toCIDRSet:
-        - cidr: "%s/32"
-      toPorts:
-        - ports:
-            - port: "%s"
-`, namespace, hostname, port)
-}
-
-func GetKustomization(c Config) string {
+	egress, err := getEgress(c)
+	if err != nil {
+		return "", err
+	}
+	giantswarm, err := getCiliumNetworkPolicy("giantswarm", egress)
+	if err != nil {
+		return "", err
+	}
+	kubeSystem, err := getCiliumNetworkPolicy("kube-system", egress)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s---\n%s", giantswarm, kubeSystem), nil
+}
+
+func getCiliumNetworkPolicy(namespace string, egress []Egress) (string, error) {
+	policy := CiliumNetworkPolicy{
+		APIVersion: "cilium.io/v2",
+		Kind:       "CiliumNetworkPolicy",
+		Metadata: Metadata{
+			Name:      "allow-egress-to-proxy",
+			Namespace: namespace,
+		},
+		Spec: CiliumNetworkPolicySpec{
+			EndpointSelector: map[string]string{},
+			Egress:           egress,
+		},
+	}
+	data, err := key.GetData(policy)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal cilium network policy.\n%w", err)
+	}
+	return string(data), nil
+}
+
+// getEgress returns one rule per proxy host with all of its ports.
+// IPs are allowed via toCIDRSet while hostnames are allowed via toFQDNs.
+// If a CIDR is given, it is used instead of the proxy hosts.
+func getEgress(c Config) ([]Egress, error) {
+	hosts, err := getHosts(c)
+	if err != nil {
+		return nil, err
+	}
+
+	var egress []Egress
+	if c.CIDR != "" {
+		var ports []string
+		for _, h := range hosts {
+			ports = appendPorts(ports, h.ports...)
+		}
+		return []Egress{{
+			ToCIDRSet: []CIDR{{CIDR: c.CIDR}},
+			ToPorts:   getPorts(ports),
+		}}, nil
+	}
+
+	fqdn := false
+	for _, h := range hosts {
+		rule := Egress{
+			ToPorts: getPorts(h.ports),
+		}
+		if ip := net.ParseIP(h.hostname); ip != nil {
+			if ip.To4() != nil {
+				rule.ToCIDRSet = []CIDR{{CIDR: fmt.Sprintf("%s/32", h.hostname)}}
+			} else {
+				rule.ToCIDRSet = []CIDR{{CIDR: fmt.Sprintf("%s/128", h.hostname)}}
+			}
+		} else {
+			rule.ToFQDNs = []FQDN{{MatchName: h.hostname}}
+			fqdn = true
+		}
+		egress = append(egress, rule)
+	}
+
+	// toFQDNs rules only work if cilium can see the DNS lookups
+	if fqdn {
+		egress = append(egress, Egress{
+			ToEndpoints: []Endpoint{{
+				MatchLabels: map[string]string{
+					"k8s:io.kubernetes.pod.namespace": "kube-system",
+					"k8s-app":                         "coredns",
+				},
+			}},
+			ToPorts: []ToPorts{{
+				Ports: []Port{
+					{Port: "53", Protocol: "ANY"},
+				},
+				Rules: &Rules{
+					DNS: []DNS{{MatchPattern: "*"}},
+				},
+			}},
+		})
+	}
+	return egress, nil
+}
+
+type host struct {
+	hostname string
+	ports    []string
+}
+
+func getHosts(c Config) ([]host, error) {
+	hosts := []host{{hostname: c.Hostname, ports: []string{c.Port}}}
+	for _, u := range []string{c.HTTPProxy, c.HTTPSProxy} {
+		if u == "" {
+			continue
+		}
+		hostname, port, err := GetProxyFromURL(u)
+		if err != nil {
+			return nil, err
+		}
+		found := false
+		for i := range hosts {
+			if hosts[i].hostname == hostname {
+				hosts[i].ports = appendPorts(hosts[i].ports, port)
+				found = true
+			}
+		}
+		if !found {
+			hosts = append(hosts, host{hostname: hostname, ports: []string{port}})
+		}
+	}
+	return hosts, nil
+}
+
+func appendPorts(ports []string, add ...string) []string {
+	for _, a := range add {
+		found := false
+		for _, p := range ports {
+			if p == a {
+				found = true
+			}
+		}
+		if !found {
+			ports = append(ports, a)
+		}
+	}
+	sort.Strings(ports)
+	return ports
+}
+
+func getPorts(ports []string) []ToPorts {
+	var p []Port
+	for _, port := range ports {
+		p = append(p, Port{Port: port})
+	}
+	return []ToPorts{{Ports: p}}
+}
+
+func GetKustomization(c Config) (string, error) {
 	log.Debug().Msg("Creating Kustomization for proxy")
-	return fmt.Sprintf(`apiVersion: kustomize.toolkit.fluxcd.io/v1
-kind: Kustomization
-metadata:
-  name: flux
-  namespace: flux-giantswarm
-spec:
-  postBuild:
-    substitute:
-      proxy_hostname: %s
-      proxy_port: %s
-	  github_port: "8081"
-`, c.Hostname, c.Port)
+
+	githubPort := c.GithubPort
+	if githubPort == "" {
+		githubPort = DefaultGithubPort
+	}
+	substitute := map[string]string{
+		ProxyHostnameKey: c.Hostname,
+		ProxyPortKey:     c.Port,
+		GithubPortKey:    githubPort,
+	}
+	if c.HTTPProxy != "" {
+		substitute[HTTPProxyKey] = c.HTTPProxy
+	}
+	if c.HTTPSProxy != "" {
+		substitute[HTTPSProxyKey] = c.HTTPSProxy
+	}
+	if len(c.NoProxy) > 0 {
+		substitute[NoProxyKey] = strings.Join(c.NoProxy, ",")
+	}
+	if c.CIDR != "" {
+		substitute[ProxyCIDRKey] = c.CIDR
+	}
+
+	k := Kustomization{
+		APIVersion: "kustomize.toolkit.fluxcd.io/v1",
+		Kind:       "Kustomization",
+		Metadata: Metadata{
+			Name:      "flux",
+			Namespace: key.FluxNamespace,
+		},
+		Spec: Spec{
+			PostBuild: PostBuild{
+				Substitute: substitute,
+			},
+		},
+	}
+	data, err := key.GetData(k)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal proxy kustomization.\n%w", err)
+	}
+	return string(data), nil
+}
+
+// GetClusterValues merges the proxy configuration into the values of the cluster app.
+// The values are returned unchanged if they already contain the same configuration.
+func GetClusterValues(clusterValues string, c Config) (string, error) {
+	log.Debug().Msg("Integrating proxy configuration in cluster values")
+
+	httpsProxy := c.HTTPSProxy
+	if httpsProxy == "" {
+		httpsProxy = fmt.Sprintf("http://%s:%s", c.Hostname, c.Port)
+	}
+	httpProxy := c.HTTPProxy
+	if httpProxy == "" {
+		httpProxy = httpsProxy
+	}
+	values := ClusterValues{
+		Global: ClusterValuesGlobal{
+			Connectivity: Connectivity{
+				Proxy: Proxy{
+					Enabled:    true,
+					HTTPProxy:  httpProxy,
+					HTTPSProxy: httpsProxy,
+				},
+			},
+		},
+	}
+	if len(c.NoProxy) > 0 {
+		values.Global.Connectivity.Proxy.NoProxy = &NoProxy{
+			Addresses: c.NoProxy,
+		}
+	}
+
+	data, err := key.GetData(values)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal proxy values.\n%w", err)
+	}
+	// previous settings such as no proxy addresses are removed before merging
+	base, err := RemoveClusterValues(clusterValues)
+	if err != nil {
+		return "", err
+	}
+	merged, err := key.MergeValues(base, string(data))
+	if err != nil {
+		return "", fmt.Errorf("failed to merge proxy values in cluster values.\n%w", err)
+	}
+	return key.KeepUnchangedValues(clusterValues, merged)
+}
+
+// RemoveClusterValues removes the proxy configuration set by GetClusterValues from the values of the cluster app.
+func RemoveClusterValues(clusterValues string) (string, error) {
+	log.Debug().Msg("Removing proxy configuration from cluster values")
+
+	var err error
+	for _, k := range []string{"enabled", "httpProxy", "httpsProxy", "noProxy"} {
+		clusterValues, err = key.RemoveValue(clusterValues, "global", "connectivity", "proxy", k)
+		if err != nil {
+			return "", fmt.Errorf("failed to remove proxy values from cluster values.\n%w", err)
+		}
+	}
+	return clusterValues, nil
 }
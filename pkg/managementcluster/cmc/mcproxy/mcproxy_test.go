@@ -0,0 +1,291 @@
+package mcproxy
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGetHTTPSProxy(t *testing.T) {
+	testCases := []struct {
+		name   string
+		config Config
+
+		expectedGithubPort string
+	}{
+		{
+			name: "hostname and port",
+			config: Config{
+				Hostname: "proxy.example.com",
+				Port:     "3128",
+			},
+			expectedGithubPort: DefaultGithubPort,
+		},
+		{
+			name: "full configuration",
+			config: Config{
+				Hostname:   "10.0.0.1",
+				Port:       "3128",
+				HTTPProxy:  "http://10.0.0.1:3129",
+				HTTPSProxy: "http://10.0.0.1:3128",
+				NoProxy:    []string{"localhost", "10.0.0.0/8", ".svc"},
+				CIDR:       "10.0.0.0/24",
+				GithubPort: "8443",
+			},
+			expectedGithubPort: "8443",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			file, err := GetKustomization(tc.config)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !strings.Contains(file, fmt.Sprintf("%s: \"%s\"", GithubPortKey, tc.expectedGithubPort)) {
+				t.Fatalf("expected github port %s in %s", tc.expectedGithubPort, file)
+			}
+			output, err := GetHTTPSProxy(file)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(output, tc.config) {
+				t.Fatalf("expected %v but got %v", tc.config, output)
+			}
+		})
+	}
+}
+
+func TestGetHTTPSProxyLegacy(t *testing.T) {
+	file := "apiVersion: kustomize.toolkit.fluxcd.io/v1\nkind: Kustomization\nmetadata:\n  name: flux\n  namespace: flux-giantswarm\nspec:\n  postBuild:\n    substitute:\n      proxy_hostname: 10.0.0.1\n      proxy_port: 3128\n\t  github_port: \"8081\"\n"
+	output, err := GetHTTPSProxy(file)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := Config{Hostname: "10.0.0.1", Port: "3128"}
+	if !reflect.DeepEqual(output, expected) {
+		t.Fatalf("expected %v but got %v", expected, output)
+	}
+}
+
+func TestGetProxyFromURL(t *testing.T) {
+	testCases := []struct {
+		name string
+		url  string
+
+		expectErr      bool
+		expectHostname string
+		expectPort     string
+	}{
+		{
+			name:           "hostname with port",
+			url:            "http://proxy.example.com:3128",
+			expectHostname: "proxy.example.com",
+			expectPort:     "3128",
+		},
+		{
+			name:           "ip without port",
+			url:            "https://10.0.0.1",
+			expectHostname: "10.0.0.1",
+			expectPort:     "443",
+		},
+		{
+			name:      "missing scheme",
+			url:       "proxy.example.com:3128",
+			expectErr: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			hostname, port, err := GetProxyFromURL(tc.url)
+			if tc.expectErr {
+				if err == nil {
+					t.Fatalf("expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error but got %v", err)
+			}
+			if hostname != tc.expectHostname || port != tc.expectPort {
+				t.Fatalf("expected %s:%s but got %s:%s", tc.expectHostname, tc.expectPort, hostname, port)
+			}
+		})
+	}
+}
+
+func TestGetAllowNetPolFile(t *testing.T) {
+	testCases := []struct {
+		name   string
+		config Config
+
+		expectContains    []string
+		expectNotContains []string
+	}{
+		{
+			name: "ip",
+			config: Config{
+				Hostname: "10.0.0.1",
+				Port:     "3128",
+			},
+			expectContains:    []string{"cidr: 10.0.0.1/32", "port: \"3128\""},
+			expectNotContains: []string{"toFQDNs"},
+		},
+		{
+			name: "hostname with multiple ports",
+			config: Config{
+				Hostname:  "proxy.example.com",
+				Port:      "3128",
+				HTTPProxy: "http://proxy.example.com:8080",
+			},
+			expectContains:    []string{"matchName: proxy.example.com", "port: \"3128\"", "port: \"8080\"", "matchPattern: '*'"},
+			expectNotContains: []string{"toCIDRSet"},
+		},
+		{
+			name: "cidr",
+			config: Config{
+				Hostname: "proxy.example.com",
+				Port:     "3128",
+				CIDR:     "10.0.0.0/24",
+			},
+			expectContains:    []string{"cidr: 10.0.0.0/24"},
+			expectNotContains: []string{"toFQDNs"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			output, err := GetAllowNetPolFile(tc.config)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			for _, c := range tc.expectContains {
+				if !strings.Contains(output, c) {
+					t.Fatalf("expected %q in %s", c, output)
+				}
+			}
+			for _, c := range tc.expectNotContains {
+				if strings.Contains(output, c) {
+					t.Fatalf("expected no %q in %s", c, output)
+				}
+			}
+		})
+	}
+}
+
+func TestGetClusterValues(t *testing.T) {
+	config := Config{
+		Hostname: "proxy.example.com",
+		Port:     "3128",
+		NoProxy:  []string{"localhost"},
+	}
+	configured := `# cluster values
+global:
+  connectivity:
+    proxy:
+      enabled: true
+      httpProxy: http://proxy.example.com:3128
+      httpsProxy: http://proxy.example.com:3128
+      noProxy:
+        addresses:
+          - localhost
+  metadata:
+    name: test # name of the cluster
+`
+	var testCases = []struct {
+		name   string
+		values string
+		config Config
+
+		expected string
+	}{
+		{
+			name:   "case 0: merge proxy configuration",
+			values: "global:\n  metadata:\n    name: test\n",
+			config: config,
+			expected: `global:
+  connectivity:
+    proxy:
+      enabled: true
+      httpProxy: http://proxy.example.com:3128
+      httpsProxy: http://proxy.example.com:3128
+      noProxy:
+        addresses:
+          - localhost
+  metadata:
+    name: test
+`,
+		},
+		{
+			name:     "case 1: unchanged configuration keeps comments",
+			values:   configured,
+			config:   config,
+			expected: configured,
+		},
+		{
+			name:   "case 2: removed no proxy addresses",
+			values: configured,
+			config: Config{
+				Hostname: "proxy.example.com",
+				Port:     "3128",
+			},
+			expected: `global:
+  connectivity:
+    proxy:
+      enabled: true
+      httpProxy: http://proxy.example.com:3128
+      httpsProxy: http://proxy.example.com:3128
+  metadata:
+    name: test
+`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			values, err := GetClusterValues(tc.values, tc.config)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if values != tc.expected {
+				t.Fatalf("expected %s but got %s", tc.expected, values)
+			}
+		})
+	}
+}
+
+func TestRemoveClusterValues(t *testing.T) {
+	var testCases = []struct {
+		name   string
+		values string
+
+		expected string
+	}{
+		{
+			name:     "case 0: remove proxy configuration",
+			values:   "# cluster values\nglobal:\n  connectivity:\n    proxy:\n      enabled: true\n      httpProxy: http://proxy.example.com:3128\n      httpsProxy: http://proxy.example.com:3128\n  metadata:\n    name: test # name of the cluster\n",
+			expected: "# cluster values\nglobal:\n  metadata:\n    name: test # name of the cluster\n",
+		},
+		{
+			name:     "case 1: other connectivity settings are kept",
+			values:   "global:\n  connectivity:\n    baseDomain: example.com\n    proxy:\n      enabled: true\n",
+			expected: "global:\n  connectivity:\n    baseDomain: example.com\n",
+		},
+		{
+			name:     "case 2: values without proxy configuration are unchanged",
+			values:   "global:\n    metadata:\n        name: test\n",
+			expected: "global:\n    metadata:\n        name: test\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			values, err := RemoveClusterValues(tc.values)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if values != tc.expected {
+				t.Fatalf("expected %q but got %q", tc.expected, values)
+			}
+		})
+	}
+}
@@ -0,0 +1,5 @@
+package privateca
+
+import "errors"
+
+var ErrInvalidCertificate = errors.New("invalid certificate")
@@ -0,0 +1,299 @@
+package privateca
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"gopkg.in/yaml.v3"
+
+	"github.com/giantswarm/mcli/pkg/key"
+)
+
+const (
+	SecretName     = "private-giantswarm-secret" // #nosec G101
+	BundleName     = "private-ca-bundle"
+	Namespace      = "kube-system"
+	BundleKey      = "ca.crt"
+	CertificateKey = "tls.crt"
+	PrivateKeyKey  = "tls.key"
+)
+
+// ContainerdValuesKey is the key in the cluster values under which containerd expects additional trusted CAs.
+// It has to match global.components.containerd.trustedCertificateAuthorities in the values schema of the cluster chart.
+const ContainerdValuesKey = "trustedCertificateAuthorities"
+
+type Config struct {
+	Bundle      string
+	Certificate string
+	Key         string
+}
+
+type CertificateInfo struct {
+	Source       string    `yaml:"source"`
+	Subject      string    `yaml:"subject"`
+	Issuer       string    `yaml:"issuer"`
+	NotBefore    time.Time `yaml:"notBefore"`
+	NotAfter     time.Time `yaml:"notAfter"`
+	DaysToExpiry int       `yaml:"daysToExpiry"`
+}
+
+type ConfigMap struct {
+	APIVersion string            `yaml:"apiVersion"`
+	Kind       string            `yaml:"kind"`
+	Metadata   Metadata          `yaml:"metadata"`
+	Data       map[string]string `yaml:"data"`
+}
+
+type Secret struct {
+	APIVersion string            `yaml:"apiVersion"`
+	Kind       string            `yaml:"kind"`
+	Type       string            `yaml:"type"`
+	Metadata   Metadata          `yaml:"metadata"`
+	Data       map[string]string `yaml:"data"`
+}
+
+type Metadata struct {
+	Name      string `yaml:"name"`
+	Namespace string `yaml:"namespace"`
+}
+
+type ClusterValues struct {
+	Global Global `yaml:"global"`
+}
+
+type Global struct {
+	Components Components `yaml:"components"`
+}
+
+type Components struct {
+	Containerd map[string]string `yaml:"containerd"`
+}
+
+// GetCertificates returns subject and expiry of all certificates in a PEM bundle.
+func GetCertificates(bundle string, source string) ([]CertificateInfo, error) {
+	certificates, err := parseCertificates(bundle)
+	if err != nil {
+		return nil, err
+	}
+	var infos []CertificateInfo
+	for _, c := range certificates {
+		infos = append(infos, CertificateInfo{
+			Source:       source,
+			Subject:      c.Subject.String(),
+			Issuer:       c.Issuer.String(),
+			NotBefore:    c.NotBefore.UTC(),
+			NotAfter:     c.NotAfter.UTC(),
+			DaysToExpiry: int(time.Until(c.NotAfter).Hours() / 24),
+		})
+	}
+	return infos, nil
+}
+
+// ValidateBundle ensures that the bundle only contains CA certificates that have not expired.
+func ValidateBundle(bundle string) error {
+	certificates, err := parseCertificates(bundle)
+	if err != nil {
+		return err
+	}
+	if len(certificates) == 0 {
+		return fmt.Errorf("no certificates found.\n%w", ErrInvalidCertificate)
+	}
+	for _, c := range certificates {
+		if !c.IsCA {
+			return fmt.Errorf("certificate %s is not a CA.\n%w", c.Subject.String(), ErrInvalidCertificate)
+		}
+		if time.Now().After(c.NotAfter) {
+			return fmt.Errorf("certificate %s expired on %s.\n%w", c.Subject.String(), c.NotAfter.UTC().Format(time.RFC3339), ErrInvalidCertificate)
+		}
+	}
+	return nil
+}
+
+// ValidateKeyPair ensures that the private key belongs to the certificate.
+func ValidateKeyPair(certificate string, privateKey string) error {
+	if _, err := tls.X509KeyPair([]byte(certificate), []byte(privateKey)); err != nil {
+		return fmt.Errorf("private key does not match certificate.\n%w", ErrInvalidCertificate)
+	}
+	return nil
+}
+
+func parseCertificates(bundle string) ([]*x509.Certificate, error) {
+	var certificates []*x509.Certificate
+	rest := []byte(bundle)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			return nil, fmt.Errorf("unexpected PEM block of type %s.\n%w", block.Type, ErrInvalidCertificate)
+		}
+		c, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse certificate.\n%w", err)
+		}
+		certificates = append(certificates, c)
+	}
+	if strings.TrimSpace(string(rest)) != "" {
+		return nil, fmt.Errorf("bundle contains data that is not PEM encoded.\n%w", ErrInvalidCertificate)
+	}
+	return certificates, nil
+}
+
+// GetTrustBundle returns the issuer certificate and the bundle as one PEM bundle.
+func GetTrustBundle(c Config) string {
+	var bundle []string
+	for _, b := range []string{c.Certificate, c.Bundle} {
+		if strings.TrimSpace(b) != "" {
+			bundle = append(bundle, strings.TrimSpace(b))
+		}
+	}
+	if len(bundle) == 0 {
+		return ""
+	}
+	return strings.Join(bundle, "\n") + "\n"
+}
+
+func GetBundleFile(c Config) (string, error) {
+	log.Debug().Msg("Creating private CA bundle ConfigMap")
+
+	configMap := ConfigMap{
+		APIVersion: "v1",
+		Kind:       "ConfigMap",
+		Metadata: Metadata{
+			Name:      BundleName,
+			Namespace: Namespace,
+		},
+		Data: map[string]string{
+			BundleKey: GetTrustBundle(c),
+		},
+	}
+	data, err := key.GetData(configMap)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal private CA bundle.\n%w", err)
+	}
+	return string(data), nil
+}
+
+// GetBundle returns the bundle of the ConfigMap without the issuer certificate.
+func GetBundle(file string, certificate string) (string, error) {
+	log.Debug().Msg("Getting private CA bundle")
+
+	configMap := ConfigMap{}
+	if err := yaml.Unmarshal([]byte(file), &configMap); err != nil {
+		return "", fmt.Errorf("failed to unmarshal private CA bundle.\n%w", err)
+	}
+	bundle := configMap.Data[BundleKey]
+	if strings.TrimSpace(certificate) == "" {
+		return bundle, nil
+	}
+
+	var remaining []byte
+	rest := []byte(bundle)
+	skip := []byte(strings.TrimSpace(certificate))
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		encoded := bytes.TrimSpace(pem.EncodeToMemory(block))
+		if bytes.Equal(encoded, skip) {
+			continue
+		}
+		remaining = append(remaining, encoded...)
+		remaining = append(remaining, '\n')
+	}
+	return string(remaining), nil
+}
+
+func GetSecretFile(c Config) (string, error) {
+	log.Debug().Msg("Creating private CA issuer Secret")
+
+	secret := Secret{
+		APIVersion: "v1",
+		Kind:       "Secret",
+		Type:       "kubernetes.io/tls",
+		Metadata: Metadata{
+			Name:      SecretName,
+			Namespace: Namespace,
+		},
+		Data: map[string]string{
+			CertificateKey: base64.StdEncoding.EncodeToString([]byte(c.Certificate)),
+			PrivateKeyKey:  base64.StdEncoding.EncodeToString([]byte(c.Key)),
+			BundleKey:      base64.StdEncoding.EncodeToString([]byte(c.Certificate)),
+		},
+	}
+	data, err := key.GetData(secret)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal private CA secret.\n%w", err)
+	}
+	return string(data), nil
+}
+
+// GetSecret returns certificate and private key of the issuer Secret.
+func GetSecret(file string) (string, string, error) {
+	log.Debug().Msg("Getting private CA issuer secret")
+
+	secret := Secret{}
+	if err := yaml.Unmarshal([]byte(file), &secret); err != nil {
+		return "", "", fmt.Errorf("failed to unmarshal private CA secret.\n%w", err)
+	}
+	certificate, err := base64.StdEncoding.DecodeString(secret.Data[CertificateKey])
+	if err != nil {
+		return "", "", fmt.Errorf("failed to decode private CA certificate.\n%w", err)
+	}
+	privateKey, err := base64.StdEncoding.DecodeString(secret.Data[PrivateKeyKey])
+	if err != nil {
+		return "", "", fmt.Errorf("failed to decode private CA key.\n%w", err)
+	}
+	return string(certificate), string(privateKey), nil
+}
+
+// GetClusterValues merges the trust bundle into the containerd configuration of the cluster values.
+// The values are returned unchanged if they already contain the bundle.
+func GetClusterValues(clusterValues string, c Config) (string, error) {
+	log.Debug().Msg("Integrating private CA bundle in cluster values")
+
+	bundle := GetTrustBundle(c)
+	if bundle == "" {
+		return RemoveClusterValues(clusterValues)
+	}
+	values := ClusterValues{
+		Global: Global{
+			Components: Components{
+				Containerd: map[string]string{
+					ContainerdValuesKey: bundle,
+				},
+			},
+		},
+	}
+	data, err := key.GetData(values)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal private CA values.\n%w", err)
+	}
+	merged, err := key.MergeValues(clusterValues, string(data))
+	if err != nil {
+		return "", fmt.Errorf("failed to merge private CA values in cluster values.\n%w", err)
+	}
+	return key.KeepUnchangedValues(clusterValues, merged)
+}
+
+// RemoveClusterValues removes the trust bundle set by GetClusterValues from the cluster values.
+func RemoveClusterValues(clusterValues string) (string, error) {
+	log.Debug().Msg("Removing private CA bundle from cluster values")
+
+	clusterValues, err := key.RemoveValue(clusterValues, "global", "components", "containerd", ContainerdValuesKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to remove private CA values from cluster values.\n%w", err)
+	}
+	return clusterValues, nil
+}
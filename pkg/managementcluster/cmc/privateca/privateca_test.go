@@ -0,0 +1,243 @@
+package privateca
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestValidateBundle(t *testing.T) {
+	ca, _ := getTestCertificate(t, "ca", true, time.Now().Add(24*time.Hour))
+	otherCA, _ := getTestCertificate(t, "other-ca", true, time.Now().Add(48*time.Hour))
+	leaf, _ := getTestCertificate(t, "leaf", false, time.Now().Add(24*time.Hour))
+	expired, _ := getTestCertificate(t, "expired", true, time.Now().Add(-24*time.Hour))
+
+	testCases := []struct {
+		name   string
+		bundle string
+
+		expectErr bool
+	}{
+		{
+			name:   "single CA",
+			bundle: ca,
+		},
+		{
+			name:   "multiple CAs",
+			bundle: ca + otherCA,
+		},
+		{
+			name:      "empty bundle",
+			bundle:    "",
+			expectErr: true,
+		},
+		{
+			name:      "no CA",
+			bundle:    leaf,
+			expectErr: true,
+		},
+		{
+			name:      "expired CA",
+			bundle:    ca + expired,
+			expectErr: true,
+		},
+		{
+			name:      "not PEM encoded",
+			bundle:    ca + "garbage",
+			expectErr: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateBundle(tc.bundle)
+			if tc.expectErr && err == nil {
+				t.Fatalf("expected error but got none")
+			} else if !tc.expectErr && err != nil {
+				t.Fatalf("expected no error but got %v", err)
+			}
+		})
+	}
+}
+
+func TestValidateKeyPair(t *testing.T) {
+	ca, caKey := getTestCertificate(t, "ca", true, time.Now().Add(24*time.Hour))
+	_, otherKey := getTestCertificate(t, "other-ca", true, time.Now().Add(24*time.Hour))
+
+	if err := ValidateKeyPair(ca, caKey); err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+	if err := ValidateKeyPair(ca, otherKey); err == nil {
+		t.Fatalf("expected error but got none")
+	}
+}
+
+func TestGetCertificates(t *testing.T) {
+	ca, _ := getTestCertificate(t, "ca", true, time.Now().Add(10*24*time.Hour+time.Hour))
+
+	infos, err := GetCertificates(ca, "bundle")
+	if err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+	if len(infos) != 1 {
+		t.Fatalf("expected 1 certificate but got %d", len(infos))
+	}
+	if infos[0].Subject != "CN=ca" || infos[0].Source != "bundle" || infos[0].DaysToExpiry != 10 {
+		t.Fatalf("unexpected certificate info %v", infos[0])
+	}
+}
+
+func TestGetBundle(t *testing.T) {
+	ca, _ := getTestCertificate(t, "ca", true, time.Now().Add(24*time.Hour))
+	issuer, _ := getTestCertificate(t, "issuer", true, time.Now().Add(24*time.Hour))
+
+	testCases := []struct {
+		name   string
+		config Config
+	}{
+		{
+			name:   "bundle only",
+			config: Config{Bundle: ca},
+		},
+		{
+			name:   "bundle and issuer certificate",
+			config: Config{Bundle: ca, Certificate: issuer},
+		},
+		{
+			name:   "issuer certificate only",
+			config: Config{Certificate: issuer},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			file, err := GetBundleFile(tc.config)
+			if err != nil {
+				t.Fatalf("expected no error but got %v", err)
+			}
+			bundle, err := GetBundle(file, tc.config.Certificate)
+			if err != nil {
+				t.Fatalf("expected no error but got %v", err)
+			}
+			if bundle != tc.config.Bundle {
+				t.Fatalf("expected %q but got %q", tc.config.Bundle, bundle)
+			}
+		})
+	}
+}
+
+func TestGetSecret(t *testing.T) {
+	ca, caKey := getTestCertificate(t, "ca", true, time.Now().Add(24*time.Hour))
+
+	file, err := GetSecretFile(Config{Certificate: ca, Key: caKey})
+	if err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+	certificate, privateKey, err := GetSecret(file)
+	if err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+	if certificate != ca || privateKey != caKey {
+		t.Fatalf("expected certificate and key to round-trip")
+	}
+}
+
+func TestGetClusterValues(t *testing.T) {
+	ca, _ := getTestCertificate(t, "ca", true, time.Now().Add(24*time.Hour))
+
+	values, err := GetClusterValues("global:\n  metadata:\n    name: test\n", Config{Bundle: ca})
+	if err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+	if !strings.Contains(values, "name: test") {
+		t.Fatalf("unexpected values %s", values)
+	}
+	// the bundle is set at the path of the values schema of the cluster chart
+	var parsed struct {
+		Global struct {
+			Components struct {
+				Containerd struct {
+					TrustedCertificateAuthorities string `yaml:"trustedCertificateAuthorities"`
+				} `yaml:"containerd"`
+			} `yaml:"components"`
+		} `yaml:"global"`
+	}
+	if err := yaml.Unmarshal([]byte(values), &parsed); err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+	if parsed.Global.Components.Containerd.TrustedCertificateAuthorities != GetTrustBundle(Config{Bundle: ca}) {
+		t.Fatalf("expected trust bundle at global.components.containerd.trustedCertificateAuthorities but got %s", values)
+	}
+
+	// the values are not rewritten if the bundle is already set
+	commented := "# cluster values\n" + values
+	unchanged, err := GetClusterValues(commented, Config{Bundle: ca})
+	if err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+	if unchanged != commented {
+		t.Fatalf("expected %s but got %s", commented, unchanged)
+	}
+
+	removed, err := GetClusterValues(commented, Config{})
+	if err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+	if expected := "# cluster values\nglobal:\n  metadata:\n    name: test\n"; removed != expected {
+		t.Fatalf("expected %q but got %q", expected, removed)
+	}
+}
+
+func TestRemoveClusterValues(t *testing.T) {
+	values := "global:\n  components:\n    containerd:\n      trustedCertificateAuthorities: bundle\n      other: value\n"
+	removed, err := RemoveClusterValues(values)
+	if err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+	if expected := "global:\n  components:\n    containerd:\n      other: value\n"; removed != expected {
+		t.Fatalf("expected %q but got %q", expected, removed)
+	}
+
+	unchanged, err := RemoveClusterValues("global:\n    metadata:\n        name: test\n")
+	if err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+	if unchanged != "global:\n    metadata:\n        name: test\n" {
+		t.Fatalf("expected values to be unchanged but got %q", unchanged)
+	}
+}
+
+func getTestCertificate(t *testing.T, name string, isCA bool, notAfter time.Time) (string, string) {
+	t.Helper()
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             notAfter.Add(-365 * 24 * time.Hour),
+		NotAfter:              notAfter,
+		IsCA:                  isCA,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &privateKey.PublicKey, privateKey)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(privateKey)
+	if err != nil {
+		t.Fatalf("failed to marshal key: %v", err)
+	}
+	certificate := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	key := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	return string(certificate), string(key)
+}
@@ -0,0 +1,7 @@
+package registry
+
+import "errors"
+
+var ErrInvalidRegistry = errors.New("invalid container registry configuration")
+
+var ErrNotFound = errors.New("container registry not found")
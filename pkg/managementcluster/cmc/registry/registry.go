@@ -1,6 +1,9 @@
 package registry
 
 import (
+	"encoding/base64"
+	"fmt"
+
 	"github.com/rs/zerolog/log"
 
 	"github.com/giantswarm/mcli/pkg/key"
@@ -20,17 +23,134 @@ data:
   values.yaml: {{ .Values }}
 `
 
+// Config is the container registry configuration of a management cluster.
+// Registries are rendered into containerd values, Raw values are merged underneath.
 type Config struct {
+	Registries []Registry
+	Raw        string
+}
+
+// Registry is a container registry with the endpoints (mirrors) to pull from in order.
+type Registry struct {
+	Name      string     `yaml:"name"`
+	Endpoints []Endpoint `yaml:"endpoints"`
+}
+
+// Endpoint authenticates either with username and password or with a token.
+type Endpoint struct {
+	Endpoint string `yaml:"endpoint"`
+	Username string `yaml:"username,omitempty"`
+	Password string `yaml:"password,omitempty"`
+	Token    string `yaml:"token,omitempty"`
+}
+
+type secret struct {
 	Values string
 }
 
-func GetRegistryConfig(file string) (string, error) {
+func (c Config) IsEmpty() bool {
+	return len(c.Registries) == 0 && c.Raw == ""
+}
+
+func GetRegistryConfig(file string) (Config, error) {
 	log.Debug().Msg("Getting registry config")
 
-	return key.GetSecretValue(ValuesKey, file)
+	values, err := key.GetSecretValue(ValuesKey, file)
+	if err != nil {
+		return Config{}, err
+	}
+	return GetConfig(values), nil
 }
 
-func GetRegistryFile(values string) (string, error) {
+func GetRegistryFile(c Config) (string, error) {
 	log.Debug().Msg("Creating container-registries-configuration Secret")
-	return template.Execute(RegistryTemplate, Config{Values: values})
+
+	values, err := GetValues(c)
+	if err != nil {
+		return "", err
+	}
+	return template.Execute(RegistryTemplate, secret{Values: base64.StdEncoding.EncodeToString([]byte(values))})
+}
+
+// Add adds the endpoint to the registry. An endpoint with the same address is replaced.
+func Add(registries []Registry, name string, endpoint Endpoint) []Registry {
+	result := copyRegistries(registries)
+	for i, r := range result {
+		if r.Name != name {
+			continue
+		}
+		for j, e := range r.Endpoints {
+			if e.Endpoint == endpoint.Endpoint {
+				result[i].Endpoints[j] = endpoint
+				return result
+			}
+		}
+		result[i].Endpoints = append(result[i].Endpoints, endpoint)
+		return result
+	}
+	result = append(result, Registry{Name: name, Endpoints: []Endpoint{endpoint}})
+	sortRegistries(result)
+	return result
+}
+
+// Remove removes the endpoint from the registry. The registry is removed if endpoint is empty or it has no endpoints left.
+func Remove(registries []Registry, name string, endpoint string) ([]Registry, error) {
+	var result []Registry
+	found := false
+	for _, r := range copyRegistries(registries) {
+		if r.Name != name {
+			result = append(result, r)
+			continue
+		}
+		if endpoint == "" {
+			found = true
+			continue
+		}
+		var endpoints []Endpoint
+		for _, e := range r.Endpoints {
+			if e.Endpoint == endpoint {
+				found = true
+				continue
+			}
+			endpoints = append(endpoints, e)
+		}
+		if len(endpoints) > 0 {
+			r.Endpoints = endpoints
+			result = append(result, r)
+		}
+	}
+	if !found {
+		if endpoint == "" {
+			return nil, fmt.Errorf("registry %s\n%w", name, ErrNotFound)
+		}
+		return nil, fmt.Errorf("endpoint %s of registry %s\n%w", endpoint, name, ErrNotFound)
+	}
+	return result, nil
+}
+
+// Redact replaces the credentials of all endpoints.
+func Redact(registries []Registry, redacted string) []Registry {
+	result := copyRegistries(registries)
+	for i := range result {
+		for j := range result[i].Endpoints {
+			if result[i].Endpoints[j].Password != "" {
+				result[i].Endpoints[j].Password = redacted
+			}
+			if result[i].Endpoints[j].Token != "" {
+				result[i].Endpoints[j].Token = redacted
+			}
+		}
+	}
+	return result
+}
+
+func copyRegistries(registries []Registry) []Registry {
+	if registries == nil {
+		return nil
+	}
+	result := make([]Registry, len(registries))
+	for i, r := range registries {
+		result[i] = Registry{Name: r.Name, Endpoints: append([]Endpoint{}, r.Endpoints...)}
+	}
+	return result
 }
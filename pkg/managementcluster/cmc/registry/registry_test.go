@@ -0,0 +1,182 @@
+package registry
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGetRegistryFile(t *testing.T) {
+	testCases := []struct {
+		name   string
+		config Config
+	}{
+		{
+			name: "case 0: registries with credentials",
+			config: Config{
+				Registries: []Registry{
+					{
+						Name: "docker.io",
+						Endpoints: []Endpoint{
+							{Endpoint: "registry-1.docker.io", Username: "user", Password: "password"},
+							{Endpoint: "mirror.example.com:5000"},
+						},
+					},
+					{
+						Name:      "gsoci.azurecr.io",
+						Endpoints: []Endpoint{{Endpoint: "gsoci.azurecr.io", Token: "token"}},
+					},
+				},
+			},
+		},
+		{
+			name: "case 1: raw values",
+			config: Config{
+				Raw: "global:\n  components:\n    containerd:\n      localRegistryCache:\n        enabled: true\n",
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			file, err := GetRegistryFile(tc.config)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			config, err := GetRegistryConfig(file)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(config, tc.config) {
+				t.Fatalf("expected %v but got %v", tc.config, config)
+			}
+			if err := Validate(config); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestGetValues(t *testing.T) {
+	config := Config{
+		Registries: []Registry{
+			{Name: "docker.io", Endpoints: []Endpoint{{Endpoint: "registry-1.docker.io", Username: "user", Password: "password"}}},
+		},
+		Raw: "global:\n  components:\n    containerd:\n      localRegistryCache:\n        enabled: true\n",
+	}
+	values, err := GetValues(config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, s := range []string{"localRegistryCache", "containerRegistries", "registry-1.docker.io", "password: password"} {
+		if !strings.Contains(values, s) {
+			t.Fatalf("expected values to contain %s, got\n%s", s, values)
+		}
+	}
+	// registries merged with other values are kept raw
+	if parsed := GetConfig(values); parsed.Raw != values || len(parsed.Registries) != 0 {
+		t.Fatalf("expected raw values, got %v", parsed)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	testCases := []struct {
+		name        string
+		config      Config
+		expectError bool
+	}{
+		{
+			name: "case 0: valid",
+			config: Config{Registries: []Registry{
+				{Name: "docker.io", Endpoints: []Endpoint{{Endpoint: "https://10.0.0.1:5000/v2"}, {Endpoint: "registry-1.docker.io"}}},
+			}},
+		},
+		{
+			name:        "case 1: token and password",
+			config:      Config{Registries: []Registry{{Name: "docker.io", Endpoints: []Endpoint{{Endpoint: "registry-1.docker.io", Username: "user", Password: "password", Token: "token"}}}}},
+			expectError: true,
+		},
+		{
+			name:        "case 2: username without password",
+			config:      Config{Registries: []Registry{{Name: "docker.io", Endpoints: []Endpoint{{Endpoint: "registry-1.docker.io", Username: "user"}}}}},
+			expectError: true,
+		},
+		{
+			name:        "case 3: invalid endpoint",
+			config:      Config{Registries: []Registry{{Name: "docker.io", Endpoints: []Endpoint{{Endpoint: "ftp://registry"}}}}},
+			expectError: true,
+		},
+		{
+			name:        "case 4: invalid registry name",
+			config:      Config{Registries: []Registry{{Name: "docker io", Endpoints: []Endpoint{{Endpoint: "registry-1.docker.io"}}}}},
+			expectError: true,
+		},
+		{
+			name:        "case 5: registry without endpoints",
+			config:      Config{Registries: []Registry{{Name: "docker.io"}}},
+			expectError: true,
+		},
+		{
+			name:        "case 6: invalid raw values",
+			config:      Config{Raw: "global: ["},
+			expectError: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := Validate(tc.config)
+			if tc.expectError && !errors.Is(err, ErrInvalidRegistry) {
+				t.Fatalf("expected invalid registry error, got %v", err)
+			}
+			if !tc.expectError && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestAddRemove(t *testing.T) {
+	registries := []Registry{
+		{Name: "quay.io", Endpoints: []Endpoint{{Endpoint: "quay.io"}}},
+	}
+
+	registries = Add(registries, "docker.io", Endpoint{Endpoint: "registry-1.docker.io"})
+	registries = Add(registries, "docker.io", Endpoint{Endpoint: "mirror.example.com", Token: "token"})
+	registries = Add(registries, "docker.io", Endpoint{Endpoint: "registry-1.docker.io", Username: "user", Password: "password"})
+	expected := []Registry{
+		{Name: "docker.io", Endpoints: []Endpoint{
+			{Endpoint: "registry-1.docker.io", Username: "user", Password: "password"},
+			{Endpoint: "mirror.example.com", Token: "token"},
+		}},
+		{Name: "quay.io", Endpoints: []Endpoint{{Endpoint: "quay.io"}}},
+	}
+	if !reflect.DeepEqual(registries, expected) {
+		t.Fatalf("expected %v but got %v", expected, registries)
+	}
+
+	redacted := Redact(registries, "REDACTED")
+	if redacted[0].Endpoints[0].Password != "REDACTED" || redacted[0].Endpoints[1].Token != "REDACTED" || redacted[0].Endpoints[0].Username != "user" {
+		t.Fatalf("expected credentials to be redacted, got %v", redacted)
+	}
+	if registries[0].Endpoints[0].Password != "password" {
+		t.Fatalf("expected original registries to be unchanged, got %v", registries)
+	}
+
+	registries, err := Remove(registries, "docker.io", "mirror.example.com")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	registries, err = Remove(registries, "quay.io", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected = []Registry{
+		{Name: "docker.io", Endpoints: []Endpoint{{Endpoint: "registry-1.docker.io", Username: "user", Password: "password"}}},
+	}
+	if !reflect.DeepEqual(registries, expected) {
+		t.Fatalf("unexpected registries %v", registries)
+	}
+	if _, err := Remove(registries, "docker.io", "mirror.example.com"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected not found error, got %v", err)
+	}
+}
@@ -0,0 +1,189 @@
+package registry
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"gopkg.in/yaml.v3"
+
+	"github.com/giantswarm/mcli/pkg/key"
+)
+
+// values is the containerd configuration of the cluster chart.
+type values struct {
+	Global struct {
+		Components struct {
+			Containerd struct {
+				ContainerRegistries map[string][]endpointValues `yaml:"containerRegistries"`
+			} `yaml:"containerd"`
+		} `yaml:"components"`
+	} `yaml:"global"`
+}
+
+type endpointValues struct {
+	Endpoint    string             `yaml:"endpoint"`
+	Credentials *credentialsValues `yaml:"credentials,omitempty"`
+}
+
+type credentialsValues struct {
+	Username      string `yaml:"username,omitempty"`
+	Password      string `yaml:"password,omitempty"`
+	IdentityToken string `yaml:"identitytoken,omitempty"`
+}
+
+var hostname = regexp.MustCompile(`^([a-zA-Z0-9]([a-zA-Z0-9-]*[a-zA-Z0-9])?\.)*[a-zA-Z0-9]([a-zA-Z0-9-]*[a-zA-Z0-9])?$`)
+
+// GetValues renders the containerd values of the configuration.
+func GetValues(c Config) (string, error) {
+	if len(c.Registries) == 0 {
+		return c.Raw, nil
+	}
+
+	var v values
+	v.Global.Components.Containerd.ContainerRegistries = map[string][]endpointValues{}
+	for _, r := range c.Registries {
+		var endpoints []endpointValues
+		for _, e := range r.Endpoints {
+			endpoint := endpointValues{Endpoint: e.Endpoint}
+			if e.Username != "" || e.Password != "" || e.Token != "" {
+				endpoint.Credentials = &credentialsValues{
+					Username:      e.Username,
+					Password:      e.Password,
+					IdentityToken: e.Token,
+				}
+			}
+			endpoints = append(endpoints, endpoint)
+		}
+		v.Global.Components.Containerd.ContainerRegistries[r.Name] = endpoints
+	}
+	data, err := key.GetData(v)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal container registry values.\n%w", err)
+	}
+	if c.Raw == "" {
+		return string(data), nil
+	}
+	merged, err := key.MergeValues(c.Raw, string(data))
+	if err != nil {
+		return "", fmt.Errorf("failed to merge container registry values.\n%w", err)
+	}
+	return merged, nil
+}
+
+// GetConfig parses containerd values into registries.
+// Values containing anything but registries are kept as raw values.
+func GetConfig(data string) Config {
+	var v values
+	if err := yaml.Unmarshal([]byte(data), &v); err != nil || len(v.Global.Components.Containerd.ContainerRegistries) == 0 {
+		log.Debug().Msg("keeping raw container registry values")
+		return Config{Raw: data}
+	}
+
+	var config Config
+	for name, endpoints := range v.Global.Components.Containerd.ContainerRegistries {
+		r := Registry{Name: name}
+		for _, e := range endpoints {
+			endpoint := Endpoint{Endpoint: e.Endpoint}
+			if e.Credentials != nil {
+				endpoint.Username = e.Credentials.Username
+				endpoint.Password = e.Credentials.Password
+				endpoint.Token = e.Credentials.IdentityToken
+			}
+			r.Endpoints = append(r.Endpoints, endpoint)
+		}
+		config.Registries = append(config.Registries, r)
+	}
+	sortRegistries(config.Registries)
+
+	// only accept the registries if they render the same values
+	rendered, err := GetValues(config)
+	if err != nil || !equalValues(rendered, data) {
+		log.Debug().Msg("container registry values contain unknown fields, keeping raw values")
+		return Config{Raw: data}
+	}
+	return config
+}
+
+// Validate checks the rendered containerd values of the configuration.
+func Validate(c Config) error {
+	data, err := GetValues(c)
+	if err != nil {
+		return err
+	}
+	return ValidateValues(data)
+}
+
+// ValidateValues checks registries, endpoints and credentials of containerd values.
+func ValidateValues(data string) error {
+	var v values
+	if err := yaml.Unmarshal([]byte(data), &v); err != nil {
+		return fmt.Errorf("container registry values are not valid YAML.\n%v\n%w", err, ErrInvalidRegistry)
+	}
+	for name, endpoints := range v.Global.Components.Containerd.ContainerRegistries {
+		if !isHost(name) {
+			return fmt.Errorf("invalid registry name %q\n%w", name, ErrInvalidRegistry)
+		}
+		if len(endpoints) == 0 {
+			return fmt.Errorf("registry %s has no endpoints\n%w", name, ErrInvalidRegistry)
+		}
+		for _, e := range endpoints {
+			if !isEndpoint(e.Endpoint) {
+				return fmt.Errorf("invalid endpoint %q of registry %s\n%w", e.Endpoint, name, ErrInvalidRegistry)
+			}
+			if e.Credentials == nil {
+				continue
+			}
+			credentials := *e.Credentials
+			if credentials.IdentityToken != "" && (credentials.Username != "" || credentials.Password != "") {
+				return fmt.Errorf("endpoint %s of registry %s has both a token and username and password\n%w", e.Endpoint, name, ErrInvalidRegistry)
+			}
+			if (credentials.Username == "") != (credentials.Password == "") {
+				return fmt.Errorf("endpoint %s of registry %s requires both username and password\n%w", e.Endpoint, name, ErrInvalidRegistry)
+			}
+		}
+	}
+	return nil
+}
+
+// isHost checks for a hostname or IP with an optional port.
+func isHost(host string) bool {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return net.ParseIP(host) != nil || hostname.MatchString(host)
+}
+
+// isEndpoint checks for a host with an optional scheme and path.
+func isEndpoint(endpoint string) bool {
+	if !strings.Contains(endpoint, "://") {
+		endpoint = "https://" + endpoint
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return false
+	}
+	return isHost(u.Host)
+}
+
+func equalValues(a string, b string) bool {
+	var x, y any
+	if err := yaml.Unmarshal([]byte(a), &x); err != nil {
+		return false
+	}
+	if err := yaml.Unmarshal([]byte(b), &y); err != nil {
+		return false
+	}
+	return reflect.DeepEqual(x, y)
+}
+
+func sortRegistries(registries []Registry) {
+	sort.SliceStable(registries, func(i, j int) bool {
+		return registries[i].Name < registries[j].Name
+	})
+}
@@ -9,7 +9,9 @@ import (
 
 	"github.com/giantswarm/mcli/pkg/github"
 	"github.com/giantswarm/mcli/pkg/key"
+	"github.com/giantswarm/mcli/pkg/managementcluster/cmc/certmanager"
 	"github.com/giantswarm/mcli/pkg/managementcluster/cmc/kustomization"
+	"github.com/giantswarm/mcli/pkg/managementcluster/cmc/registry"
 )
 
 const (
@@ -27,10 +29,27 @@ func (c *CMC) RedactSecrets() {
 	c.SSHdeployKey.Passphrase = Redacted
 
 	if c.CertManagerDNSChallenge.Enabled {
-		c.CertManagerDNSChallenge.SecretAccessKey = Redacted
+		switch certmanager.GetSolver(c.CertManagerDNSChallenge.Solver) {
+		case certmanager.SolverRoute53:
+			c.CertManagerDNSChallenge.SecretAccessKey = Redacted
+		case certmanager.SolverAzureDNS:
+			if c.CertManagerDNSChallenge.AzureDNS.ClientSecret != "" {
+				c.CertManagerDNSChallenge.AzureDNS.ClientSecret = Redacted
+			}
+		case certmanager.SolverCloudflare:
+			c.CertManagerDNSChallenge.Cloudflare.APIToken = Redacted
+		case certmanager.SolverRFC2136:
+			c.CertManagerDNSChallenge.RFC2136.TSIGSecret = Redacted
+		}
 	}
 	if c.ConfigureContainerRegistries.Enabled {
-		c.ConfigureContainerRegistries.Values = Redacted
+		c.ConfigureContainerRegistries.Registries = registry.Redact(c.ConfigureContainerRegistries.Registries, Redacted)
+		if c.ConfigureContainerRegistries.Values != "" {
+			c.ConfigureContainerRegistries.Values = Redacted
+		}
+	}
+	if c.PrivateCA.Key != "" {
+		c.PrivateCA.Key = Redacted
 	}
 
 	if key.IsProviderAzure(c.Provider.Name) {
@@ -42,6 +61,27 @@ func (c *CMC) RedactSecrets() {
 	}
 }
 
+// StripSecrets removes all secrets so that they have to be supplied again.
+func (c *CMC) StripSecrets() {
+	log.Debug().Msg("Stripping secret values")
+	c.AgePubKey = ""
+	c.TaylorBotToken = ""
+	c.SSHdeployKey = DeployKey{}
+	c.CustomerDeployKey = DeployKey{}
+	c.SharedDeployKey = DeployKey{}
+	c.CertManagerDNSChallenge.SecretAccessKey = ""
+	c.CertManagerDNSChallenge.AzureDNS.ClientSecret = ""
+	c.CertManagerDNSChallenge.Cloudflare.APIToken = ""
+	c.CertManagerDNSChallenge.RFC2136.TSIGSecret = ""
+	c.ConfigureContainerRegistries.Registries = registry.Redact(c.ConfigureContainerRegistries.Registries, "")
+	c.ConfigureContainerRegistries.Values = ""
+	c.PrivateCA.Key = ""
+	c.Provider.CAPZ.ClientSecret = ""
+	c.Provider.CAPVCD.RefreshToken = ""
+	c.Provider.CAPV.CloudConfig = ""
+	c.CredentialExpiry = nil
+}
+
 func (c *CMC) EncodeSecrets() {
 	log.Debug().Msg("Base64 encoding secret values")
 	c.TaylorBotToken = encodeSecret(c.TaylorBotToken)
@@ -54,7 +94,6 @@ func (c *CMC) EncodeSecrets() {
 	c.SSHdeployKey.Identity = encodeSecret(c.SSHdeployKey.Identity)
 	c.SSHdeployKey.Passphrase = encodeSecret(c.SSHdeployKey.Passphrase)
 	c.SSHdeployKey.KnownHosts = encodeSecret(c.SSHdeployKey.KnownHosts)
-	c.ConfigureContainerRegistries.Values = encodeSecret(c.ConfigureContainerRegistries.Values)
 	c.Provider.CAPZ.ClientSecret = encodeSecret(c.Provider.CAPZ.ClientSecret)
 	c.Provider.CAPVCD.RefreshToken = encodeSecret(c.Provider.CAPVCD.RefreshToken)
 	c.Provider.CAPV.CloudConfig = encodeSecret(c.Provider.CAPV.CloudConfig)
@@ -103,10 +142,6 @@ func (c *CMC) DecodeSecrets() error {
 	if err != nil {
 		return fmt.Errorf("failed to decode SSHdeployKey KnownHosts: %w", err)
 	}
-	c.ConfigureContainerRegistries.Values, err = decodeSecret(c.ConfigureContainerRegistries.Values)
-	if err != nil {
-		return fmt.Errorf("failed to decode ConfigureContainerRegistries Values: %w", err)
-	}
 	c.Provider.CAPZ.ClientSecret, err = decodeSecret(c.Provider.CAPZ.ClientSecret)
 	if err != nil {
 		return fmt.Errorf("failed to decode CAPZ ClientSecret: %w", err)
@@ -158,6 +193,9 @@ func MarkUnchangedSecretsInMap(currentCMC *CMC, desiredCMC *CMC, update map[stri
 	if reflect.DeepEqual(currentCMC.CertManagerDNSChallenge, desiredCMC.CertManagerDNSChallenge) {
 		markUnchanged(update, fmt.Sprintf("%s/%s", key.GetCMCPath(desiredCMC.Cluster), kustomization.CertManagerFile))
 	}
+	if reflect.DeepEqual(currentCMC.PrivateCA, desiredCMC.PrivateCA) {
+		markUnchanged(update, fmt.Sprintf("%s/%s", key.GetCMCPath(desiredCMC.Cluster), kustomization.PrivateCASecretFile))
+	}
 	if reflect.DeepEqual(currentCMC.ConfigureContainerRegistries, desiredCMC.ConfigureContainerRegistries) {
 		markUnchanged(update, fmt.Sprintf("%s/%s", key.GetCMCPath(desiredCMC.Cluster), kustomization.RegistryFile))
 	}
@@ -48,6 +48,30 @@ func GetSopsFile(c Config, file string) (string, error) {
 	return string(data), nil
 }
 
+// RemoveCluster removes the creation rule of the cluster and reports whether it existed.
+func RemoveCluster(file string, cluster string) (string, bool, error) {
+	log.Debug().Msg(fmt.Sprintf("Removing SOPS pubkey of the installation %s", cluster))
+	sops, err := getSops(file)
+	if err != nil {
+		return "", false, err
+	}
+	var rules []CreationRule
+	for _, rule := range sops.CreationRules {
+		if rule.PathRegex != getRegex(cluster) {
+			rules = append(rules, rule)
+		}
+	}
+	if len(rules) == len(sops.CreationRules) {
+		return file, false, nil
+	}
+	sops.CreationRules = rules
+	data, err := key.GetData(sops)
+	if err != nil {
+		return "", false, err
+	}
+	return string(data), true, nil
+}
+
 func GetSopsConfig(file string, cluster string) (Config, error) {
 	log.Debug().Msg(fmt.Sprintf("Getting SOPS pubkey for the installation %s", cluster))
 	sops, err := getSops(file)
@@ -0,0 +1,218 @@
+package managementcluster
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/giantswarm/mcli/pkg/key"
+)
+
+// Placeholders replace cluster-specific values when management clusters are compared.
+const (
+	PlaceholderCluster    = "<cluster>"
+	PlaceholderBaseDomain = "<baseDomain>"
+	PlaceholderNamespace  = "<namespace>"
+	PlaceholderCMCPath    = "<cmcPath>"
+)
+
+// Difference is a field with different values in two management clusters.
+type Difference struct {
+	Field string `json:"field"`
+	A     string `json:"a"`
+	B     string `json:"b"`
+}
+
+// Compare returns the fields which differ between two management clusters after their cluster-specific values are normalized.
+// Strings which contain YAML objects, e.g. app values, are compared field by field.
+func Compare(a *ManagementCluster, b *ManagementCluster) ([]Difference, error) {
+	fieldsA, err := getNormalizedFields(a)
+	if err != nil {
+		return nil, err
+	}
+	fieldsB, err := getNormalizedFields(b)
+	if err != nil {
+		return nil, err
+	}
+	differences := getDifferences("", fieldsA, fieldsB)
+	sort.SliceStable(differences, func(i, j int) bool {
+		return differences[i].Field < differences[j].Field
+	})
+	return differences, nil
+}
+
+// CompareLists returns the entries which are only part of one of the lists.
+func CompareLists(field string, a []string, b []string) []Difference {
+	inA := map[string]bool{}
+	for _, entry := range a {
+		inA[entry] = true
+	}
+	inB := map[string]bool{}
+	for _, entry := range b {
+		inB[entry] = true
+	}
+	var differences []Difference
+	for _, entry := range a {
+		if !inB[entry] {
+			differences = append(differences, Difference{Field: field, A: entry, B: Unset})
+		}
+	}
+	for _, entry := range b {
+		if !inA[entry] {
+			differences = append(differences, Difference{Field: field, A: Unset, B: entry})
+		}
+	}
+	return differences
+}
+
+// Normalize replaces the cluster-specific values of the management cluster in s by placeholders.
+// Values are only replaced as a whole, e.g. the cluster gig is not replaced in gigantic.io.
+func Normalize(mc *ManagementCluster, s string) string {
+	for _, r := range getReplacements(mc) {
+		s = replaceWord(s, r.value, r.placeholder)
+	}
+	return s
+}
+
+type replacement struct {
+	value       string
+	placeholder string
+}
+
+// getReplacements returns the cluster-specific values, longest first, so that values containing others are replaced before them.
+func getReplacements(mc *ManagementCluster) []replacement {
+	cluster := mc.CMC.Cluster
+	if cluster == "" {
+		cluster = mc.Installations.Codename
+	}
+	baseDomain := mc.CMC.BaseDomain
+	if baseDomain == "" {
+		baseDomain = mc.Installations.Base
+	}
+	var replacements []replacement
+	if cluster != "" {
+		replacements = append(replacements,
+			replacement{value: key.GetCMCPath(cluster), placeholder: PlaceholderCMCPath},
+			replacement{value: cluster, placeholder: PlaceholderCluster},
+		)
+	}
+	if baseDomain != "" {
+		replacements = append(replacements, replacement{value: baseDomain, placeholder: PlaceholderBaseDomain})
+	}
+	if mc.CMC.ClusterNamespace != "" {
+		replacements = append(replacements, replacement{value: mc.CMC.ClusterNamespace, placeholder: PlaceholderNamespace})
+	}
+	sort.SliceStable(replacements, func(i, j int) bool {
+		return len(replacements[i].value) > len(replacements[j].value)
+	})
+	return replacements
+}
+
+func replaceWord(s string, old string, placeholder string) string {
+	var b strings.Builder
+	for {
+		i := strings.Index(s, old)
+		if i < 0 {
+			b.WriteString(s)
+			return b.String()
+		}
+		end := i + len(old)
+		if isBoundary(s, i-1) && isBoundary(s, end) {
+			b.WriteString(s[:i])
+			b.WriteString(placeholder)
+		} else {
+			b.WriteString(s[:end])
+		}
+		s = s[end:]
+	}
+}
+
+func isBoundary(s string, i int) bool {
+	if i < 0 || i >= len(s) {
+		return true
+	}
+	c := rune(s[i])
+	return !unicode.IsLetter(c) && !unicode.IsDigit(c)
+}
+
+func getNormalizedFields(mc *ManagementCluster) (map[string]any, error) {
+	data, err := GetData(mc)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]any
+	if err := yaml.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal management cluster.\n%w", err)
+	}
+	return expandValues(mc, fields).(map[string]any), nil
+}
+
+// expandValues replaces strings which contain YAML objects by the objects and normalizes all other strings.
+// Keys are kept as they are.
+func expandValues(mc *ManagementCluster, value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for k, nested := range v {
+			v[k] = expandValues(mc, nested)
+		}
+		return v
+	case []any:
+		for i, nested := range v {
+			v[i] = expandValues(mc, nested)
+		}
+		return v
+	case string:
+		if !strings.Contains(v, "\n") {
+			return Normalize(mc, v)
+		}
+		var object map[string]any
+		if err := yaml.Unmarshal([]byte(v), &object); err != nil || len(object) == 0 {
+			return Normalize(mc, v)
+		}
+		return expandValues(mc, object)
+	}
+	return value
+}
+
+func getDifferences(path string, a any, b any) []Difference {
+	mapA, okA := a.(map[string]any)
+	mapB, okB := b.(map[string]any)
+	if okA && okB {
+		keys := map[string]bool{}
+		for k := range mapA {
+			keys[k] = true
+		}
+		for k := range mapB {
+			keys[k] = true
+		}
+		var differences []Difference
+		for k := range keys {
+			nested := k
+			if path != "" {
+				nested = fmt.Sprintf("%s.%s", path, k)
+			}
+			differences = append(differences, getDifferences(nested, mapA[k], mapB[k])...)
+		}
+		return differences
+	}
+	if reflect.DeepEqual(a, b) {
+		return nil
+	}
+	return []Difference{{Field: path, A: formatAny(a), B: formatAny(b)}}
+}
+
+func formatAny(value any) string {
+	if value == nil {
+		return Unset
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
@@ -0,0 +1,93 @@
+package managementcluster
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/giantswarm/mcli/pkg/managementcluster/cmc"
+	"github.com/giantswarm/mcli/pkg/managementcluster/installations"
+)
+
+func getCompareManagementCluster(cluster string, version string, values string) *ManagementCluster {
+	return &ManagementCluster{
+		Installations: installations.Installations{
+			Codename: cluster,
+			Base:     cluster + ".gigantic.io",
+			Pipeline: "testing",
+		},
+		CMC: cmc.CMC{
+			Cluster:          cluster,
+			BaseDomain:       cluster + ".gigantic.io",
+			ClusterNamespace: "org-" + cluster,
+			ClusterApp: cmc.App{
+				Name:    cluster,
+				Version: version,
+				Values:  values,
+			},
+		},
+	}
+}
+
+func TestCompare(t *testing.T) {
+	testCases := []struct {
+		name string
+		a    *ManagementCluster
+		b    *ManagementCluster
+
+		expected []Difference
+	}{
+		{
+			name: "case 0: cluster-specific values are normalized",
+			a:    getCompareManagementCluster("gigmac", "1.2.3", "global:\n  metadata:\n    name: gigmac\n  domain: api.gigmac.gigantic.io\n"),
+			b:    getCompareManagementCluster("gauss", "1.2.3", "global:\n  metadata:\n    name: gauss\n  domain: api.gauss.gigantic.io\n"),
+		},
+		{
+			name: "case 1: differences in fields and values",
+			a:    getCompareManagementCluster("gigmac", "1.2.3", "global:\n  metadata:\n    name: gigmac\n  replicas: 1\n"),
+			b:    getCompareManagementCluster("gauss", "1.3.0", "global:\n  metadata:\n    name: gauss\n  replicas: 3\n  debug: true\n"),
+
+			expected: []Difference{
+				{Field: "cmc.clusterApp.values.global.debug", A: Unset, B: "true"},
+				{Field: "cmc.clusterApp.values.global.replicas", A: "1", B: "3"},
+				{Field: "cmc.clusterApp.version", A: `"1.2.3"`, B: `"1.3.0"`},
+			},
+		},
+		{
+			name: "case 2: cluster names are only replaced as a whole",
+			a:    getCompareManagementCluster("gig", "1.2.3", "global:\n  catalog: gigantic\n"),
+			b:    getCompareManagementCluster("gauss", "1.2.3", "global:\n  catalog: gaussian\n"),
+
+			expected: []Difference{
+				{Field: "cmc.clusterApp.values.global.catalog", A: `"gigantic"`, B: `"gaussian"`},
+			},
+		},
+		{
+			name: "case 3: keys are not normalized",
+			a:    getCompareManagementCluster("gig", "1.2.3", "global:\n  gig:\n    name: gig\n"),
+			b:    getCompareManagementCluster("gauss", "1.2.3", "global:\n  gig:\n    name: gauss\n"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			differences, err := Compare(tc.a, tc.b)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(differences, tc.expected) {
+				t.Fatalf("expected %v, got %v", tc.expected, differences)
+			}
+		})
+	}
+}
+
+func TestCompareLists(t *testing.T) {
+	differences := CompareLists("resources", []string{"a.yaml", "b.yaml"}, []string{"b.yaml", "c.yaml"})
+	expected := []Difference{
+		{Field: "resources", A: "a.yaml", B: Unset},
+		{Field: "resources", A: Unset, B: "c.yaml"},
+	}
+	if !reflect.DeepEqual(differences, expected) {
+		t.Fatalf("expected %v, got %v", expected, differences)
+	}
+}
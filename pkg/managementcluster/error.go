@@ -0,0 +1,9 @@
+package managementcluster
+
+import (
+	"errors"
+)
+
+var ErrInvalidField = errors.New("invalid field")
+
+var ErrFieldNotSet = errors.New("field not set")
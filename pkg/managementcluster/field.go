@@ -0,0 +1,211 @@
+package managementcluster
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/giantswarm/mcli/pkg/key"
+)
+
+// Unset is shown for fields which are not set.
+const Unset = "<unset>"
+
+// GetField returns the YAML value of the field at the path, e.g. cmc.clusterApp.version.
+// Numeric path segments address list elements.
+func GetField(mc *ManagementCluster, path string) (string, error) {
+	root, err := getNode(mc)
+	if err != nil {
+		return "", err
+	}
+	node, err := findNode(root, getSegments(path), false)
+	if err != nil {
+		return "", fmt.Errorf("failed to get field %s.\n%w", path, err)
+	}
+	if node == nil {
+		return "", fmt.Errorf("field %s is not set.\n%w", path, ErrFieldNotSet)
+	}
+	data, err := key.GetData(node)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal field %s.\n%w", path, err)
+	}
+	return string(data), nil
+}
+
+// FormatField returns the value of the field at the path on a single line or <unset> if it is not set.
+func FormatField(mc *ManagementCluster, path string) string {
+	value, err := GetField(mc, path)
+	if err != nil {
+		return Unset
+	}
+	return FormatValue(value)
+}
+
+// PrintChanges prints the changed fields with their current and desired value, or no changes.
+// Unless displaySecrets is set, secrets are redacted after the changes are detected.
+func PrintChanges(w io.Writer, current *ManagementCluster, desired *ManagementCluster, displaySecrets bool) error {
+	changedFields, err := key.GetChangedFields(current, desired)
+	if err != nil {
+		return fmt.Errorf("failed to get changed fields.\n%w", err)
+	}
+	if len(changedFields) == 0 {
+		fmt.Fprintln(w, "no changes")
+		return nil
+	}
+	if !displaySecrets {
+		current.CMC.RedactSecrets()
+		desired.CMC.RedactSecrets()
+	}
+	for _, path := range changedFields {
+		fmt.Fprintf(w, "%s: %s -> %s\n", path, FormatField(current, path), FormatField(desired, path))
+	}
+	return nil
+}
+
+// FormatValue returns a YAML value on a single line.
+func FormatValue(value string) string {
+	var v any
+	if err := yaml.Unmarshal([]byte(value), &v); err != nil {
+		return strings.TrimSpace(value)
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return strings.TrimSpace(value)
+	}
+	return string(data)
+}
+
+// SetFields returns a copy of the management cluster with the fields given as path=value set.
+// Values are parsed as YAML and have to match the type of the field.
+func SetFields(mc *ManagementCluster, fields []string) (*ManagementCluster, error) {
+	root, err := getNode(mc)
+	if err != nil {
+		return nil, err
+	}
+	for _, field := range fields {
+		path, value, err := ParseField(field)
+		if err != nil {
+			return nil, err
+		}
+		node, err := findNode(root, getSegments(path), true)
+		if err != nil {
+			return nil, fmt.Errorf("failed to set field %s.\n%w", path, err)
+		}
+		var document yaml.Node
+		if err := yaml.Unmarshal([]byte(value), &document); err != nil {
+			return nil, fmt.Errorf("failed to parse value of field %s.\n%w", path, err)
+		}
+		if len(document.Content) == 0 {
+			*node = yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str"}
+		} else {
+			*node = *document.Content[0]
+		}
+	}
+	data, err := key.GetData(root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal management cluster fields.\n%w", err)
+	}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	result := ManagementCluster{}
+	if err := decoder.Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to set fields. %s\n%w", err, ErrInvalidField)
+	}
+	return &result, nil
+}
+
+// ParseField splits a field given as path=value.
+func ParseField(field string) (string, string, error) {
+	path, value, ok := strings.Cut(field, "=")
+	if !ok || path == "" {
+		return "", "", fmt.Errorf("invalid field %s. Expected format: path=value.\n%w", field, ErrInvalidField)
+	}
+	return path, value, nil
+}
+
+// GetFieldRepository returns the repository which holds the field at the path.
+func GetFieldRepository(path string) (string, error) {
+	switch getSegments(path)[0] {
+	case "installations":
+		return key.RepositoryInstallations, nil
+	case "cmc":
+		return key.RepositoryCMC, nil
+	}
+	return "", fmt.Errorf("field %s has to start with installations or cmc.\n%w", path, ErrInvalidField)
+}
+
+func getSegments(path string) []string {
+	return strings.Split(path, ".")
+}
+
+func getNode(mc *ManagementCluster) (*yaml.Node, error) {
+	data, err := GetData(mc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal management cluster.\n%w", err)
+	}
+	var document yaml.Node
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal management cluster.\n%w", err)
+	}
+	if len(document.Content) == 0 {
+		return &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}, nil
+	}
+	return document.Content[0], nil
+}
+
+// findNode returns the node at the path or nil if it is not set.
+// With create, missing mapping keys and a list element after the last one are added.
+func findNode(node *yaml.Node, segments []string, create bool) (*yaml.Node, error) {
+	if len(segments) == 0 {
+		return node, nil
+	}
+	segment := segments[0]
+	if segment == "" {
+		return nil, fmt.Errorf("empty path segment\n%w", ErrInvalidField)
+	}
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == segment {
+				return findNode(node.Content[i+1], segments[1:], create)
+			}
+		}
+		if !create {
+			return nil, nil
+		}
+		value := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: segment}, value)
+		return findNode(value, segments[1:], create)
+	case yaml.SequenceNode:
+		index, err := strconv.Atoi(segment)
+		if err != nil || index < 0 {
+			return nil, fmt.Errorf("%s is not a list index\n%w", segment, ErrInvalidField)
+		}
+		if index < len(node.Content) {
+			return findNode(node.Content[index], segments[1:], create)
+		}
+		if !create {
+			return nil, nil
+		}
+		if index > len(node.Content) {
+			return nil, fmt.Errorf("list index %d is out of range\n%w", index, ErrInvalidField)
+		}
+		value := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		node.Content = append(node.Content, value)
+		return findNode(value, segments[1:], create)
+	case yaml.ScalarNode:
+		if node.Tag == "!!null" {
+			if !create {
+				return nil, nil
+			}
+			*node = yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			return findNode(node, segments, create)
+		}
+	}
+	return nil, fmt.Errorf("%s is not an object or list\n%w", segment, ErrInvalidField)
+}
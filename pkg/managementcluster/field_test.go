@@ -0,0 +1,235 @@
+package managementcluster
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/giantswarm/mcli/pkg/managementcluster/cmc"
+	"github.com/giantswarm/mcli/pkg/managementcluster/installations"
+)
+
+func getTestManagementCluster() *ManagementCluster {
+	return &ManagementCluster{
+		Installations: installations.Installations{
+			Codename: "gigmac",
+			Pipeline: "testing",
+			Aws: installations.AwsConfig{
+				HostCluster: installations.HostCluster{
+					GuardDuty: true,
+				},
+			},
+		},
+		CMC: cmc.CMC{
+			Cluster: "gigmac",
+			ClusterApp: cmc.App{
+				Version: "1.2.2",
+			},
+			MCProxy: cmc.MCProxy{
+				NoProxy: []string{"a.example.com", "b.example.com"},
+			},
+		},
+	}
+}
+
+func TestGetField(t *testing.T) {
+	testCases := []struct {
+		name string
+		path string
+
+		expected    string
+		expectedErr error
+	}{
+		{
+			name: "case 0: get string",
+			path: "cmc.clusterApp.version",
+
+			expected: "1.2.2\n",
+		},
+		{
+			name: "case 1: get list element",
+			path: "cmc.mcProxy.noProxy.1",
+
+			expected: "b.example.com\n",
+		},
+		{
+			name: "case 2: get object",
+			path: "installations.aws.hostCluster",
+
+			expected: "account: \"\"\ncloudtrailBucket: \"\"\nadminRoleARN: \"\"\nguardDuty: true\n",
+		},
+		{
+			name: "case 3: field is not set",
+			path: "cmc.clusterApp.unknown",
+
+			expectedErr: ErrFieldNotSet,
+		},
+		{
+			name: "case 4: path through a string",
+			path: "installations.pipeline.stable",
+
+			expectedErr: ErrInvalidField,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			value, err := GetField(getTestManagementCluster(), tc.path)
+			if !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("expected error %v, got %v", tc.expectedErr, err)
+			}
+			if value != tc.expected {
+				t.Fatalf("expected %q, got %q", tc.expected, value)
+			}
+		})
+	}
+}
+
+func TestSetFields(t *testing.T) {
+	testCases := []struct {
+		name   string
+		fields []string
+
+		check       func(mc *ManagementCluster) bool
+		expectedErr error
+	}{
+		{
+			name:   "case 0: set strings in both repositories",
+			fields: []string{"cmc.clusterApp.version=1.2.3", "installations.pipeline=stable"},
+
+			check: func(mc *ManagementCluster) bool {
+				return mc.CMC.ClusterApp.Version == "1.2.3" && mc.Installations.Pipeline == "stable"
+			},
+		},
+		{
+			name:   "case 1: version is kept as written",
+			fields: []string{"cmc.clusterApp.version=1.10"},
+
+			check: func(mc *ManagementCluster) bool {
+				return mc.CMC.ClusterApp.Version == "1.10"
+			},
+		},
+		{
+			name:   "case 2: unset boolean",
+			fields: []string{"installations.aws.hostCluster.guardDuty=false"},
+
+			check: func(mc *ManagementCluster) bool {
+				return !mc.Installations.Aws.HostCluster.GuardDuty
+			},
+		},
+		{
+			name:   "case 3: set and append list elements",
+			fields: []string{"cmc.mcProxy.noProxy.0=c.example.com", "cmc.mcProxy.noProxy.2=d.example.com"},
+
+			check: func(mc *ManagementCluster) bool {
+				p := mc.CMC.MCProxy.NoProxy
+				return len(p) == 3 && p[0] == "c.example.com" && p[1] == "b.example.com" && p[2] == "d.example.com"
+			},
+		},
+		{
+			name:   "case 4: set whole list",
+			fields: []string{"cmc.mcProxy.noProxy=[e.example.com]"},
+
+			check: func(mc *ManagementCluster) bool {
+				return len(mc.CMC.MCProxy.NoProxy) == 1 && mc.CMC.MCProxy.NoProxy[0] == "e.example.com"
+			},
+		},
+		{
+			name:   "case 5: unknown cmc field",
+			fields: []string{"cmc.clusterApp.unknown=1"},
+
+			expectedErr: ErrInvalidField,
+		},
+		{
+			name:   "case 6: wrong type",
+			fields: []string{"installations.aws.hostCluster.guardDuty=maybe"},
+
+			expectedErr: ErrInvalidField,
+		},
+		{
+			name:   "case 7: missing value",
+			fields: []string{"cmc.clusterApp.version"},
+
+			expectedErr: ErrInvalidField,
+		},
+		{
+			name:   "case 8: list index out of range",
+			fields: []string{"cmc.mcProxy.noProxy.5=x"},
+
+			expectedErr: ErrInvalidField,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mc := getTestManagementCluster()
+			result, err := SetFields(mc, tc.fields)
+			if !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("expected error %v, got %v", tc.expectedErr, err)
+			}
+			if err != nil {
+				return
+			}
+			if !tc.check(result) {
+				t.Fatalf("unexpected result %+v", result)
+			}
+			if mc.CMC.ClusterApp.Version != "1.2.2" || mc.Installations.Pipeline != "testing" {
+				t.Fatalf("expected input to be unchanged")
+			}
+		})
+	}
+}
+
+func TestPrintChanges(t *testing.T) {
+	testCases := []struct {
+		name           string
+		change         func(mc *ManagementCluster)
+		displaySecrets bool
+
+		expected string
+	}{
+		{
+			name:     "case 0: no changes",
+			change:   func(mc *ManagementCluster) {},
+			expected: "no changes\n",
+		},
+		{
+			name: "case 1: changed fields",
+			change: func(mc *ManagementCluster) {
+				mc.CMC.ClusterApp.Version = "1.2.3"
+				mc.Installations.Pipeline = "stable"
+			},
+			expected: "cmc.clusterApp.version: \"1.2.2\" -> \"1.2.3\"\ninstallations.pipeline: \"testing\" -> \"stable\"\n",
+		},
+		{
+			name: "case 2: changed secrets are redacted",
+			change: func(mc *ManagementCluster) {
+				mc.CMC.TaylorBotToken = "token"
+			},
+			expected: "cmc.taylorBotToken: \"" + cmc.Redacted + "\" -> \"" + cmc.Redacted + "\"\n",
+		},
+		{
+			name: "case 3: changed secrets are displayed",
+			change: func(mc *ManagementCluster) {
+				mc.CMC.TaylorBotToken = "token"
+			},
+			displaySecrets: true,
+			expected:       "cmc.taylorBotToken: \"\" -> \"token\"\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			current := getTestManagementCluster()
+			desired := getTestManagementCluster()
+			tc.change(desired)
+			out := &bytes.Buffer{}
+			if err := PrintChanges(out, current, desired, tc.displaySecrets); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if out.String() != tc.expected {
+				t.Fatalf("expected %q, got %q", tc.expected, out.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,11 @@
+package installations
+
+import (
+	"errors"
+)
+
+var ErrInvalidField = errors.New("invalid field")
+
+var ErrInvalidSchema = errors.New("invalid schema")
+
+var ErrSchemaValidation = errors.New("schema validation failed")
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"reflect"
+	"strings"
 
 	"github.com/rs/zerolog/log"
 	"gopkg.in/yaml.v3"
@@ -22,18 +23,22 @@ type Installations struct {
 	Provider            string    `yaml:"provider"`
 	AdditionalProviders []string  `yaml:"additionalProviders,omitempty"`
 	Aws                 AwsConfig `yaml:"aws,omitempty"`
+	// Extra holds fields of the schema that are not modelled above.
+	Extra map[string]any `yaml:",inline"`
 }
 
 type AwsConfig struct {
-	Region      string      `yaml:"region"`
-	HostCluster HostCluster `yaml:"hostCluster"`
+	Region      string         `yaml:"region"`
+	HostCluster HostCluster    `yaml:"hostCluster"`
+	Extra       map[string]any `yaml:",inline"`
 }
 
 type HostCluster struct {
-	Account          string `yaml:"account"`
-	CloudtrailBucket string `yaml:"cloudtrailBucket"`
-	AdminRoleArn     string `yaml:"adminRoleARN"`
-	GuardDuty        bool   `yaml:"guardDuty"`
+	Account          string         `yaml:"account"`
+	CloudtrailBucket string         `yaml:"cloudtrailBucket"`
+	AdminRoleArn     string         `yaml:"adminRoleARN"`
+	GuardDuty        bool           `yaml:"guardDuty"`
+	Extra            map[string]any `yaml:",inline"`
 }
 
 type InstallationsConfig struct {
@@ -142,9 +147,87 @@ func (i *Installations) Override(override *Installations) *Installations {
 	if override.Aws.HostCluster.GuardDuty {
 		installation.Aws.HostCluster.GuardDuty = override.Aws.HostCluster.GuardDuty
 	}
+	installation.Extra = mergeExtra(installation.Extra, override.Extra)
+	installation.Aws.Extra = mergeExtra(installation.Aws.Extra, override.Aws.Extra)
+	installation.Aws.HostCluster.Extra = mergeExtra(installation.Aws.HostCluster.Extra, override.Aws.HostCluster.Extra)
 	return &installation
 }
 
+// mergeExtra returns a copy of current with the fields of override merged in.
+// Nested objects are merged, all other values are replaced.
+func mergeExtra(current map[string]any, override map[string]any) map[string]any {
+	if len(override) == 0 {
+		return current
+	}
+	merged := map[string]any{}
+	for k, v := range current {
+		merged[k] = v
+	}
+	for k, v := range override {
+		nestedCurrent, okCurrent := merged[k].(map[string]any)
+		nestedOverride, okOverride := v.(map[string]any)
+		if okCurrent && okOverride {
+			merged[k] = mergeExtra(nestedCurrent, nestedOverride)
+		} else {
+			merged[k] = v
+		}
+	}
+	return merged
+}
+
+// Unset returns a copy of the installations object with the fields at the paths set to their empty value.
+func (i *Installations) Unset(paths []string) (*Installations, error) {
+	if len(paths) == 0 {
+		return i, nil
+	}
+	data, err := key.RemoveFields(i, paths)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unset fields.\n%w", err)
+	}
+	return GetInstallations(data)
+}
+
+// SetFields returns a copy of the installations object with the fields set.
+// Fields are addressed by their YAML path, e.g. aws.hostCluster.guardDuty.
+func (i *Installations) SetFields(fields map[string]any) (*Installations, error) {
+	data, err := GetData(i)
+	if err != nil {
+		return nil, err
+	}
+	var values map[string]any
+	if err := yaml.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal installations object.\n%w", err)
+	}
+	if values == nil {
+		values = map[string]any{}
+	}
+	for path, value := range fields {
+		if err := setField(values, strings.Split(path, "."), value); err != nil {
+			return nil, fmt.Errorf("failed to set field %s.\n%w", path, err)
+		}
+	}
+	data, err = key.GetData(values)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal installations fields.\n%w", err)
+	}
+	return GetInstallations(data)
+}
+
+func setField(values map[string]any, path []string, value any) error {
+	if len(path) == 1 {
+		values[path[0]] = value
+		return nil
+	}
+	if values[path[0]] == nil {
+		values[path[0]] = map[string]any{}
+	}
+	nested, ok := values[path[0]].(map[string]any)
+	if !ok {
+		return fmt.Errorf("%s is not an object\n%w", path[0], ErrInvalidField)
+	}
+	return setField(nested, path[1:], value)
+}
+
 func (i *Installations) Validate() error {
 	if i.Base == "" {
 		return fmt.Errorf("base domain is empty")
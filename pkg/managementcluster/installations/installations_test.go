@@ -0,0 +1,156 @@
+package installations
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/giantswarm/mcli/pkg/key"
+)
+
+const clusterYAML = `base: test.gigantic.io
+codename: test
+customer: giantswarm
+cmc_repository: giantswarm-management-clusters
+ccr_repository: giantswarm-customer-configs
+accountEngineer: phoenix
+pipeline: testing
+provider: capa
+aws:
+  region: eu-west-1
+  hostCluster:
+    account: "123456789012"
+    cloudtrailBucket: ""
+    adminRoleARN: arn:aws:iam::123456789012:role/GiantSwarmAdmin
+    guardDuty: false
+    externalID: abc
+  partition: aws
+sla: 99.9
+slack:
+  channel: '#alert-test'
+`
+
+func TestGetInstallationsPreservesUnknownFields(t *testing.T) {
+	i, err := GetInstallations([]byte(clusterYAML))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectedExtra := map[string]any{
+		"slack": map[string]any{"channel": "#alert-test"},
+		"sla":   99.9,
+	}
+	if !reflect.DeepEqual(i.Extra, expectedExtra) {
+		t.Fatalf("expected extra fields %v, got %v", expectedExtra, i.Extra)
+	}
+	data, err := GetData(i)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(data) != clusterYAML {
+		t.Fatalf("expected:\n%s\ngot:\n%s", clusterYAML, data)
+	}
+}
+
+func TestOverride(t *testing.T) {
+	current, err := GetInstallations([]byte(clusterYAML))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	override := &Installations{
+		Pipeline: "stable",
+		Extra: map[string]any{
+			"slack": map[string]any{"opsgenie": true},
+		},
+		Aws: AwsConfig{
+			HostCluster: HostCluster{Extra: map[string]any{"externalID": "def"}},
+		},
+	}
+	result := current.Override(override)
+
+	if result.Pipeline != "stable" {
+		t.Fatalf("expected pipeline stable, got %s", result.Pipeline)
+	}
+	expectedExtra := map[string]any{
+		"slack": map[string]any{"channel": "#alert-test", "opsgenie": true},
+		"sla":   99.9,
+	}
+	if !reflect.DeepEqual(result.Extra, expectedExtra) {
+		t.Fatalf("expected extra fields %v, got %v", expectedExtra, result.Extra)
+	}
+	if result.Aws.HostCluster.Extra["externalID"] != "def" {
+		t.Fatalf("expected host cluster external ID def, got %v", result.Aws.HostCluster.Extra["externalID"])
+	}
+	if result.Aws.Extra["partition"] != "aws" {
+		t.Fatalf("expected partition aws, got %v", result.Aws.Extra["partition"])
+	}
+	if current.Extra["slack"].(map[string]any)["opsgenie"] != nil {
+		t.Fatalf("expected current installations to be unchanged")
+	}
+}
+
+func TestSetFields(t *testing.T) {
+	current, err := GetInstallations([]byte(clusterYAML))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result, err := current.SetFields(map[string]any{
+		"pipeline":                  "stable",
+		"aws.hostCluster.guardDuty": true,
+		"slack.channel":             "#alert-other",
+		"observability.enabled":     true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Pipeline != "stable" || !result.Aws.HostCluster.GuardDuty {
+		t.Fatalf("expected modelled fields to be set, got %v", result)
+	}
+	if result.Extra["slack"].(map[string]any)["channel"] != "#alert-other" {
+		t.Fatalf("expected slack channel to be set, got %v", result.Extra["slack"])
+	}
+	if !reflect.DeepEqual(result.Extra["observability"], map[string]any{"enabled": true}) {
+		t.Fatalf("expected observability to be created, got %v", result.Extra["observability"])
+	}
+
+	_, err = current.SetFields(map[string]any{"sla.value": 1})
+	if err == nil {
+		t.Fatalf("expected error setting a field below a scalar")
+	}
+}
+
+func TestOverrideUnset(t *testing.T) {
+	current, err := GetInstallations([]byte(clusterYAML))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	current.Aws.HostCluster.GuardDuty = true
+	current.AdditionalProviders = []string{"capz"}
+
+	input := []byte("pipeline: stable\nadditionalProviders: null\naws:\n  hostCluster:\n    guardDuty: false\nslack: null\n")
+	override, err := GetInstallations(input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	unset, err := key.GetUnsetFields(input, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result, err := current.Override(override).Unset(unset)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Pipeline != "stable" {
+		t.Fatalf("expected pipeline stable, got %s", result.Pipeline)
+	}
+	if result.Aws.HostCluster.GuardDuty {
+		t.Fatalf("expected guard duty to be disabled")
+	}
+	if len(result.AdditionalProviders) != 0 {
+		t.Fatalf("expected additional providers to be removed, got %v", result.AdditionalProviders)
+	}
+	if _, ok := result.Extra["slack"]; ok {
+		t.Fatalf("expected slack to be removed, got %v", result.Extra["slack"])
+	}
+	if result.Aws.HostCluster.Account != "123456789012" || result.Customer != "giantswarm" {
+		t.Fatalf("expected fields missing in the input to be kept, got %v", result)
+	}
+}
@@ -0,0 +1,273 @@
+package installations
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"gopkg.in/yaml.v3"
+)
+
+// schemaURL is the location the schema is compiled at. References to other files are not resolved.
+const schemaURL = "file:///schema.json"
+
+// Schema is the schema.json of the installations repository.
+// Validation uses the complete JSON schema, the fields describe the subset needed to look up the type of fields.
+type Schema struct {
+	Ref         string             `json:"$ref,omitempty"`
+	Type        any                `json:"type,omitempty"`
+	Properties  map[string]*Schema `json:"properties,omitempty"`
+	Items       *Schema            `json:"items,omitempty"`
+	Description string             `json:"description,omitempty"`
+	Defs        map[string]*Schema `json:"$defs,omitempty"`
+	Definitions map[string]*Schema `json:"definitions,omitempty"`
+
+	compiled *jsonschema.Schema
+}
+
+func GetSchema(data []byte) (*Schema, error) {
+	log.Debug().Msg("getting installations schema from data")
+	var s Schema
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal schema.\n%w", err)
+	}
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal schema.\n%w", err)
+	}
+	compiler := jsonschema.NewCompiler()
+	compiler.AssertFormat()
+	if err := compiler.AddResource(schemaURL, doc); err != nil {
+		return nil, fmt.Errorf("failed to add schema.\n%v\n%w", err, ErrInvalidSchema)
+	}
+	s.compiled, err = compiler.Compile(schemaURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile schema.\n%v\n%w", err, ErrInvalidSchema)
+	}
+	return &s, nil
+}
+
+// Validate checks the installations object against the schema and reports all violations.
+func (s *Schema) Validate(i *Installations) error {
+	if s.compiled == nil {
+		return nil
+	}
+	data, err := GetData(i)
+	if err != nil {
+		return err
+	}
+	var values any
+	if err := yaml.Unmarshal(data, &values); err != nil {
+		return fmt.Errorf("failed to unmarshal installations object.\n%w", err)
+	}
+	// the validator expects JSON values
+	jsonData, err := json.Marshal(values)
+	if err != nil {
+		return fmt.Errorf("failed to marshal installations object.\n%w", err)
+	}
+	instance, err := jsonschema.UnmarshalJSON(bytes.NewReader(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal installations object.\n%w", err)
+	}
+	err = s.compiled.Validate(instance)
+	if err == nil {
+		return nil
+	}
+	var validationError *jsonschema.ValidationError
+	if !errors.As(err, &validationError) {
+		return fmt.Errorf("failed to validate installations object.\n%w", err)
+	}
+	violations := getViolations(validationError)
+	sort.Strings(violations)
+	return fmt.Errorf("%s\n%w", strings.Join(violations, "\n"), ErrSchemaValidation)
+}
+
+// getViolations returns the innermost errors of the validation as path: message.
+func getViolations(e *jsonschema.ValidationError) []string {
+	if len(e.Causes) > 0 {
+		var violations []string
+		for _, cause := range e.Causes {
+			violations = append(violations, getViolations(cause)...)
+		}
+		return violations
+	}
+	return []string{fmt.Sprintf("%s: %s", getPath(e.InstanceLocation), e.ErrorKind.LocalizedString(printer))}
+}
+
+var printer = message.NewPrinter(language.English)
+
+// getPath returns the YAML path of an instance location, e.g. zones[1].
+func getPath(location []string) string {
+	var path string
+	for _, name := range location {
+		if _, err := strconv.Atoi(name); err == nil {
+			path = fmt.Sprintf("%s[%s]", path, name)
+		} else if path == "" {
+			path = name
+		} else {
+			path = fmt.Sprintf("%s.%s", path, name)
+		}
+	}
+	if path == "" {
+		return "."
+	}
+	return path
+}
+
+// Lookup returns the schema of the field at the YAML path or nil if the schema does not describe it.
+func (s *Schema) Lookup(path string) (*Schema, error) {
+	current, err := s.resolve(s)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range strings.Split(path, ".") {
+		next, ok := current.Properties[name]
+		if !ok {
+			return nil, nil
+		}
+		current, err = s.resolve(next)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return current, nil
+}
+
+// ParseValue converts the value of the field at the YAML path to the type required by the schema.
+// Values of fields the schema does not describe are parsed as YAML.
+func (s *Schema) ParseValue(path string, value string) (any, error) {
+	field, err := s.Lookup(path)
+	if err != nil {
+		return nil, err
+	}
+	if field == nil {
+		return parseYAMLValue(value)
+	}
+	return s.parseValue(field, value)
+}
+
+func (s *Schema) parseValue(field *Schema, value string) (any, error) {
+	types := getTypes(field.Type)
+	if len(types) == 0 {
+		return parseYAMLValue(value)
+	}
+	var err error
+	for _, t := range types {
+		var parsed any
+		switch t {
+		case "string":
+			return value, nil
+		case "integer":
+			parsed, err = strconv.Atoi(value)
+		case "number":
+			parsed, err = strconv.ParseFloat(value, 64)
+		case "boolean":
+			parsed, err = strconv.ParseBool(value)
+		case "null":
+			err = nil
+			if value != "null" && value != "" {
+				err = fmt.Errorf("%s is not null", value)
+			}
+		case "array":
+			parsed, err = s.parseArray(field, value)
+		case "object":
+			parsed, err = parseYAMLValue(value)
+			if _, ok := parsed.(map[string]any); err == nil && !ok {
+				err = fmt.Errorf("%s is not an object", value)
+			}
+		default:
+			err = fmt.Errorf("unknown type %s", t)
+		}
+		if err == nil {
+			return parsed, nil
+		}
+	}
+	return nil, fmt.Errorf("value %s does not match type %v.\n%v\n%w", value, types, err, ErrInvalidField)
+}
+
+// parseArray parses comma separated items or a YAML list.
+func (s *Schema) parseArray(field *Schema, value string) (any, error) {
+	if strings.HasPrefix(value, "[") {
+		return parseYAMLValue(value)
+	}
+	items := []any{}
+	if value == "" {
+		return items, nil
+	}
+	itemSchema := &Schema{}
+	if field.Items != nil {
+		resolved, err := s.resolve(field.Items)
+		if err != nil {
+			return nil, err
+		}
+		itemSchema = resolved
+	}
+	for _, item := range strings.Split(value, ",") {
+		parsed, err := s.parseValue(itemSchema, strings.TrimSpace(item))
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, parsed)
+	}
+	return items, nil
+}
+
+func parseYAMLValue(value string) (any, error) {
+	var parsed any
+	if err := yaml.Unmarshal([]byte(value), &parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse value %s.\n%w", value, ErrInvalidField)
+	}
+	return parsed, nil
+}
+
+// resolve follows local references like #/$defs/name.
+func (s *Schema) resolve(field *Schema) (*Schema, error) {
+	if field == nil {
+		return &Schema{}, nil
+	}
+	for depth := 0; field.Ref != ""; depth++ {
+		if depth > 32 {
+			return nil, fmt.Errorf("too many nested references %s.\n%w", field.Ref, ErrInvalidSchema)
+		}
+		var defs map[string]*Schema
+		var name string
+		switch {
+		case strings.HasPrefix(field.Ref, "#/$defs/"):
+			defs, name = s.Defs, strings.TrimPrefix(field.Ref, "#/$defs/")
+		case strings.HasPrefix(field.Ref, "#/definitions/"):
+			defs, name = s.Definitions, strings.TrimPrefix(field.Ref, "#/definitions/")
+		default:
+			return nil, fmt.Errorf("unsupported reference %s.\n%w", field.Ref, ErrInvalidSchema)
+		}
+		resolved, ok := defs[name]
+		if !ok {
+			return nil, fmt.Errorf("reference %s not found.\n%w", field.Ref, ErrInvalidSchema)
+		}
+		field = resolved
+	}
+	return field, nil
+}
+
+func getTypes(t any) []string {
+	switch v := t.(type) {
+	case string:
+		return []string{v}
+	case []any:
+		var types []string
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				types = append(types, s)
+			}
+		}
+		return types
+	}
+	return nil
+}
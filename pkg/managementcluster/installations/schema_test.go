@@ -0,0 +1,240 @@
+package installations
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const schemaJSON = `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "type": "object",
+  "required": ["base", "codename", "provider"],
+  "additionalProperties": false,
+  "properties": {
+    "base": {"type": "string", "pattern": "^[a-z0-9.-]+$"},
+    "codename": {"type": "string"},
+    "customer": {"type": "string", "minLength": 1},
+    "cmc_repository": {"type": "string"},
+    "ccr_repository": {"type": "string"},
+    "accountEngineer": {"type": "string"},
+    "pipeline": {"type": "string", "enum": ["testing", "stable"]},
+    "provider": {"type": "string"},
+    "aws": {"$ref": "#/$defs/aws"},
+    "sla": {"type": "number"},
+    "decommissionDate": {"type": "string", "format": "date"},
+    "replicas": {"type": "integer", "enum": [1, 3]},
+    "zones": {"type": "array", "items": {"type": "string"}},
+    "slack": {
+      "type": "object",
+      "properties": {
+        "channel": {"type": "string"},
+        "opsgenie": {"type": ["boolean", "null"]}
+      },
+      "if": {"required": ["channel"]},
+      "then": {"properties": {"channel": {"pattern": "^#"}}}
+    }
+  },
+  "allOf": [
+    {"properties": {"ccr_repository": {"const": "giantswarm-customer-configs"}}}
+  ],
+  "$defs": {
+    "aws": {
+      "type": "object",
+      "properties": {
+        "region": {"type": "string"},
+        "hostCluster": {
+          "type": "object",
+          "additionalProperties": {"type": "string"},
+          "properties": {
+            "guardDuty": {"type": "boolean"}
+          }
+        }
+      }
+    }
+  }
+}`
+
+func TestGetSchema(t *testing.T) {
+	testCases := []struct {
+		name        string
+		schema      string
+		expectError error
+	}{
+		{
+			name:   "valid",
+			schema: schemaJSON,
+		},
+		{
+			name:        "unknown type",
+			schema:      `{"type": "text"}`,
+			expectError: ErrInvalidSchema,
+		},
+		{
+			name:        "invalid pattern",
+			schema:      `{"properties": {"base": {"pattern": "("}}}`,
+			expectError: ErrInvalidSchema,
+		},
+		{
+			name:        "remote reference",
+			schema:      `{"$ref": "https://example.com/schema.json"}`,
+			expectError: ErrInvalidSchema,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := GetSchema([]byte(tc.schema))
+			if tc.expectError == nil && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tc.expectError != nil && !errors.Is(err, tc.expectError) {
+				t.Fatalf("expected %v but got %v", tc.expectError, err)
+			}
+		})
+	}
+}
+
+func TestSchemaValidate(t *testing.T) {
+	testCases := []struct {
+		name               string
+		fields             map[string]any
+		expectedViolations []string
+	}{
+		{
+			name: "valid",
+		},
+		{
+			name: "valid extra fields",
+			fields: map[string]any{
+				"replicas":      3,
+				"zones":         []any{"a", "b"},
+				"slack.channel": "#alert-test",
+			},
+		},
+		{
+			name: "invalid enum",
+			fields: map[string]any{
+				"pipeline": "unknown",
+				"replicas": 2,
+			},
+			expectedViolations: []string{
+				"pipeline: value must be one of 'testing', 'stable'",
+				"replicas: value must be one of 1, 3",
+			},
+		},
+		{
+			name: "invalid types",
+			fields: map[string]any{
+				"sla":                   "high",
+				"zones":                 []any{"a", 1},
+				"aws.hostCluster.extra": true,
+			},
+			expectedViolations: []string{
+				"aws.hostCluster.extra: got boolean, want string",
+				"sla: got string, want number",
+				"zones[1]: got number, want string",
+			},
+		},
+		{
+			name: "invalid pattern and unknown field",
+			fields: map[string]any{
+				"base":    "Test_Base",
+				"unknown": "value",
+			},
+			expectedViolations: []string{
+				"base: 'Test_Base' does not match pattern '^[a-z0-9.-]+$'",
+				".: additional properties 'unknown' not allowed",
+			},
+		},
+		{
+			name: "keywords beyond types and enums",
+			fields: map[string]any{
+				"customer":         "",
+				"decommissionDate": "tomorrow",
+				"ccr_repository":   "ccr",
+				"slack.channel":    "alerts",
+			},
+			expectedViolations: []string{
+				"customer: minLength: got 0, want 1",
+				"decommissionDate: 'tomorrow' is not valid date",
+				"slack.channel: 'alerts' does not match pattern '^#'",
+				"ccr_repository: value must be 'giantswarm-customer-configs'",
+			},
+		},
+	}
+	schema, err := GetSchema([]byte(schemaJSON))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			i, err := GetInstallations([]byte(clusterYAML))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			i.Extra = nil
+			i.Aws.Extra = nil
+			i.Aws.HostCluster.Extra = nil
+			i, err = i.SetFields(tc.fields)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			err = schema.Validate(i)
+			if len(tc.expectedViolations) == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrSchemaValidation) {
+				t.Fatalf("expected schema validation error, got %v", err)
+			}
+			for _, violation := range tc.expectedViolations {
+				if !strings.Contains(err.Error(), violation) {
+					t.Fatalf("expected violation %q in %v", violation, err)
+				}
+			}
+		})
+	}
+}
+
+func TestSchemaParseValue(t *testing.T) {
+	testCases := []struct {
+		name        string
+		path        string
+		value       string
+		expected    any
+		expectError bool
+	}{
+		{name: "string", path: "pipeline", value: "true", expected: "true"},
+		{name: "number", path: "sla", value: "99.9", expected: 99.9},
+		{name: "integer", path: "replicas", value: "3", expected: 3},
+		{name: "invalid integer", path: "replicas", value: "three", expectError: true},
+		{name: "boolean from reference", path: "aws.hostCluster.guardDuty", value: "true", expected: true},
+		{name: "nullable boolean", path: "slack.opsgenie", value: "null", expected: nil},
+		{name: "array", path: "zones", value: "a, b", expected: []any{"a", "b"}},
+		{name: "unknown field", path: "observability.enabled", value: "false", expected: false},
+	}
+	schema, err := GetSchema([]byte(schemaJSON))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			value, err := schema.ParseValue(tc.path, tc.value)
+			if tc.expectError {
+				if !errors.Is(err, ErrInvalidField) {
+					t.Fatalf("expected invalid field error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(value, tc.expected) {
+				t.Fatalf("expected %#v, got %#v", tc.expected, value)
+			}
+		})
+	}
+}
@@ -15,6 +15,8 @@ import (
 type ManagementCluster struct {
 	Installations installations.Installations `yaml:"installations,omitempty"`
 	CMC           cmc.CMC                     `yaml:"cmc,omitempty"`
+	// SecretFolder is read on push to set secrets which are not part of the file.
+	SecretFolder string `yaml:"secretFolder,omitempty"`
 }
 
 func (mc *ManagementCluster) Print() error {
@@ -31,6 +33,17 @@ func GetData(mc *ManagementCluster) ([]byte, error) {
 	return key.GetData(mc)
 }
 
+func (mc *ManagementCluster) WriteFile(file string) error {
+	data, err := GetData(mc)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(file, data, 0600); err != nil {
+		return fmt.Errorf("failed to write %s.\n%w", file, err)
+	}
+	return nil
+}
+
 func GetManagementCluster(data []byte) (*ManagementCluster, error) {
 	log.Debug().Msg("getting management cluster object from data")
 	managementcluster := ManagementCluster{}
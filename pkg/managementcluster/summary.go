@@ -0,0 +1,28 @@
+package managementcluster
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/giantswarm/mcli/pkg/github"
+)
+
+// Summary describes the changes made by a push.
+type Summary struct {
+	Cluster      string               `json:"cluster"`
+	Repositories []*RepositorySummary `json:"repositories"`
+}
+
+type RepositorySummary struct {
+	github.Result
+	ChangedFields []string `json:"changedFields,omitempty"`
+}
+
+func (s *Summary) Print() error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal summary.\n%w", err)
+	}
+	fmt.Println(string(data))
+	return nil
+}
@@ -0,0 +1,9 @@
+package policy
+
+import (
+	"errors"
+)
+
+var ErrInvalidPolicy = errors.New("invalid policy")
+
+var ErrDenied = errors.New("denied by policy")
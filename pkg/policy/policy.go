@@ -0,0 +1,365 @@
+// Package policy evaluates organisational rules written in CEL against the management cluster model.
+package policy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/ext"
+	"github.com/rs/zerolog/log"
+	"gopkg.in/yaml.v3"
+
+	"github.com/giantswarm/mcli/pkg/github"
+	"github.com/giantswarm/mcli/pkg/key"
+	"github.com/giantswarm/mcli/pkg/managementcluster"
+	"github.com/giantswarm/mcli/pkg/managementcluster/cmc"
+	"github.com/giantswarm/mcli/pkg/managementcluster/installations"
+)
+
+const (
+	SeverityWarn = "warn"
+	SeverityDeny = "deny"
+)
+
+// CMCPrefix marks policy sources which are read from the main branch of the CMC repository.
+const CMCPrefix = "cmc:"
+
+// Policy is a bundle of rules, e.g. all files of a policy directory.
+type Policy struct {
+	Rules []Rule `yaml:"rules"`
+}
+
+// Rule requires the CEL expression Require to be true for management clusters for which When is true.
+// Expressions access the management cluster model as cmc and installations, e.g. cmc.mcProxy.enabled.
+type Rule struct {
+	Name    string `yaml:"name"`
+	Message string `yaml:"message,omitempty"`
+	// Severity is warn or deny. Defaults to deny.
+	Severity string `yaml:"severity,omitempty"`
+	// When selects the management clusters the rule applies to. Defaults to all.
+	When    string `yaml:"when,omitempty"`
+	Require string `yaml:"require"`
+
+	when         cel.Program
+	require      cel.Program
+	repositories []string
+}
+
+type Violation struct {
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+// Config points at the policy sources. Sources are local files or directories,
+// or files or directories of the CMC repository prefixed with cmc:, e.g. cmc:policies.
+type Config struct {
+	Sources       []string
+	Github        *github.Github
+	CMCRepository string
+}
+
+// Load reads and validates the rules of all sources.
+func (c *Config) Load(ctx context.Context) (*Policy, error) {
+	policy := &Policy{}
+	for _, source := range c.Sources {
+		var files map[string]string
+		var err error
+		if strings.HasPrefix(source, CMCPrefix) {
+			files, err = c.readCMC(ctx, strings.TrimPrefix(source, CMCPrefix))
+		} else {
+			files, err = readLocal(source)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read policy %s.\n%w", source, err)
+		}
+		var names []string
+		for name := range files {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			p, err := Parse([]byte(files[name]))
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse policy %s.\n%w", name, err)
+			}
+			policy.Rules = append(policy.Rules, p.Rules...)
+		}
+	}
+	log.Debug().Msgf("loaded %d policy rules", len(policy.Rules))
+	return policy, nil
+}
+
+func (c *Config) readCMC(ctx context.Context, p string) (map[string]string, error) {
+	repository := github.Repository{
+		Github:       c.Github,
+		Name:         c.CMCRepository,
+		Organization: key.OrganizationGiantSwarm,
+		Branch:       key.CMCMainBranch,
+	}
+	if isYAML(p) {
+		file, err := repository.GetFile(ctx, p)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]string{p: file}, nil
+	}
+	directory, err := repository.GetDirectory(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+	files := map[string]string{}
+	for name, content := range directory {
+		if isYAML(name) {
+			files[name] = content
+		}
+	}
+	return files, nil
+}
+
+func readLocal(p string) (map[string]string, error) {
+	info, err := os.Stat(p)
+	if err != nil {
+		return nil, err
+	}
+	names := []string{p}
+	if info.IsDir() {
+		entries, err := os.ReadDir(p)
+		if err != nil {
+			return nil, err
+		}
+		names = nil
+		for _, entry := range entries {
+			if !entry.IsDir() && isYAML(entry.Name()) {
+				names = append(names, filepath.Join(p, entry.Name()))
+			}
+		}
+	}
+	files := map[string]string{}
+	for _, name := range names {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+		files[name] = string(data)
+	}
+	return files, nil
+}
+
+// Parse reads a policy file and validates its rules.
+func Parse(data []byte) (*Policy, error) {
+	policy := &Policy{}
+	decoder := yaml.NewDecoder(strings.NewReader(string(data)))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(policy); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w\n%w", err, ErrInvalidPolicy)
+	}
+	for i := range policy.Rules {
+		if err := policy.Rules[i].validate(); err != nil {
+			return nil, err
+		}
+	}
+	return policy, nil
+}
+
+func (r *Rule) validate() error {
+	if r.Name == "" {
+		return fmt.Errorf("rule without name.\n%w", ErrInvalidPolicy)
+	}
+	if r.Severity == "" {
+		r.Severity = SeverityDeny
+	}
+	if r.Severity != SeverityWarn && r.Severity != SeverityDeny {
+		return fmt.Errorf("rule %s has invalid severity %s. Valid values: %s, %s.\n%w", r.Name, r.Severity, SeverityWarn, SeverityDeny, ErrInvalidPolicy)
+	}
+	if r.Require == "" {
+		return fmt.Errorf("rule %s has no requirement.\n%w", r.Name, ErrInvalidPolicy)
+	}
+	return r.compile()
+}
+
+// compile checks the expressions against the management cluster model, so unknown fields are rejected on load.
+func (r *Rule) compile() error {
+	env, err := getEnv()
+	if err != nil {
+		return err
+	}
+	r.repositories = nil
+	r.when = nil
+	if r.When != "" {
+		r.when, err = r.compileExpression(env, r.When)
+		if err != nil {
+			return err
+		}
+	}
+	r.require, err = r.compileExpression(env, r.Require)
+	return err
+}
+
+func (r *Rule) compileExpression(env *cel.Env, expression string) (cel.Program, error) {
+	ast, issues := env.Compile(expression)
+	if issues.Err() != nil {
+		return nil, fmt.Errorf("rule %s has invalid expression %s.\n%w\n%w", r.Name, expression, issues.Err(), ErrInvalidPolicy)
+	}
+	if ast.OutputType() != cel.BoolType {
+		return nil, fmt.Errorf("rule %s has expression %s of type %s instead of bool.\n%w", r.Name, expression, ast.OutputType(), ErrInvalidPolicy)
+	}
+	for _, reference := range ast.NativeRep().ReferenceMap() {
+		if (reference.Name == key.RepositoryCMC || reference.Name == key.RepositoryInstallations) && !slices.Contains(r.repositories, reference.Name) {
+			r.repositories = append(r.repositories, reference.Name)
+		}
+	}
+	program, err := env.Program(ast)
+	if err != nil {
+		return nil, fmt.Errorf("rule %s has invalid expression %s.\n%w\n%w", r.Name, expression, err, ErrInvalidPolicy)
+	}
+	return program, nil
+}
+
+// getEnv declares the parts of the management cluster model with their YAML field names.
+var getEnv = sync.OnceValues(func() (*cel.Env, error) {
+	env, err := cel.NewEnv(
+		ext.NativeTypes(reflect.TypeOf(cmc.CMC{}), reflect.TypeOf(installations.Installations{}), ext.ParseStructTag("yaml")),
+		ext.Strings(),
+		cel.Variable(key.RepositoryCMC, cel.ObjectType("cmc.CMC")),
+		cel.Variable(key.RepositoryInstallations, cel.ObjectType("installations.Installations")),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create policy environment.\n%w", err)
+	}
+	return env, nil
+})
+
+// Without returns the rules which only check fields of repositories which are not skipped.
+func (p *Policy) Without(skip []string) *Policy {
+	result := &Policy{}
+	for _, rule := range p.Rules {
+		if rule.require == nil {
+			if err := rule.compile(); err != nil {
+				log.Debug().Msgf("failed to compile policy rule %s.\n%s", rule.Name, err)
+			}
+		}
+		skipped := false
+		for _, repository := range rule.repositories {
+			if key.Skip(repository, skip) {
+				skipped = true
+			}
+		}
+		if skipped {
+			log.Debug().Msgf("skipping policy rule %s", rule.Name)
+			continue
+		}
+		result.Rules = append(result.Rules, rule)
+	}
+	return result
+}
+
+// Evaluate returns the violations of the rules by the management cluster.
+// Rules which fail to evaluate are reported as violations with the error as message.
+func (p *Policy) Evaluate(mc *managementcluster.ManagementCluster) []Violation {
+	var violations []Violation
+	variables := map[string]any{
+		key.RepositoryCMC:           mc.CMC,
+		key.RepositoryInstallations: mc.Installations,
+	}
+	for _, rule := range p.Rules {
+		message, ok := rule.evaluate(variables)
+		if ok {
+			continue
+		}
+		violations = append(violations, Violation{
+			Rule:     rule.Name,
+			Severity: rule.Severity,
+			Message:  message,
+		})
+	}
+	return violations
+}
+
+// evaluate returns false and the message if the rule is violated.
+func (r Rule) evaluate(variables map[string]any) (string, bool) {
+	if r.require == nil {
+		if err := r.compile(); err != nil {
+			return err.Error(), false
+		}
+	}
+	if r.when != nil {
+		applies, err := evaluate(r.when, variables)
+		if err != nil {
+			return fmt.Sprintf("failed to evaluate %s: %s", r.When, err), false
+		}
+		if !applies {
+			return "", true
+		}
+	}
+	holds, err := evaluate(r.require, variables)
+	if err != nil {
+		return fmt.Sprintf("failed to evaluate %s: %s", r.Require, err), false
+	}
+	if holds {
+		return "", true
+	}
+	if r.Message != "" {
+		return r.Message, false
+	}
+	return fmt.Sprintf("%s does not hold", r.Require), false
+}
+
+func evaluate(program cel.Program, variables map[string]any) (bool, error) {
+	out, _, err := program.Eval(variables)
+	if err != nil {
+		return false, err
+	}
+	result, ok := out.Value().(bool)
+	if !ok {
+		return false, fmt.Errorf("result %v is no bool", out.Value())
+	}
+	return result, nil
+}
+
+// IsDenied reports whether any violation has severity deny.
+func IsDenied(violations []Violation) bool {
+	for _, v := range violations {
+		if v.Severity == SeverityDeny {
+			return true
+		}
+	}
+	return false
+}
+
+func Print(w io.Writer, violations []Violation) {
+	for _, v := range violations {
+		fmt.Fprintf(w, "policy %s: %s: %s\n", v.Severity, v.Rule, v.Message)
+	}
+}
+
+// Check evaluates the policy, prints the violations and returns an error if any of them denies the management cluster.
+func (p *Policy) Check(w io.Writer, mc *managementcluster.ManagementCluster) error {
+	violations := p.Evaluate(mc)
+	Print(w, violations)
+	if IsDenied(violations) {
+		return fmt.Errorf("management cluster %s violates policy.\n%w", getCluster(mc), ErrDenied)
+	}
+	return nil
+}
+
+func getCluster(mc *managementcluster.ManagementCluster) string {
+	if mc.CMC.Cluster != "" {
+		return mc.CMC.Cluster
+	}
+	return mc.Installations.Codename
+}
+
+func isYAML(file string) bool {
+	return strings.HasSuffix(file, ".yaml") || strings.HasSuffix(file, ".yml")
+}
@@ -0,0 +1,213 @@
+package policy
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/giantswarm/mcli/pkg/github/githubtest"
+	"github.com/giantswarm/mcli/pkg/key"
+	"github.com/giantswarm/mcli/pkg/managementcluster"
+	"github.com/giantswarm/mcli/pkg/managementcluster/cmc"
+	"github.com/giantswarm/mcli/pkg/managementcluster/installations"
+)
+
+const testPolicy = `rules:
+- name: stable-prevent-deletion
+  message: stable management clusters must prevent the deletion of their apps
+  when: installations.pipeline == "stable"
+  require: cmc.mcAppsPreventDeletion
+- name: private-ca
+  when: cmc.privateMC
+  require: cmc.privateCA.enabled
+- name: proxy-github
+  severity: warn
+  when: cmc.mcProxy.enabled
+  require: cmc.mcProxy.noProxy.exists(a, a.endsWith("github.com"))
+- name: approved-registry
+  when: cmc.registryDomain != ""
+  require: cmc.registryDomain == "gsoci.azurecr.io" || cmc.registryDomain.endsWith(".gigantic.io")
+`
+
+func TestEvaluate(t *testing.T) {
+	testCases := []struct {
+		name string
+		mc   *managementcluster.ManagementCluster
+
+		expected []Violation
+	}{
+		{
+			name: "case 0: compliant",
+			mc: &managementcluster.ManagementCluster{
+				Installations: installations.Installations{Pipeline: "stable"},
+				CMC: cmc.CMC{
+					MCAppsPreventDeletion: true,
+					PrivateMC:             true,
+					PrivateCA:             cmc.PrivateCA{Enabled: true},
+					MCProxy:               cmc.MCProxy{Enabled: true, NoProxy: []string{"api.github.com"}},
+					RegistryDomain:        "gsoci.azurecr.io",
+				},
+			},
+		},
+		{
+			name: "case 1: rules without matching conditions are skipped",
+			mc: &managementcluster.ManagementCluster{
+				Installations: installations.Installations{Pipeline: "testing"},
+			},
+		},
+		{
+			name: "case 2: evaluation errors are violations",
+			mc: &managementcluster.ManagementCluster{
+				Installations: installations.Installations{Pipeline: "alpha"},
+			},
+
+			expected: []Violation{
+				{Rule: "credential-expiry", Severity: SeverityDeny, Message: `failed to evaluate cmc.credentialExpiry["azureClientSecret"] > "2025": no such key: azureClientSecret`},
+			},
+		},
+		{
+			name: "case 3: violations",
+			mc: &managementcluster.ManagementCluster{
+				Installations: installations.Installations{Pipeline: "stable"},
+				CMC: cmc.CMC{
+					PrivateMC:      true,
+					MCProxy:        cmc.MCProxy{Enabled: true},
+					RegistryDomain: "docker.io",
+				},
+			},
+
+			expected: []Violation{
+				{Rule: "stable-prevent-deletion", Severity: SeverityDeny, Message: "stable management clusters must prevent the deletion of their apps"},
+				{Rule: "private-ca", Severity: SeverityDeny, Message: "cmc.privateCA.enabled does not hold"},
+				{Rule: "proxy-github", Severity: SeverityWarn, Message: `cmc.mcProxy.noProxy.exists(a, a.endsWith("github.com")) does not hold`},
+				{Rule: "approved-registry", Severity: SeverityDeny, Message: `cmc.registryDomain == "gsoci.azurecr.io" || cmc.registryDomain.endsWith(".gigantic.io") does not hold`},
+			},
+		},
+	}
+
+	policy, err := Parse([]byte(testPolicy + `- name: credential-expiry
+  when: installations.pipeline == "alpha"
+  require: cmc.credentialExpiry["azureClientSecret"] > "2025"
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			violations := policy.Evaluate(tc.mc)
+			if !reflect.DeepEqual(violations, tc.expected) {
+				t.Fatalf("expected %v, got %v", tc.expected, violations)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		name   string
+		policy string
+
+		expectedError error
+	}{
+		{
+			name:   "case 0: valid",
+			policy: testPolicy,
+		},
+		{
+			name:   "case 1: unknown field of the model",
+			policy: "rules:\n- name: a\n  require: cmc.provider == \"capz\"\n",
+
+			expectedError: ErrInvalidPolicy,
+		},
+		{
+			name:   "case 2: invalid severity",
+			policy: "rules:\n- name: a\n  severity: error\n  require: cmc.privateMC\n",
+
+			expectedError: ErrInvalidPolicy,
+		},
+		{
+			name:   "case 3: rule without requirement",
+			policy: "rules:\n- name: a\n  when: cmc.privateMC\n",
+
+			expectedError: ErrInvalidPolicy,
+		},
+		{
+			name:   "case 4: unknown key",
+			policy: "rules:\n- name: a\n  expression: cmc.privateMC == true\n",
+
+			expectedError: ErrInvalidPolicy,
+		},
+		{
+			name:   "case 5: expression is no bool",
+			policy: "rules:\n- name: a\n  require: cmc.registryDomain\n",
+
+			expectedError: ErrInvalidPolicy,
+		},
+		{
+			name:   "case 6: syntax error",
+			policy: "rules:\n- name: a\n  require: cmc.privateMC ==\n",
+
+			expectedError: ErrInvalidPolicy,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Parse([]byte(tc.policy))
+			if !errors.Is(err, tc.expectedError) {
+				t.Fatalf("expected error %v, got %v", tc.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestWithout(t *testing.T) {
+	policy, err := Parse([]byte(testPolicy))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var names []string
+	for _, rule := range policy.Without([]string{key.RepositoryInstallations}).Rules {
+		names = append(names, rule.Name)
+	}
+	expected := []string{"private-ca", "proxy-github", "approved-registry"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Fatalf("expected %v, got %v", expected, names)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "rules.yaml"), []byte(testPolicy), 0600); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("policies"), 0600); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	server := githubtest.NewServer()
+	defer server.Close()
+	server.AddRepository(key.OrganizationGiantSwarm, "giantswarm-management-clusters", map[string]string{
+		"policies/stable.yaml": "rules:\n- name: stable\n  require: installations.pipeline == \"stable\"\n",
+	})
+
+	c := Config{
+		Sources:       []string{dir, CMCPrefix + "policies"},
+		Github:        server.Client(),
+		CMCRepository: "giantswarm-management-clusters",
+	}
+	policy, err := c.Load(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var names []string
+	for _, rule := range policy.Rules {
+		names = append(names, rule.Name)
+	}
+	expected := []string{"stable-prevent-deletion", "private-ca", "proxy-github", "approved-registry", "stable"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Fatalf("expected %v, got %v", expected, names)
+	}
+}
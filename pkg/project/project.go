@@ -0,0 +1,20 @@
+// Package project holds build information which is set via ldflags.
+package project
+
+var (
+	buildTimestamp = "n/a"
+	gitSHA         = "n/a"
+	version        = "dev"
+)
+
+func BuildTimestamp() string {
+	return buildTimestamp
+}
+
+func GitSHA() string {
+	return gitSHA
+}
+
+func Version() string {
+	return version
+}